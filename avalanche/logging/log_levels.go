@@ -5,12 +5,12 @@ import (
 )
 
 const (
-	trace = "trace"
-	debug = "debug"
-	info  = "info"
-	warn  = "warn"
-	error = "error"
-	fatal = "fatal"
+	traceLevel = "trace"
+	debugLevel = "debug"
+	infoLevel  = "info"
+	warnLevel  = "warn"
+	errorLevel = "error"
+	fatalLevel = "fatal"
 )
 
 /*
@@ -18,12 +18,12 @@ These are the strings forming a pseudo-"enum" of log levels that the Avalanche E
 	accept, with a mapping to the logrus log levels that will be used to actually set the logger's level
 */
 var acceptableLogLevels = map[string]logrus.Level{
-	trace: logrus.TraceLevel,
-	debug: logrus.DebugLevel,
-	info:  logrus.InfoLevel,
-	warn:  logrus.WarnLevel,
-	error: logrus.ErrorLevel,
-	fatal: logrus.FatalLevel,
+	traceLevel: logrus.TraceLevel,
+	debugLevel: logrus.DebugLevel,
+	infoLevel:  logrus.InfoLevel,
+	warnLevel:  logrus.WarnLevel,
+	errorLevel: logrus.ErrorLevel,
+	fatalLevel: logrus.FatalLevel,
 }
 
 /*
@@ -43,11 +43,11 @@ Gets the list of all acceptable strings representing our log levels (used for va
 */
 func GetAcceptableStrings() []string {
 	return []string{
-		trace,
-		debug,
-		info,
-		warn,
-		error,
-		fatal,
+		traceLevel,
+		debugLevel,
+		infoLevel,
+		warnLevel,
+		errorLevel,
+		fatalLevel,
 	}
 }
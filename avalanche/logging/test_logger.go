@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// Fields that tag the lines logged about a test and its nodes
+const (
+	TestNameField  = "test"
+	ServiceIDField = "service"
+	NodeIDField    = "node"
+)
+
+// Formats the test suite can log in
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+// ConfigureTestLogger configures [logger] to log the test named [testName] at [level] in [format], tagging every line
+// with the test's name. Lines are written to the logger's output and, if [logFilepath] isn't empty, without colors to
+// the file at [logFilepath] too. The returned closer closes that file.
+func ConfigureTestLogger(logger *logrus.Logger, testName string, level logrus.Level, format string, logFilepath string) (io.Closer, error) {
+	outputFormatter, fileFormatter, err := newFormatters(format)
+	if err != nil {
+		return nil, err
+	}
+	testFields := logrus.Fields{TestNameField: testName}
+	logger.SetLevel(level)
+	logger.SetFormatter(fieldsFormatter{fields: testFields, formatter: outputFormatter})
+
+	if logFilepath == "" {
+		return noopCloser{}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(logFilepath), 0755); err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to create the directory of log file %s", logFilepath)
+	}
+	logFile, err := os.OpenFile(logFilepath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to open log file %s", logFilepath)
+	}
+	logger.AddHook(fileHook{
+		writer:    logFile,
+		formatter: fieldsFormatter{fields: testFields, formatter: fileFormatter},
+	})
+	return logFile, nil
+}
+
+// ForService returns an entry of the standard logger that tags lines with the service ID of the node they're about
+func ForService(serviceID string) *logrus.Entry {
+	return logrus.WithField(ServiceIDField, serviceID)
+}
+
+// ForNode returns an entry of the standard logger that tags lines with the service ID and node ID of the node they're
+// about
+func ForNode(serviceID string, nodeID string) *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{
+		ServiceIDField: serviceID,
+		NodeIDField:    nodeID,
+	})
+}
+
+// newFormatters returns the formatters of the logger's output and of its log file for [format]
+func newFormatters(format string) (logrus.Formatter, logrus.Formatter, error) {
+	switch format {
+	case TextFormat:
+		output := &logrus.TextFormatter{
+			ForceColors:   true,
+			FullTimestamp: true,
+		}
+		file := &logrus.TextFormatter{
+			DisableColors: true,
+			FullTimestamp: true,
+		}
+		return output, file, nil
+	case JSONFormat:
+		return &logrus.JSONFormatter{}, &logrus.JSONFormatter{}, nil
+	default:
+		return nil, nil, stacktrace.NewError("Unknown log format '%s', expected one of %v", format, []string{TextFormat, JSONFormat})
+	}
+}
+
+// fieldsFormatter formats entries with [fields] added to their own fields, without modifying the entries, since logrus
+// shares the fields of an entry between the lines logged through it
+type fieldsFormatter struct {
+	fields    logrus.Fields
+	formatter logrus.Formatter
+}
+
+func (f fieldsFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data)+len(f.fields))
+	for key, value := range f.fields {
+		data[key] = value
+	}
+	for key, value := range entry.Data {
+		data[key] = value
+	}
+	tagged := *entry
+	tagged.Data = data
+	return f.formatter.Format(&tagged)
+}
+
+// fileHook writes every logged line to [writer] in its own format. Hooks fire while the logger holds its lock, so
+// writes don't interleave.
+type fileHook struct {
+	writer    io.Writer
+	formatter logrus.Formatter
+}
+
+func (hook fileHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (hook fileHook) Fire(entry *logrus.Entry) error {
+	line, err := hook.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = hook.writer.Write(line)
+	return err
+}
+
+type noopCloser struct{}
+
+func (noopCloser) Close() error {
+	return nil
+}
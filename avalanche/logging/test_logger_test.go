@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureTestLogger(t *testing.T) {
+	logsDirpath, err := ioutil.TempDir("", "test-logs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(logsDirpath)
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	logFilepath := filepath.Join(logsDirpath, "services", "rpcWorkflowTest.log")
+	logFile, err := ConfigureTestLogger(logger, "rpcWorkflowTest", logrus.InfoLevel, JSONFormat, logFilepath)
+	assert.NoError(t, err)
+	logger.WithFields(logrus.Fields{ServiceIDField: "boot-node-0", NodeIDField: "NodeID-1"}).Info("Node is healthy")
+	logger.Debug("Below the log level")
+	assert.NoError(t, logFile.Close())
+
+	logged, err := ioutil.ReadFile(logFilepath)
+	assert.NoError(t, err)
+	line := make(map[string]string)
+	assert.NoError(t, json.Unmarshal(logged, &line), "Expected exactly one JSON line")
+	assert.Equal(t, "rpcWorkflowTest", line[TestNameField])
+	assert.Equal(t, "boot-node-0", line[ServiceIDField])
+	assert.Equal(t, "NodeID-1", line[NodeIDField])
+	assert.Equal(t, "Node is healthy", line["msg"])
+
+	_, err = ConfigureTestLogger(logrus.New(), "rpcWorkflowTest", logrus.InfoLevel, "xml", "")
+	assert.Error(t, err, "Expected an error for an unknown log format")
+}
+
+func TestLevelFromString(t *testing.T) {
+	assert.Equal(t, logrus.WarnLevel, *LevelFromString("warn"))
+	assert.Nil(t, LevelFromString("verbo"), "Expected no level for a string the suite doesn't accept")
+}
@@ -2,6 +2,7 @@ package networks
 
 import (
 	"bytes"
+	"path/filepath"
 	"time"
 
 	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
@@ -12,7 +13,9 @@ import (
 
 	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
 	"github.com/ava-labs/avalanche-testing/avalanche/services/certs"
+	"github.com/ava-labs/avalanche-testing/utils/addressing"
 	"github.com/ava-labs/avalanche-testing/utils/constants"
+	avalancheConstants "github.com/ava-labs/avalanchego/utils/constants"
 
 	"github.com/palantir/stacktrace"
 )
@@ -23,6 +26,13 @@ const (
 
 	// The prefix for boot node service IDs, with an integer appended to specify each one
 	bootNodeServiceIDPrefix string = "boot-node-"
+
+	// The prefix for the configuration IDs that restore nodes from a snapshot, with the node's service ID appended
+	snapshotConfigIDPrefix string = "snapshot-config-"
+
+	// LocalNetworkID is the ID of the local test network. Test networks may also use any other network ID that doesn't
+	// belong to a public network, in which case the nodes start from the local genesis under that network ID.
+	LocalNetworkID = avalancheConstants.LocalID
 )
 
 // ========================================================================================================
@@ -37,28 +47,96 @@ const (
 type TestAvalancheNetwork struct {
 	networks.Network
 
-	svcNetwork *networks.ServiceNetwork
+	// The nodes of the network, run by Kurtosis or by another execution backend
+	nodes nodeNetwork
+
+	// The ID of the network that the nodes were started with
+	networkID uint32
+
+	// Whether the nodes were started in working directories on the test volume, which hold their profiles, IPC sockets,
+	// and logs
+	exposeNodeFiles bool
+
+	// The snapshot the network starts from, or nil if it starts from scratch
+	snapshot *networkSnapshot
+}
+
+// GetNetworkID returns the ID of the network that the nodes were started with
+func (network TestAvalancheNetwork) GetNetworkID() uint32 {
+	return network.networkID
+}
+
+// GetAddressFormatter returns a formatter for bech32 addresses on this network
+func (network TestAvalancheNetwork) GetAddressFormatter() addressing.Formatter {
+	return addressing.NewFormatter(network.networkID)
+}
+
+// GetChainRegistry builds a registry of this network's chain IDs and AVAX asset ID from the first boot node. These
+// depend on the network ID and genesis, so they must not be hardcoded.
+func (network TestAvalancheNetwork) GetChainRegistry() (*avalancheService.ChainRegistry, error) {
+	client, err := network.GetAvalancheClient(networks.ServiceID(bootNodeServiceIDPrefix + strconv.Itoa(0)))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get the client of the first boot node")
+	}
+	registry, err := avalancheService.NewChainRegistry(client)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to build the chain registry of the network")
+	}
+	if registry.NetworkID() != network.networkID {
+		return nil, stacktrace.NewError("Node reported network ID %d, but the network was started with network ID %d", registry.NetworkID(), network.networkID)
+	}
+	return registry, nil
 }
 
 // GetAvalancheClient returns the API Client for the node with the given service ID
 func (network TestAvalancheNetwork) GetAvalancheClient(serviceID networks.ServiceID) (*avalancheService.Client, error) {
-	node, err := network.svcNetwork.GetService(serviceID)
+	service, err := network.nodes.getService(serviceID)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "An error occurred retrieving service node with ID %v", serviceID)
 	}
-	service := node.Service.(avalancheService.AvalancheService)
 	jsonRPCSocket := service.GetJSONRPCSocket()
-	return avalancheService.NewClient(jsonRPCSocket.GetIPAddr(), jsonRPCSocket.GetPort(), constants.DefaultRequestTimeout)
+	return avalancheService.NewClient(jsonRPCSocket.GetIPAddr(), jsonRPCSocket.GetPort(), constants.DefaultRequestTimeout), nil
+}
+
+// GetNodeWorkingDirpath returns the path, as seen by the test suite, of the working directory of the node with the
+// given service ID. This is where the node's admin API writes profiles and where its IPC sockets are created, and it's
+// only available if the network was loaded with node files exposed.
+func (network TestAvalancheNetwork) GetNodeWorkingDirpath(serviceID networks.ServiceID) (string, error) {
+	if !network.exposeNodeFiles {
+		return "", stacktrace.NewError("Node working directories are only available on networks loaded with node files exposed")
+	}
+	return network.nodes.getNodeWorkingDirpath(serviceID)
+}
+
+// GetNodeLogsDirpath returns the path, as seen by the test suite, of the directory the node with the given service ID
+// writes its log files to. It's only available if the network was loaded with node files exposed.
+func (network TestAvalancheNetwork) GetNodeLogsDirpath(serviceID networks.ServiceID) (string, error) {
+	if !network.exposeNodeFiles {
+		return "", stacktrace.NewError("Node log directories are only available on networks loaded with node files exposed")
+	}
+	workingDirpath, err := network.nodes.getNodeWorkingDirpath(serviceID)
+	if err != nil {
+		return "", err
+	}
+	return avalancheService.WorkingDirLogsDirpath(workingDirpath), nil
+}
+
+// GetAvalancheClients returns the API Clients for every node in [serviceIDs], keyed by service ID
+func (network TestAvalancheNetwork) GetAvalancheClients(serviceIDs map[networks.ServiceID]bool) (map[networks.ServiceID]*avalancheService.Client, error) {
+	clients := make(map[networks.ServiceID]*avalancheService.Client, len(serviceIDs))
+	for serviceID := range serviceIDs {
+		client, err := network.GetAvalancheClient(serviceID)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred getting the Avalanche client for service with ID %v", serviceID)
+		}
+		clients[serviceID] = client
+	}
+	return clients, nil
 }
 
 // GetAllBootServiceIDs returns the service IDs of all the boot nodes in the network
 func (network TestAvalancheNetwork) GetAllBootServiceIDs() map[networks.ServiceID]bool {
-	result := make(map[networks.ServiceID]bool)
-	for i := 0; i < len(DefaultLocalNetGenesisConfig.Stakers); i++ {
-		bootID := networks.ServiceID(bootNodeServiceIDPrefix + strconv.Itoa(i))
-		result[bootID] = true
-	}
-	return result
+	return bootServiceIDs()
 }
 
 // AddService adds a service to the test Avalanche network, using the given configuration
@@ -68,7 +146,14 @@ func (network TestAvalancheNetwork) GetAllBootServiceIDs() map[networks.ServiceI
 // Returns:
 // 		An availability checker that will return true when teh newly-added service is available
 func (network TestAvalancheNetwork) AddService(configurationID networks.ConfigurationID, serviceID networks.ServiceID) (*services.ServiceAvailabilityChecker, error) {
-	availabilityChecker, err := network.svcNetwork.AddService(configurationID, serviceID, network.GetAllBootServiceIDs())
+	// Boot nodes that have been removed, for example to restart them, can't be bootstrapped from
+	dependencies := make(map[networks.ServiceID]bool)
+	for bootServiceID := range network.GetAllBootServiceIDs() {
+		if _, err := network.nodes.getService(bootServiceID); err == nil {
+			dependencies[bootServiceID] = true
+		}
+	}
+	availabilityChecker, err := network.nodes.addService(configurationID, serviceID, dependencies)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "An error occurred adding service with service ID %v, configuration ID %v", serviceID, configurationID)
 	}
@@ -79,12 +164,24 @@ func (network TestAvalancheNetwork) AddService(configurationID networks.Configur
 // Args:
 // 	serviceID: The ID of the service to remove from the network
 func (network TestAvalancheNetwork) RemoveService(serviceID networks.ServiceID) error {
-	if err := network.svcNetwork.RemoveService(serviceID, containerStopTimeoutSeconds); err != nil {
+	if err := network.nodes.removeService(serviceID); err != nil {
 		return stacktrace.Propagate(err, "An error occurred removing service with ID %v", serviceID)
 	}
 	return nil
 }
 
+// GetBootNodeConfigurationIDs returns the ID of the configuration each boot node is launched from, keyed by service
+// ID, so that a boot node can be added back with its staking key after being removed. A boot node added back from its
+// configuration starts with an empty database, even on a network restored from a snapshot.
+func (network TestAvalancheNetwork) GetBootNodeConfigurationIDs() map[networks.ServiceID]networks.ConfigurationID {
+	result := make(map[networks.ServiceID]networks.ConfigurationID)
+	for i := 0; i < len(DefaultLocalNetGenesisConfig.Stakers); i++ {
+		serviceID := networks.ServiceID(bootNodeServiceIDPrefix + strconv.Itoa(i))
+		result[serviceID] = networks.ConfigurationID(bootNodeConfigIDPrefix + strconv.Itoa(i))
+	}
+	return result
+}
+
 // ========================================================================================================
 //                                    Avalanche Service Config
 // ========================================================================================================
@@ -178,6 +275,20 @@ type TestAvalancheNetworkLoader struct {
 
 	// The initial timeout for the network
 	networkInitialTimeout time.Duration
+
+	// The ID of the network that the nodes are started with
+	networkID uint32
+
+	// Whether the nodes are started in working directories on the test volume, exposing their profiles, IPC sockets, and
+	// logs
+	exposeNodeFiles bool
+
+	// Log levels that replace the levels of every node in the network, which are left as configured where empty
+	logLevelOverrides avalancheService.NodeLogLevels
+
+	// The snapshot the network starts from, or nil to start it from scratch. It's a pointer so that the copies of the
+	// loader made while the network is loaded share whether it was restored.
+	snapshot *networkSnapshot
 }
 
 // NewTestAvalancheNetworkLoader creates a new loader to create a TestAvalancheNetwork with the specified parameters, transparently handling the creation
@@ -185,14 +296,19 @@ type TestAvalancheNetworkLoader struct {
 // NOTE: Bootstrapper nodes will be created automatically, and will show up in the ServiceAvailabilityChecker map that gets returned
 // upon initialization.
 // Args:
+// 	networkID: The ID of the network the nodes will be started with. Must not be the ID of a public network.
 // 	isStaking: Whether the network will have staking enabled
 // 	bootNodeImage: The Docker image that should be used to launch the boot nodes
 // 	bootNodeLogLevel: The log level that the boot nodes will launch with
 // 	bootstrapperSnowQuorumSize: The Snow consensus sample size used for nodes in the network
 // 	bootstrapperSnowSampleSize: The Snow consensus quorum size used for nodes in the network
+// 	exposeNodeFiles: Whether nodes are started in working directories on the test volume, found with
+// 		GetNodeWorkingDirpath, so that the profiles written by their admin API, their IPC sockets, and their logs can be
+// 		accessed
 // 	serviceConfigs: A mapping of service config ID -> config info that the network will provide to the test for use
 // 	desiredServiceConfigs: A map of service_id -> config_id, one per node, that this network will initialize with
 func NewTestAvalancheNetworkLoader(
+	networkID uint32,
 	isStaking bool,
 	bootNodeImage string,
 	bootNodeLogLevel avalancheService.AvalancheLogLevel,
@@ -200,8 +316,15 @@ func NewTestAvalancheNetworkLoader(
 	bootstrapperSnowSampleSize int,
 	txFee uint64,
 	networkInitialTimeout time.Duration,
+	exposeNodeFiles bool,
 	serviceConfigs map[networks.ConfigurationID]TestAvalancheNetworkServiceConfig,
 	desiredServiceConfigs map[networks.ServiceID]networks.ConfigurationID) (*TestAvalancheNetworkLoader, error) {
+	if networkID == avalancheConstants.MainnetID || networkID == avalancheConstants.FujiID {
+		return nil, stacktrace.NewError("Network ID %v belongs to the public %v network and cannot be used for a test network",
+			networkID,
+			avalancheConstants.NetworkName(networkID))
+	}
+
 	// Defensive copy
 	serviceConfigsCopy := make(map[networks.ConfigurationID]TestAvalancheNetworkServiceConfig)
 	for configID, configParams := range serviceConfigs {
@@ -211,6 +334,12 @@ func NewTestAvalancheNetworkLoader(
 				bootNodeConfigIDPrefix,
 				bootNodeConfigIDPrefix)
 		}
+		if strings.HasPrefix(string(configID), snapshotConfigIDPrefix) {
+			return nil, stacktrace.NewError("Config ID %v cannot be used because prefix %v is reserved for snapshot configurations. Choose a configuration id that does not begin with %v.",
+				configID,
+				snapshotConfigIDPrefix,
+				snapshotConfigIDPrefix)
+		}
 		serviceConfigsCopy[configID] = configParams
 	}
 
@@ -236,11 +365,77 @@ func NewTestAvalancheNetworkLoader(
 		bootstrapperSnowSampleSize: bootstrapperSnowSampleSize,
 		txFee:                      txFee,
 		networkInitialTimeout:      networkInitialTimeout,
+		networkID:                  networkID,
+		exposeNodeFiles:            exposeNodeFiles,
 	}, nil
 }
 
+// OverrideNodeLogLevels makes every node of the network, boot nodes included, log at the non-empty levels of [levels]
+// rather than the levels they were configured with. This lets the suite choose how verbose a test's nodes are without
+// changing the test.
+func (loader *TestAvalancheNetworkLoader) OverrideNodeLogLevels(levels avalancheService.NodeLogLevels) {
+	loader.logLevelOverrides = levels
+}
+
+// UseSnapshot makes the network start from the snapshot named [snapshotName] if it has been taken, skipping the setup
+// that the test runs with TestAvalancheNetwork.SetUpFromSnapshot, and otherwise makes that setup take it. A snapshot
+// holds the database and staking cert and key of the boot nodes and of the nodes in [nodeConfigs], which maps their
+// service IDs to the configurations they're launched from. The nodes may be started by the loader or added by the
+// setup. Snapshots are taken from the nodes' working directories, so node files are exposed.
+func (loader *TestAvalancheNetworkLoader) UseSnapshot(snapshotName string, nodeConfigs map[networks.ServiceID]networks.ConfigurationID) error {
+	if snapshotName == "" || snapshotName != filepath.Base(snapshotName) || strings.HasPrefix(snapshotName, ".") {
+		return stacktrace.NewError("Snapshot name '%v' must be a non-empty file name that doesn't start with a dot", snapshotName)
+	}
+	// Defensive copy
+	nodeConfigsCopy := make(map[networks.ServiceID]networks.ConfigurationID, len(nodeConfigs))
+	for serviceID, configID := range nodeConfigs {
+		if _, found := loader.serviceConfigs[configID]; !found {
+			return stacktrace.NewError("Snapshot node %v is launched from configuration %v, which the network doesn't have", serviceID, configID)
+		}
+		nodeConfigsCopy[serviceID] = configID
+	}
+	loader.snapshot = &networkSnapshot{
+		name:  snapshotName,
+		nodes: nodeConfigsCopy,
+	}
+	loader.exposeNodeFiles = true
+	return nil
+}
+
+// nodeLogLevels returns the levels a node configured to log at [configuredLevel] logs at, after applying the overrides
+func (loader TestAvalancheNetworkLoader) nodeLogLevels(configuredLevel avalancheService.AvalancheLogLevel) avalancheService.NodeLogLevels {
+	levels := avalancheService.NodeLogLevels{File: configuredLevel}
+	if loader.logLevelOverrides.File != "" {
+		levels.File = loader.logLevelOverrides.File
+	}
+	levels.Display = loader.logLevelOverrides.Display
+	return levels
+}
+
 // ConfigureNetwork defines the netwrok's service configurations to be used
 func (loader TestAvalancheNetworkLoader) ConfigureNetwork(builder *networks.ServiceNetworkBuilder) error {
+	for configID, config := range loader.nodeConfigurations(loader.exposeNodeFiles) {
+		if err := builder.AddConfiguration(configID, config.imageName, config.initializerCore, config.availabilityCheckerCore); err != nil {
+			return stacktrace.Propagate(err, "An error occurred adding Avalanche node configuration with ID %v", configID)
+		}
+	}
+	return nil
+}
+
+// nodeConfiguration is how the nodes of a configuration of the network are launched, whatever backend executes them
+type nodeConfiguration struct {
+	// The Docker image the nodes are launched from, which other backends map to what they launch instead
+	imageName string
+
+	initializerCore         *avalancheService.AvalancheServiceInitializerCore
+	availabilityCheckerCore services.ServiceAvailabilityCheckerCore
+}
+
+// nodeConfigurations returns the configurations of the boot nodes and of the test's nodes, keyed by configuration ID,
+// with node files exposed if [exposeNodeFiles] is true
+func (loader TestAvalancheNetworkLoader) nodeConfigurations(exposeNodeFiles bool) map[networks.ConfigurationID]nodeConfiguration {
+	configurations := make(map[networks.ConfigurationID]nodeConfiguration)
+
 	localNetGenesisStakers := DefaultLocalNetGenesisConfig.Stakers
 	bootNodeIDs := make([]string, 0, len(localNetGenesisStakers))
 	for _, staker := range DefaultLocalNetGenesisConfig.Stakers {
@@ -256,8 +451,10 @@ func (loader TestAvalancheNetworkLoader) ConfigureNetwork(builder *networks.Serv
 
 		certBytes := bytes.NewBufferString(certString)
 		keyBytes := bytes.NewBufferString(keyString)
+		logLevels := loader.nodeLogLevels(loader.bootNodeLogLevel)
 
 		initializerCore := avalancheService.NewAvalancheServiceInitializerCore(
+			loader.networkID,
 			loader.bootstrapperSnowSampleSize,
 			loader.bootstrapperSnowQuorumSize,
 			loader.txFee,
@@ -266,21 +463,24 @@ func (loader TestAvalancheNetworkLoader) ConfigureNetwork(builder *networks.Serv
 			make(map[string]string), // No additional CLI args for the default network
 			bootNodeIDs[0:i],        // Only the node IDs of the already-started nodes
 			certs.NewStaticAvalancheCertProvider(*keyBytes, *certBytes),
-			loader.bootNodeLogLevel,
+			logLevels.File,
+			logLevels.Display,
+			exposeNodeFiles,
 		)
-		availabilityCheckerCore := avalancheService.AvalancheServiceAvailabilityCheckerCore{}
-
-		if err := builder.AddConfiguration(configID, loader.bootNodeImage, initializerCore, availabilityCheckerCore); err != nil {
-			return stacktrace.Propagate(err, "An error occurred adding bootstrapper node with config ID %v", configID)
+		configurations[configID] = nodeConfiguration{
+			imageName:               loader.bootNodeImage,
+			initializerCore:         initializerCore,
+			availabilityCheckerCore: avalancheService.AvalancheServiceAvailabilityCheckerCore{},
 		}
 	}
 
 	// Add user-custom configs
 	for configID, configParams := range loader.serviceConfigs {
 		certProvider := certs.NewRandomAvalancheCertProvider(configParams.varyCerts)
-		imageName := configParams.imageName
+		logLevels := loader.nodeLogLevels(configParams.serviceLogLevel)
 
 		initializerCore := avalancheService.NewAvalancheServiceInitializerCore(
+			loader.networkID,
 			configParams.snowSampleSize,
 			configParams.snowQuorumSize,
 			loader.txFee,
@@ -289,14 +489,37 @@ func (loader TestAvalancheNetworkLoader) ConfigureNetwork(builder *networks.Serv
 			configParams.additionalCLIArgs,
 			bootNodeIDs,
 			certProvider,
-			configParams.serviceLogLevel,
+			logLevels.File,
+			logLevels.Display,
+			exposeNodeFiles,
 		)
-		availabilityCheckerCore := avalancheService.AvalancheServiceAvailabilityCheckerCore{}
-		if err := builder.AddConfiguration(configID, imageName, initializerCore, availabilityCheckerCore); err != nil {
-			return stacktrace.Propagate(err, "An error occurred adding Avalanche node configuration with ID %v", configID)
+		configurations[configID] = nodeConfiguration{
+			imageName:               configParams.imageName,
+			initializerCore:         initializerCore,
+			availabilityCheckerCore: avalancheService.AvalancheServiceAvailabilityCheckerCore{},
 		}
 	}
-	return nil
+
+	// Add the configs that restore each node of the snapshot from it
+	if loader.snapshot != nil {
+		snapshotNodes := make(map[networks.ServiceID]networks.ConfigurationID)
+		for i := 0; i < len(DefaultLocalNetGenesisConfig.Stakers); i++ {
+			serviceID := networks.ServiceID(bootNodeServiceIDPrefix + strconv.Itoa(i))
+			snapshotNodes[serviceID] = networks.ConfigurationID(bootNodeConfigIDPrefix + strconv.Itoa(i))
+		}
+		for serviceID, configID := range loader.snapshot.nodes {
+			snapshotNodes[serviceID] = configID
+		}
+		for serviceID, configID := range snapshotNodes {
+			config := configurations[configID]
+			configurations[snapshotConfigID(serviceID)] = nodeConfiguration{
+				imageName:               config.imageName,
+				initializerCore:         config.initializerCore.RestoredFrom(loader.snapshot.name, string(serviceID)),
+				availabilityCheckerCore: config.availabilityCheckerCore,
+			}
+		}
+	}
+	return configurations
 }
 
 // InitializeNetwork implements networks.NetworkLoader that initializes the Avalanche test network to the state specified at
@@ -304,16 +527,63 @@ func (loader TestAvalancheNetworkLoader) ConfigureNetwork(builder *networks.Serv
 // NOTE: The resulting services.ServiceAvailabilityChecker map will contain more IDs than the user requested as it will
 // 		contain boot nodes. The IDs that these boot nodes are an unspecified implementation detail.
 func (loader TestAvalancheNetworkLoader) InitializeNetwork(network *networks.ServiceNetwork) (map[networks.ServiceID]services.ServiceAvailabilityChecker, error) {
-	availabilityCheckers := make(map[networks.ServiceID]services.ServiceAvailabilityChecker)
+	return loader.initializeNodes(kurtosisNodeNetwork{svcNetwork: network})
+}
+
+// initializeNodes adds the boot nodes and then the test's nodes to [nodes], returning the availability checkers of
+// every node it added
+func (loader TestAvalancheNetworkLoader) initializeNodes(nodes nodeNetwork) (map[networks.ServiceID]services.ServiceAvailabilityChecker, error) {
+	restore := false
+	if loader.snapshot != nil {
+		taken, err := loader.snapshot.isTaken(loader.networkID)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to check for snapshot %v", loader.snapshot.name)
+		}
+		restore = taken
+		loader.snapshot.restored = taken
+	}
 
-	// Add the bootstrapper nodes
+	var availabilityCheckers map[networks.ServiceID]services.ServiceAvailabilityChecker
+	var bootstrapperServiceIDs map[networks.ServiceID]bool
+	var err error
+	if restore {
+		availabilityCheckers, err = loader.snapshot.restoreNodes(nodes)
+		bootstrapperServiceIDs = bootServiceIDs()
+	} else {
+		availabilityCheckers, bootstrapperServiceIDs, err = addBootNodes(nodes, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Additional user defined nodes
+	for serviceID, configID := range loader.desiredServiceConfig {
+		if _, launched := availabilityCheckers[serviceID]; launched {
+			continue
+		}
+		checker, err := nodes.addService(configID, serviceID, bootstrapperServiceIDs)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Error occurred when adding non-boot node with ID %v and config ID %v", serviceID, configID)
+		}
+		availabilityCheckers[serviceID] = *checker
+	}
+	return availabilityCheckers, nil
+}
+
+// addBootNodes adds the boot nodes to [nodes], each bootstrapping from the ones before it, and restoring them from the
+// network's snapshot if [restore] is true. It returns the availability checkers and the service IDs of the boot nodes.
+func addBootNodes(nodes nodeNetwork, restore bool) (map[networks.ServiceID]services.ServiceAvailabilityChecker, map[networks.ServiceID]bool, error) {
+	availabilityCheckers := make(map[networks.ServiceID]services.ServiceAvailabilityChecker)
 	bootstrapperServiceIDs := make(map[networks.ServiceID]bool)
 	for i := 0; i < len(DefaultLocalNetGenesisConfig.Stakers); i++ {
 		configID := networks.ConfigurationID(bootNodeConfigIDPrefix + strconv.Itoa(i))
 		serviceID := networks.ServiceID(bootNodeServiceIDPrefix + strconv.Itoa(i))
-		checker, err := network.AddService(configID, serviceID, bootstrapperServiceIDs)
+		if restore {
+			configID = snapshotConfigID(serviceID)
+		}
+		checker, err := nodes.addService(configID, serviceID, bootstrapperServiceIDs)
 		if err != nil {
-			return nil, stacktrace.Propagate(err, "Error occurred when adding boot node with ID %v and config ID %v", serviceID, configID)
+			return nil, nil, stacktrace.Propagate(err, "Error occurred when adding boot node with ID %v and config ID %v", serviceID, configID)
 		}
 
 		// TODO the first node should have zero dependencies and the rest should
@@ -321,21 +591,24 @@ func (loader TestAvalancheNetworkLoader) InitializeNetwork(network *networks.Ser
 		bootstrapperServiceIDs[serviceID] = true
 		availabilityCheckers[serviceID] = *checker
 	}
+	return availabilityCheckers, bootstrapperServiceIDs, nil
+}
 
-	// Additional user defined nodes
-	for serviceID, configID := range loader.desiredServiceConfig {
-		checker, err := network.AddService(configID, serviceID, bootstrapperServiceIDs)
-		if err != nil {
-			return nil, stacktrace.Propagate(err, "Error occurred when adding non-boot node with ID %v and config ID %v", serviceID, configID)
-		}
-		availabilityCheckers[serviceID] = *checker
+// bootServiceIDs returns the service IDs of all the boot nodes
+func bootServiceIDs() map[networks.ServiceID]bool {
+	result := make(map[networks.ServiceID]bool)
+	for i := 0; i < len(DefaultLocalNetGenesisConfig.Stakers); i++ {
+		result[networks.ServiceID(bootNodeServiceIDPrefix+strconv.Itoa(i))] = true
 	}
-	return availabilityCheckers, nil
+	return result
 }
 
 // WrapNetwork implements a networks.NetworkLoader function and wraps the underlying networks.ServiceNetwork with the TestAvalancheNetwork
 func (loader TestAvalancheNetworkLoader) WrapNetwork(network *networks.ServiceNetwork) (networks.Network, error) {
 	return TestAvalancheNetwork{
-		svcNetwork: network,
+		nodes:           kurtosisNodeNetwork{svcNetwork: network},
+		networkID:       loader.networkID,
+		exposeNodeFiles: loader.exposeNodeFiles,
+		snapshot:        loader.snapshot,
 	}, nil
 }
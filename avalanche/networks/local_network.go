@@ -0,0 +1,365 @@
+package networks
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/services"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/logging"
+	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/utils/files"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// The IP that local nodes listen on and advertise to their peers
+	localNodeIP = "127.0.0.1"
+
+	// Time a local node is given to shut down once interrupted, after which it's killed
+	localNodeStopTimeout = containerStopTimeoutSeconds * time.Second
+
+	// Directory, next to the avalanchego binary, that avalanchego builds its VM plugins into
+	localPluginsDirname = "plugins"
+
+	// File, in a local node's working directory, that the node's output is written to
+	localNodeOutputFilename = "output.log"
+)
+
+// LocalNetwork runs the nodes of a test network as avalanchego processes on this machine rather than in Docker
+// containers, so that tests can run without Docker or Kurtosis. It launches the same boot nodes and node configurations
+// as Kurtosis would from the network's loader. Each node listens on its own ports on 127.0.0.1 and runs in its own
+// working directory, which holds its database, staking cert files, logs, and output. Nodes always run with their files
+// exposed.
+type LocalNetwork struct {
+	loader TestAvalancheNetworkLoader
+
+	// Configuration ID -> how the nodes of the configuration are launched
+	configurations map[networks.ConfigurationID]nodeConfiguration
+
+	// Image name -> absolute path of the avalanchego binary launched instead of the image
+	binaryPaths map[string]string
+
+	// Directory holding the working directory of every node
+	rootDirpath string
+
+	lock sync.Mutex
+
+	nodes map[networks.ServiceID]*localNode
+
+	// Ports handed to nodes so far, which aren't handed out again even once their nodes stop
+	usedPorts map[int]bool
+}
+
+// localNode is a running avalanchego process
+type localNode struct {
+	service        avalancheService.AvalancheService
+	workingDirpath string
+	cmd            *exec.Cmd
+	output         *os.File
+
+	// Closed once the process has exited
+	exited chan struct{}
+}
+
+// NewLocalNetwork creates a network that launches the nodes [loader] defines as local processes, in working
+// directories under [rootDirpath]. Nodes aren't launched until Launch is called.
+// Args:
+// 	loader: The loader defining the network, as it would be passed to Kurtosis
+// 	binaryPaths: A mapping of image name -> avalanchego binary that replaces it, for every image the loader launches
+// 		nodes from. The VM plugins of a binary must be in the plugins directory next to it, where avalanchego builds them.
+// 	rootDirpath: The directory the working directories of the nodes are created in
+func NewLocalNetwork(loader *TestAvalancheNetworkLoader, binaryPaths map[string]string, rootDirpath string) (*LocalNetwork, error) {
+	configurations := loader.nodeConfigurations(true)
+	absBinaryPaths := make(map[string]string, len(binaryPaths))
+	for configID, config := range configurations {
+		binaryPath, found := binaryPaths[config.imageName]
+		if !found {
+			return nil, stacktrace.NewError("No avalanchego binary was given for image %v, which configuration %v launches nodes from", config.imageName, configID)
+		}
+		absBinaryPath, err := filepath.Abs(binaryPath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to get the absolute path of binary %v", binaryPath)
+		}
+		if _, err := os.Stat(absBinaryPath); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to find the avalanchego binary for image %v", config.imageName)
+		}
+		absBinaryPaths[config.imageName] = absBinaryPath
+	}
+	if err := os.MkdirAll(rootDirpath, 0755); err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to create the directory of the local nodes %v", rootDirpath)
+	}
+
+	return &LocalNetwork{
+		loader:         *loader,
+		configurations: configurations,
+		binaryPaths:    absBinaryPaths,
+		rootDirpath:    rootDirpath,
+		nodes:          make(map[networks.ServiceID]*localNode),
+		usedPorts:      make(map[int]bool),
+	}, nil
+}
+
+// Launch starts the boot nodes and then the nodes the loader requested, waits until every one of them is available,
+// and returns the network for tests to run against. The nodes must be stopped with Close once the test is done, even
+// if Launch fails.
+func (network *LocalNetwork) Launch() (TestAvalancheNetwork, error) {
+	availabilityCheckers, err := network.loader.initializeNodes(network)
+	if err != nil {
+		return TestAvalancheNetwork{}, stacktrace.Propagate(err, "Failed to launch the nodes of the network")
+	}
+	for serviceID, checker := range availabilityCheckers {
+		if err := checker.WaitForStartup(); err != nil {
+			return TestAvalancheNetwork{}, stacktrace.Propagate(err, "Node %v did not become available", serviceID)
+		}
+	}
+	return TestAvalancheNetwork{
+		nodes:           network,
+		networkID:       network.loader.networkID,
+		exposeNodeFiles: true,
+		snapshot:        network.loader.snapshot,
+	}, nil
+}
+
+// Close stops every node of the network that's still running. Their working directories are kept.
+func (network *LocalNetwork) Close() {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+
+	for serviceID, node := range network.nodes {
+		network.stopNode(serviceID, node)
+		delete(network.nodes, serviceID)
+	}
+}
+
+func (network *LocalNetwork) addService(configID networks.ConfigurationID, serviceID networks.ServiceID, dependencies map[networks.ServiceID]bool) (*services.ServiceAvailabilityChecker, error) {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+
+	config, found := network.configurations[configID]
+	if !found {
+		return nil, stacktrace.NewError("No service configuration with ID '%v' has been registered", configID)
+	}
+	if _, exists := network.nodes[serviceID]; exists {
+		return nil, stacktrace.NewError("Service ID %s already exists in the network", serviceID)
+	}
+	dependencyServices := make([]services.Service, 0, len(dependencies))
+	for dependencyID := range dependencies {
+		dependency, found := network.nodes[dependencyID]
+		if !found {
+			return nil, stacktrace.NewError("Declared a dependency on %v but no service with this ID has been registered", dependencyID)
+		}
+		dependencyServices = append(dependencyServices, dependency.service)
+	}
+
+	workingDirpath, err := network.createWorkingDir(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	mountedFilepaths, err := initializeLocalFiles(config.initializerCore, workingDirpath, dependencyServices)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to initialize the files of node %v", serviceID)
+	}
+	dbDirpath := avalancheService.WorkingDirDBDirpath(workingDirpath)
+	if restoreDBDirpath := config.initializerCore.RestoreDBDirpath(); restoreDBDirpath != "" {
+		if err := files.CopyDir(restoreDBDirpath, dbDirpath); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to restore the database of node %v", serviceID)
+		}
+	}
+	ports, err := network.freePorts(2)
+	if err != nil {
+		return nil, err
+	}
+	binaryPath := network.binaryPaths[config.imageName]
+	launch := avalancheService.NodeLaunch{
+		BinaryPath:    binaryPath,
+		PublicIP:      localNodeIP,
+		HTTPPort:      ports[0],
+		StakingPort:   ports[1],
+		DBDirpath:     dbDirpath,
+		PluginDirpath: filepath.Join(filepath.Dir(binaryPath), localPluginsDirname),
+	}
+	command, err := config.initializerCore.NodeCommand(launch, mountedFilepaths, dependencyServices)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to build the command of node %v", serviceID)
+	}
+
+	node, err := startLocalNode(command, workingDirpath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to start node %v", serviceID)
+	}
+	node.service = avalancheService.NewAvalancheService(localNodeIP, launch.HTTPPort, launch.StakingPort)
+	network.nodes[serviceID] = node
+	logging.ForService(string(serviceID)).Debugf("Started local node %v with HTTP port %d and staking port %d in %s", serviceID, launch.HTTPPort, launch.StakingPort, workingDirpath)
+
+	return services.NewServiceAvailabilityChecker(context.Background(), config.availabilityCheckerCore, node.service, dependencyServices), nil
+}
+
+func (network *LocalNetwork) getService(serviceID networks.ServiceID) (avalancheService.AvalancheService, error) {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+
+	node, found := network.nodes[serviceID]
+	if !found {
+		return avalancheService.AvalancheService{}, stacktrace.NewError("No service with ID %v exists in the network", serviceID)
+	}
+	return node.service, nil
+}
+
+func (network *LocalNetwork) removeService(serviceID networks.ServiceID) error {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+
+	node, found := network.nodes[serviceID]
+	if !found {
+		return stacktrace.NewError("No service with ID %v found", serviceID)
+	}
+	delete(network.nodes, serviceID)
+	network.stopNode(serviceID, node)
+	return nil
+}
+
+func (network *LocalNetwork) getNodeWorkingDirpath(serviceID networks.ServiceID) (string, error) {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+
+	node, found := network.nodes[serviceID]
+	if !found {
+		return "", stacktrace.NewError("No service with ID %v exists in the network", serviceID)
+	}
+	return node.workingDirpath, nil
+}
+
+// createWorkingDir creates a new working directory for a node with service ID [serviceID]. A node added again after
+// being removed gets a new directory, so the files of the removed node are kept.
+// Assumes [network.lock] is held.
+func (network *LocalNetwork) createWorkingDir(serviceID networks.ServiceID) (string, error) {
+	workingDirpath := filepath.Join(network.rootDirpath, string(serviceID))
+	for i := 2; ; i++ {
+		if _, err := os.Stat(workingDirpath); os.IsNotExist(err) {
+			break
+		}
+		workingDirpath = filepath.Join(network.rootDirpath, string(serviceID)+"-"+strconv.Itoa(i))
+	}
+	if err := os.MkdirAll(workingDirpath, 0755); err != nil {
+		return "", stacktrace.Propagate(err, "Failed to create working directory %v", workingDirpath)
+	}
+	return workingDirpath, nil
+}
+
+// freePorts returns [numPorts] ports that are free on the local IP and weren't handed out before. Another process could
+// take a port between this check and the node binding it, which is unlikely enough for tests.
+// Assumes [network.lock] is held.
+func (network *LocalNetwork) freePorts(numPorts int) ([]int, error) {
+	listeners := make([]net.Listener, 0, numPorts)
+	defer func() {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+	}()
+
+	ports := make([]int, 0, numPorts)
+	for len(ports) < numPorts {
+		listener, err := net.Listen("tcp", net.JoinHostPort(localNodeIP, "0"))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to find a free port on %v", localNodeIP)
+		}
+		listeners = append(listeners, listener)
+		port := listener.Addr().(*net.TCPAddr).Port
+		if network.usedPorts[port] {
+			continue
+		}
+		network.usedPorts[port] = true
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+// stopNode interrupts the node and waits for it to exit, killing it if it doesn't exit in time. Failures are logged
+// rather than returned, like Kurtosis makes a best-effort attempt to stop containers.
+func (network *LocalNetwork) stopNode(serviceID networks.ServiceID, node *localNode) {
+	defer node.output.Close()
+
+	select {
+	case <-node.exited:
+		logging.ForService(string(serviceID)).Warnf("Local node %v had already exited: %v", serviceID, node.cmd.ProcessState)
+		return
+	default:
+	}
+	if err := node.cmd.Process.Signal(os.Interrupt); err != nil {
+		logrus.Errorf("Failed to interrupt local node %v: %v", serviceID, err)
+	}
+	select {
+	case <-node.exited:
+	case <-time.After(localNodeStopTimeout):
+		logrus.Errorf("Local node %v didn't stop within %v of being interrupted; killing it", serviceID, localNodeStopTimeout)
+		if err := node.cmd.Process.Kill(); err != nil {
+			logrus.Errorf("Failed to kill local node %v: %v", serviceID, err)
+		}
+		<-node.exited
+	}
+	logrus.Debugf("Stopped local node %v", serviceID)
+}
+
+// initializeLocalFiles creates the files [core] requests in [workingDirpath] and initializes them, returning the paths
+// of the files keyed by their IDs
+func initializeLocalFiles(core *avalancheService.AvalancheServiceInitializerCore, workingDirpath string, dependencies []services.Service) (map[string]string, error) {
+	osFiles := make(map[string]*os.File)
+	filepaths := make(map[string]string)
+	defer func() {
+		for _, file := range osFiles {
+			file.Close()
+		}
+	}()
+	for fileID := range core.GetFilesToMount() {
+		fileFilepath := filepath.Join(workingDirpath, fileID)
+		file, err := os.Create(fileFilepath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Could not create new file for requested file ID '%v'", fileID)
+		}
+		osFiles[fileID] = file
+		filepaths[fileID] = fileFilepath
+	}
+	if err := core.InitializeMountedFiles(osFiles, dependencies); err != nil {
+		return nil, err
+	}
+	return filepaths, nil
+}
+
+// startLocalNode starts [command] in [workingDirpath], writing its output to a file there
+func startLocalNode(command []string, workingDirpath string) (*localNode, error) {
+	outputFilepath := filepath.Join(workingDirpath, localNodeOutputFilename)
+	output, err := os.Create(outputFilepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to create output file %v", outputFilepath)
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = workingDirpath
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		output.Close()
+		return nil, stacktrace.Propagate(err, "Failed to start %v", command[0])
+	}
+
+	node := &localNode{
+		workingDirpath: workingDirpath,
+		cmd:            cmd,
+		output:         output,
+		exited:         make(chan struct{}),
+	}
+	go func() {
+		// The exit status is kept in cmd.ProcessState
+		_ = cmd.Wait()
+		close(node.exited)
+	}()
+	return node, nil
+}
@@ -0,0 +1,61 @@
+package networks
+
+import (
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/services"
+
+	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/palantir/stacktrace"
+)
+
+// nodeNetwork is the set of running nodes behind a TestAvalancheNetwork, which is managed by the backend executing the
+// nodes
+type nodeNetwork interface {
+	// addService launches a node with service ID [serviceID] from the configuration with ID [configID], bootstrapping
+	// from the nodes in [dependencies], and returns a checker for when the node is available
+	addService(configID networks.ConfigurationID, serviceID networks.ServiceID, dependencies map[networks.ServiceID]bool) (*services.ServiceAvailabilityChecker, error)
+
+	// getService returns the service of the node with service ID [serviceID]
+	getService(serviceID networks.ServiceID) (avalancheService.AvalancheService, error)
+
+	// removeService stops the node with service ID [serviceID] and removes it from the network
+	removeService(serviceID networks.ServiceID) error
+
+	// getNodeWorkingDirpath returns the path, as seen by the test suite, of the working directory of the node with
+	// service ID [serviceID], which only exists if the node was started with its files exposed
+	getNodeWorkingDirpath(serviceID networks.ServiceID) (string, error)
+}
+
+// kurtosisNodeNetwork runs nodes in Docker containers through Kurtosis
+type kurtosisNodeNetwork struct {
+	svcNetwork *networks.ServiceNetwork
+}
+
+func (nodes kurtosisNodeNetwork) addService(configID networks.ConfigurationID, serviceID networks.ServiceID, dependencies map[networks.ServiceID]bool) (*services.ServiceAvailabilityChecker, error) {
+	return nodes.svcNetwork.AddService(configID, serviceID, dependencies)
+}
+
+func (nodes kurtosisNodeNetwork) getService(serviceID networks.ServiceID) (avalancheService.AvalancheService, error) {
+	node, err := nodes.svcNetwork.GetService(serviceID)
+	if err != nil {
+		return avalancheService.AvalancheService{}, err
+	}
+	service, ok := node.Service.(avalancheService.AvalancheService)
+	if !ok {
+		return avalancheService.AvalancheService{}, stacktrace.NewError("Service with ID %v is not an Avalanche node", serviceID)
+	}
+	return service, nil
+}
+
+func (nodes kurtosisNodeNetwork) removeService(serviceID networks.ServiceID) error {
+	return nodes.svcNetwork.RemoveService(serviceID, containerStopTimeoutSeconds)
+}
+
+func (nodes kurtosisNodeNetwork) getNodeWorkingDirpath(serviceID networks.ServiceID) (string, error) {
+	service, err := nodes.getService(serviceID)
+	if err != nil {
+		return "", err
+	}
+	jsonRPCSocket := service.GetJSONRPCSocket()
+	return avalancheService.NodeWorkingDirpath(jsonRPCSocket.GetIPAddr()), nil
+}
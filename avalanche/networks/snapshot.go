@@ -0,0 +1,239 @@
+package networks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/services"
+
+	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/utils/files"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// File, in a snapshot's directory, describing the network the snapshot was taken of. A snapshot directory only
+	// appears once the snapshot is complete, so this file existing means the snapshot can be restored.
+	snapshotManifestFilename = "snapshot.json"
+
+	// Time between checks of whether a removed snapshot node has stopped, and how long it may take to stop
+	nodeStopPollInterval = 500 * time.Millisecond
+	nodeStopTimeout      = time.Minute
+)
+
+// networkSnapshot is a snapshot, named by the test, of the databases and staking certs and keys of a network's nodes
+// after a setup phase. Tests starting from a taken snapshot skip the setup, such as adding and funding validators.
+type networkSnapshot struct {
+	name string
+
+	// Service ID -> configuration ID of the nodes, other than the boot nodes, that the snapshot holds
+	nodes map[networks.ServiceID]networks.ConfigurationID
+
+	// Whether the network's nodes were restored from the snapshot rather than started from scratch
+	restored bool
+}
+
+// snapshotManifest describes the network a snapshot was taken of, so that it's only restored onto the same network
+type snapshotManifest struct {
+	NetworkID uint32 `json:"networkID"`
+
+	// Service ID -> configuration ID of the nodes, other than the boot nodes, that the snapshot holds
+	Nodes map[networks.ServiceID]networks.ConfigurationID `json:"nodes"`
+}
+
+// SetUpFromSnapshot brings the network to the state of the snapshot its loader uses. If the network was restored from
+// the snapshot, it's already in that state. Otherwise [setup] is run against the network, after which every node of
+// the snapshot is stopped, the snapshot is taken, and the nodes are restarted from it. Only state kept by the nodes
+// survives a restore, so tests must not rely on values computed by [setup] outside the network.
+func (network TestAvalancheNetwork) SetUpFromSnapshot(setup func() error) error {
+	snapshot := network.snapshot
+	if snapshot == nil {
+		return stacktrace.NewError("The network wasn't loaded with a snapshot")
+	}
+	if snapshot.restored {
+		logrus.Infof("Network was restored from snapshot %v; skipping its setup.", snapshot.name)
+		return nil
+	}
+	if err := setup(); err != nil {
+		return stacktrace.Propagate(err, "Failed to set up the network for snapshot %v", snapshot.name)
+	}
+
+	// Every node is stopped before any database is copied, so that the copies agree with each other
+	nodeWorkingDirpaths := make(map[networks.ServiceID]string)
+	clients := make(map[networks.ServiceID]*avalancheService.Client)
+	for serviceID := range snapshot.serviceIDs() {
+		workingDirpath, err := network.nodes.getNodeWorkingDirpath(serviceID)
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to get the working directory of snapshot node %v", serviceID)
+		}
+		nodeWorkingDirpaths[serviceID] = workingDirpath
+		if clients[serviceID], err = network.GetAvalancheClient(serviceID); err != nil {
+			return stacktrace.Propagate(err, "Failed to get the client of snapshot node %v", serviceID)
+		}
+	}
+	for serviceID := range nodeWorkingDirpaths {
+		if err := network.nodes.removeService(serviceID); err != nil {
+			return stacktrace.Propagate(err, "Failed to stop snapshot node %v", serviceID)
+		}
+	}
+	// Kurtosis doesn't report failing to stop a removed service, so a node still writing to its database could
+	// otherwise be copied
+	for serviceID, client := range clients {
+		if err := awaitNodeStopped(client); err != nil {
+			return stacktrace.Propagate(err, "Snapshot node %v didn't stop", serviceID)
+		}
+	}
+	manifest := snapshotManifest{
+		NetworkID: network.networkID,
+		Nodes:     snapshot.nodes,
+	}
+	if err := writeSnapshot(avalancheService.SnapshotDirpath(snapshot.name), nodeWorkingDirpaths, manifest); err != nil {
+		return stacktrace.Propagate(err, "Failed to take snapshot %v", snapshot.name)
+	}
+	logrus.Infof("Took snapshot %v; restarting its nodes from it...", snapshot.name)
+
+	availabilityCheckers, err := snapshot.restoreNodes(network.nodes)
+	if err != nil {
+		return err
+	}
+	for serviceID, checker := range availabilityCheckers {
+		if err := checker.WaitForStartup(); err != nil {
+			return stacktrace.Propagate(err, "Node %v did not become available after being restored from snapshot %v", serviceID, snapshot.name)
+		}
+	}
+	return nil
+}
+
+// awaitNodeStopped waits until the API of the node of [client] stops responding, failing after nodeStopTimeout
+func awaitNodeStopped(client *avalancheService.Client) error {
+	for deadline := time.Now().Add(nodeStopTimeout); time.Now().Before(deadline); time.Sleep(nodeStopPollInterval) {
+		if _, err := client.Liveness(); err != nil {
+			return nil
+		}
+	}
+	return stacktrace.NewError("Node's health API still responded %v after it was removed", nodeStopTimeout)
+}
+
+// serviceIDs returns the service IDs of every node the snapshot holds, boot nodes included
+func (snapshot networkSnapshot) serviceIDs() map[networks.ServiceID]bool {
+	serviceIDs := bootServiceIDs()
+	for serviceID := range snapshot.nodes {
+		serviceIDs[serviceID] = true
+	}
+	return serviceIDs
+}
+
+// isTaken returns whether the snapshot has been taken, returning an error if it was taken of a network other than
+// one with ID [networkID] and the snapshot's nodes
+func (snapshot networkSnapshot) isTaken(networkID uint32) (bool, error) {
+	manifestFilepath := filepath.Join(avalancheService.SnapshotDirpath(snapshot.name), snapshotManifestFilename)
+	manifestBytes, err := ioutil.ReadFile(manifestFilepath)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to read snapshot manifest %v", manifestFilepath)
+	}
+	var manifest snapshotManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return false, stacktrace.Propagate(err, "Failed to parse snapshot manifest %v", manifestFilepath)
+	}
+
+	matches := manifest.NetworkID == networkID && len(manifest.Nodes) == len(snapshot.nodes)
+	for serviceID, configID := range snapshot.nodes {
+		matches = matches && manifest.Nodes[serviceID] == configID
+	}
+	if !matches {
+		return false, stacktrace.NewError(
+			"Snapshot %v was taken of network %d with nodes %v, but the network is %d with nodes %v; delete %v to retake it",
+			snapshot.name,
+			manifest.NetworkID,
+			manifest.Nodes,
+			networkID,
+			snapshot.nodes,
+			avalancheService.SnapshotDirpath(snapshot.name),
+		)
+	}
+	return true, nil
+}
+
+// restoreNodes adds every node of the snapshot to [nodes], restored from the snapshot, and returns their availability
+// checkers
+func (snapshot networkSnapshot) restoreNodes(nodes nodeNetwork) (map[networks.ServiceID]services.ServiceAvailabilityChecker, error) {
+	availabilityCheckers, bootstrapperServiceIDs, err := addBootNodes(nodes, true)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to restore the boot nodes from snapshot %v", snapshot.name)
+	}
+	for serviceID := range snapshot.nodes {
+		checker, err := nodes.addService(snapshotConfigID(serviceID), serviceID, bootstrapperServiceIDs)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to restore node %v from snapshot %v", serviceID, snapshot.name)
+		}
+		availabilityCheckers[serviceID] = *checker
+	}
+	return availabilityCheckers, nil
+}
+
+// snapshotConfigID returns the ID of the configuration that restores the node with service ID [serviceID] from the
+// network's snapshot
+func snapshotConfigID(serviceID networks.ServiceID) networks.ConfigurationID {
+	return networks.ConfigurationID(snapshotConfigIDPrefix + string(serviceID))
+}
+
+// writeSnapshot copies the database and staking cert and key of the stopped nodes in [nodeWorkingDirpaths], keyed by
+// service ID, into a snapshot at [snapshotDirpath] described by [manifest]. The snapshot is written to a temporary
+// directory first, so it only appears once complete. If another test took the same snapshot in the meantime, theirs
+// is kept.
+func writeSnapshot(snapshotDirpath string, nodeWorkingDirpaths map[networks.ServiceID]string, manifest snapshotManifest) error {
+	snapshotsDirpath := filepath.Dir(snapshotDirpath)
+	if err := os.MkdirAll(snapshotsDirpath, 0755); err != nil {
+		return stacktrace.Propagate(err, "Failed to create snapshots directory %v", snapshotsDirpath)
+	}
+	tempDirpath, err := ioutil.TempDir(snapshotsDirpath, "."+filepath.Base(snapshotDirpath)+"-")
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create a temporary snapshot directory")
+	}
+	defer os.RemoveAll(tempDirpath)
+	if err := os.Chmod(tempDirpath, 0755); err != nil {
+		return stacktrace.Propagate(err, "Failed to make temporary snapshot directory %v readable", tempDirpath)
+	}
+
+	for serviceID, workingDirpath := range nodeWorkingDirpaths {
+		nodeDirpath := filepath.Join(tempDirpath, string(serviceID))
+		if err := files.CopyDir(avalancheService.WorkingDirDBDirpath(workingDirpath), avalancheService.WorkingDirDBDirpath(nodeDirpath)); err != nil {
+			return stacktrace.Propagate(err, "Failed to copy the database of node %v", serviceID)
+		}
+		certFilepath, keyFilepath := avalancheService.WorkingDirStakingFilepaths(workingDirpath)
+		snapshotCertFilepath, snapshotKeyFilepath := avalancheService.WorkingDirStakingFilepaths(nodeDirpath)
+		for src, dst := range map[string]string{certFilepath: snapshotCertFilepath, keyFilepath: snapshotKeyFilepath} {
+			// Nodes only have staking certs and keys if staking is enabled
+			if _, err := os.Stat(src); os.IsNotExist(err) {
+				continue
+			}
+			if err := files.CopyFile(src, dst, 0600); err != nil {
+				return stacktrace.Propagate(err, "Failed to copy the staking files of node %v", serviceID)
+			}
+		}
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to serialize the snapshot manifest")
+	}
+	if err := ioutil.WriteFile(filepath.Join(tempDirpath, snapshotManifestFilename), manifestBytes, 0644); err != nil {
+		return stacktrace.Propagate(err, "Failed to write the snapshot manifest")
+	}
+
+	if err := os.Rename(tempDirpath, snapshotDirpath); err != nil {
+		if _, statErr := os.Stat(filepath.Join(snapshotDirpath, snapshotManifestFilename)); statErr == nil {
+			logrus.Infof("Snapshot %v was taken by another test in the meantime; keeping theirs.", snapshotDirpath)
+			return nil
+		}
+		return stacktrace.Propagate(err, "Failed to move the snapshot into %v", snapshotDirpath)
+	}
+	return nil
+}
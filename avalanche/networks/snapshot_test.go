@@ -0,0 +1,104 @@
+package networks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+
+	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testNodeConfigID networks.ConfigurationID = "normal-config"
+)
+
+func newSnapshotTestLoader(t *testing.T) *TestAvalancheNetworkLoader {
+	serviceConfigs := map[networks.ConfigurationID]TestAvalancheNetworkServiceConfig{
+		testNodeConfigID: *NewTestAvalancheNetworkServiceConfig(true, avalancheService.INFO, "avalanche-go", 2, 2, 2*time.Second, make(map[string]string)),
+	}
+	loader, err := NewTestAvalancheNetworkLoader(LocalNetworkID, true, "avalanche-go", avalancheService.INFO, 2, 2, 0, 2*time.Second, false, serviceConfigs, make(map[networks.ServiceID]networks.ConfigurationID))
+	assert.NoError(t, err)
+	return loader
+}
+
+func writeTestWorkingDir(t *testing.T, workingDirpath string, contents string) {
+	dbDirpath := avalancheService.WorkingDirDBDirpath(workingDirpath)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dbDirpath, "v1.0.0"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dbDirpath, "v1.0.0", "000001.log"), []byte(contents), 0644))
+	certFilepath, keyFilepath := avalancheService.WorkingDirStakingFilepaths(workingDirpath)
+	assert.NoError(t, ioutil.WriteFile(certFilepath, []byte(contents+"-cert"), 0600))
+	assert.NoError(t, ioutil.WriteFile(keyFilepath, []byte(contents+"-key"), 0600))
+}
+
+func TestUseSnapshot(t *testing.T) {
+	loader := newSnapshotTestLoader(t)
+	assert.Error(t, loader.UseSnapshot("../stakers", nil), "Expected an error for a snapshot name that's a path")
+	assert.Error(t, loader.UseSnapshot("stakers", map[networks.ServiceID]networks.ConfigurationID{"validator-1": "unknown-config"}), "Expected an error for an unknown configuration")
+
+	assert.NoError(t, loader.UseSnapshot("stakers", map[networks.ServiceID]networks.ConfigurationID{"validator-1": testNodeConfigID}))
+	assert.True(t, loader.exposeNodeFiles, "Expected node files to be exposed for snapshots")
+	configurations := loader.nodeConfigurations(loader.exposeNodeFiles)
+	for serviceID := range loader.snapshot.serviceIDs() {
+		config, found := configurations[snapshotConfigID(serviceID)]
+		if assert.True(t, found, "Expected a configuration restoring %v", serviceID) {
+			assert.Equal(t, avalancheService.WorkingDirDBDirpath(avalancheService.SnapshotNodeDirpath("stakers", string(serviceID))), config.initializerCore.RestoreDBDirpath())
+		}
+	}
+}
+
+func TestWriteSnapshot(t *testing.T) {
+	rootDirpath := t.TempDir()
+	// The suite execution directory is global, so it's restored for the tests that run after this one
+	oldSuiteExecutionDirpath := avalancheService.SuiteExecutionPath("")
+	t.Cleanup(func() { avalancheService.SetSuiteExecutionDirpath(oldSuiteExecutionDirpath) })
+	avalancheService.SetSuiteExecutionDirpath(rootDirpath)
+	loader := newSnapshotTestLoader(t)
+	assert.NoError(t, loader.UseSnapshot("stakers", map[networks.ServiceID]networks.ConfigurationID{"validator-1": testNodeConfigID}))
+	snapshot := loader.snapshot
+
+	taken, err := snapshot.isTaken(LocalNetworkID)
+	assert.NoError(t, err)
+	assert.False(t, taken)
+
+	workingDirpaths := map[networks.ServiceID]string{
+		"boot-node-0": filepath.Join(rootDirpath, "nodes", "10.0.0.1"),
+		"validator-1": filepath.Join(rootDirpath, "nodes", "10.0.0.2"),
+	}
+	for serviceID, workingDirpath := range workingDirpaths {
+		writeTestWorkingDir(t, workingDirpath, string(serviceID))
+	}
+	manifest := snapshotManifest{NetworkID: LocalNetworkID, Nodes: snapshot.nodes}
+	snapshotDirpath := avalancheService.SnapshotDirpath(snapshot.name)
+	assert.NoError(t, writeSnapshot(snapshotDirpath, workingDirpaths, manifest))
+
+	taken, err = snapshot.isTaken(LocalNetworkID)
+	assert.NoError(t, err)
+	assert.True(t, taken)
+	nodeDirpath := avalancheService.SnapshotNodeDirpath(snapshot.name, "validator-1")
+	logBytes, err := ioutil.ReadFile(filepath.Join(avalancheService.WorkingDirDBDirpath(nodeDirpath), "v1.0.0", "000001.log"))
+	assert.NoError(t, err)
+	assert.Equal(t, "validator-1", string(logBytes))
+	certFilepath, _ := avalancheService.WorkingDirStakingFilepaths(nodeDirpath)
+	certBytes, err := ioutil.ReadFile(certFilepath)
+	assert.NoError(t, err)
+	assert.Equal(t, "validator-1-cert", string(certBytes))
+
+	// A snapshot taken concurrently by another test is kept
+	writeTestWorkingDir(t, workingDirpaths["validator-1"], "other")
+	assert.NoError(t, writeSnapshot(snapshotDirpath, workingDirpaths, manifest))
+	logBytes, err = ioutil.ReadFile(filepath.Join(avalancheService.WorkingDirDBDirpath(nodeDirpath), "v1.0.0", "000001.log"))
+	assert.NoError(t, err)
+	assert.Equal(t, "validator-1", string(logBytes))
+	entries, err := ioutil.ReadDir(filepath.Dir(snapshotDirpath))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "Expected the temporary snapshot directory to be removed")
+
+	// A snapshot of another network isn't restored
+	_, err = snapshot.isTaken(LocalNetworkID + 1)
+	assert.Error(t, err, "Expected an error for a snapshot of another network")
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"github.com/ava-labs/avalanchego/api/admin"
+	"github.com/palantir/stacktrace"
+)
+
+// Files that a node's admin API writes profiles to, relative to the node's working directory
+const (
+	CPUProfileFile  = "cpu.profile"
+	MemProfileFile  = "mem.profile"
+	LockProfileFile = "lock.profile"
+	StacktraceFile  = "stacktrace.txt"
+)
+
+// NodeAdmin triggers admin operations on a node, returning an error if the node fails to perform them
+type NodeAdmin struct {
+	client *admin.Client
+}
+
+// AliasChain gives the chain with ID or alias [chain] the additional alias [alias]
+func (a NodeAdmin) AliasChain(chain string, alias string) error {
+	success, err := a.client.AliasChain(chain, alias)
+	return checkAdminReply("aliasChain", success, err)
+}
+
+// StartCPUProfiler starts profiling the node's CPU usage into CPUProfileFile. Only one CPU profile may run at a time.
+func (a NodeAdmin) StartCPUProfiler() error {
+	success, err := a.client.StartCPUProfiler()
+	return checkAdminReply("startCPUProfiler", success, err)
+}
+
+// StopCPUProfiler stops the CPU profile started by StartCPUProfiler and flushes it to CPUProfileFile
+func (a NodeAdmin) StopCPUProfiler() error {
+	success, err := a.client.StopCPUProfiler()
+	return checkAdminReply("stopCPUProfiler", success, err)
+}
+
+// MemoryProfile writes a heap profile of the node to MemProfileFile
+func (a NodeAdmin) MemoryProfile() error {
+	success, err := a.client.MemoryProfile()
+	return checkAdminReply("memoryProfile", success, err)
+}
+
+// LockProfile writes a mutex contention profile of the node to LockProfileFile
+func (a NodeAdmin) LockProfile() error {
+	success, err := a.client.LockProfile()
+	return checkAdminReply("lockProfile", success, err)
+}
+
+// Stacktrace writes the stacktraces of all of the node's goroutines to StacktraceFile
+func (a NodeAdmin) Stacktrace() error {
+	success, err := a.client.Stacktrace()
+	return checkAdminReply("stacktrace", success, err)
+}
+
+func checkAdminReply(method string, success bool, err error) error {
+	if err != nil {
+		return stacktrace.Propagate(err, "Admin API call %s failed.", method)
+	}
+	if !success {
+		return stacktrace.NewError("Admin API call %s was unsuccessful.", method)
+	}
+	return nil
+}
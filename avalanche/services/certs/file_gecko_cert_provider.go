@@ -0,0 +1,36 @@
+package certs
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/palantir/stacktrace"
+)
+
+// FileAvalancheCertProvider implements AvalancheCertProvider and provides the cert and key stored in files, such as
+// those of a node in a network snapshot
+type FileAvalancheCertProvider struct {
+	certFilepath string
+	keyFilepath  string
+}
+
+// NewFileAvalancheCertProvider creates an instance of FileAvalancheCertProvider that reads the given files
+// Args:
+// 	certFilepath: The file holding the PEM-encoded cert, which is read on every call to GetCertAndKey
+// 	keyFilepath: The file holding the PEM-encoded private key, which is read on every call to GetCertAndKey
+func NewFileAvalancheCertProvider(certFilepath string, keyFilepath string) *FileAvalancheCertProvider {
+	return &FileAvalancheCertProvider{certFilepath: certFilepath, keyFilepath: keyFilepath}
+}
+
+// GetCertAndKey returns the cert and key currently stored in the files
+func (f FileAvalancheCertProvider) GetCertAndKey() (certPemBytes bytes.Buffer, keyPemBytes bytes.Buffer, err error) {
+	certBytes, err := ioutil.ReadFile(f.certFilepath)
+	if err != nil {
+		return bytes.Buffer{}, bytes.Buffer{}, stacktrace.Propagate(err, "Failed to read cert file %v", f.certFilepath)
+	}
+	keyBytes, err := ioutil.ReadFile(f.keyFilepath)
+	if err != nil {
+		return bytes.Buffer{}, bytes.Buffer{}, stacktrace.Propagate(err, "Failed to read key file %v", f.keyFilepath)
+	}
+	return *bytes.NewBuffer(certBytes), *bytes.NewBuffer(keyBytes), nil
+}
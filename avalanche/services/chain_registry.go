@@ -0,0 +1,136 @@
+package services
+
+import (
+	"github.com/ava-labs/avalanche-testing/utils/addressing"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// AvaxAssetAlias is the alias of AVAX on the X Chain
+	AvaxAssetAlias = "AVAX"
+)
+
+// ChainRegistry holds the network ID, the IDs of the primary network's chains, the AVAX asset ID, and the blockchains
+// known to the P Chain of a running network. All of these depend on the genesis the network was started with, so they
+// are looked up from a node rather than hardcoded.
+type ChainRegistry struct {
+	networkID uint32
+
+	// Chain alias -> blockchain ID for the chains of the primary network
+	chainIDs map[string]ids.ID
+
+	avaxAssetID ids.ID
+
+	// The blockchains the P Chain reported, which includes every chain other than the P Chain itself
+	blockchains []platformvm.APIBlockchain
+}
+
+// NewChainRegistry builds a ChainRegistry from the node behind [client], which must have finished bootstrapping
+func NewChainRegistry(client *Client) (*ChainRegistry, error) {
+	networkID, err := client.InfoAPI().GetNetworkID()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get network ID.")
+	}
+
+	chainIDs := make(map[string]ids.ID, 3)
+	for _, alias := range []string{XChain, PChain, CChain} {
+		chainIDStr, err := client.InfoAPI().GetBlockchainID(alias)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to get the blockchain ID of the %s Chain.", alias)
+		}
+		chainID, err := ids.FromString(chainIDStr)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to parse the blockchain ID of the %s Chain: %s", alias, chainIDStr)
+		}
+		chainIDs[alias] = chainID
+	}
+
+	assetDescription, err := client.XChainAPI().GetAssetDescription(AvaxAssetAlias)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get the description of %s.", AvaxAssetAlias)
+	}
+
+	blockchains, err := client.PChainAPI().GetBlockchains()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to list the blockchains of the P Chain.")
+	}
+	// The P Chain doesn't list itself, but every other chain of the primary network must be known to it
+	for _, alias := range []string{XChain, CChain} {
+		if !containsBlockchain(blockchains, chainIDs[alias]) {
+			return nil, stacktrace.NewError("The P Chain does not know of the %s Chain with ID %s", alias, chainIDs[alias])
+		}
+	}
+
+	return &ChainRegistry{
+		networkID:   networkID,
+		chainIDs:    chainIDs,
+		avaxAssetID: assetDescription.AssetID,
+		blockchains: blockchains,
+	}, nil
+}
+
+// NetworkID returns the ID of the network
+func (registry *ChainRegistry) NetworkID() uint32 {
+	return registry.networkID
+}
+
+// AddressFormatter returns a formatter for bech32 addresses on the network
+func (registry *ChainRegistry) AddressFormatter() addressing.Formatter {
+	return addressing.NewFormatter(registry.networkID)
+}
+
+// XChainID returns the blockchain ID of the X Chain
+func (registry *ChainRegistry) XChainID() ids.ID {
+	return registry.chainIDs[XChain]
+}
+
+// PChainID returns the blockchain ID of the P Chain
+func (registry *ChainRegistry) PChainID() ids.ID {
+	return registry.chainIDs[PChain]
+}
+
+// CChainID returns the blockchain ID of the C Chain
+func (registry *ChainRegistry) CChainID() ids.ID {
+	return registry.chainIDs[CChain]
+}
+
+// ChainID returns the blockchain ID of the primary network chain with alias [alias]
+func (registry *ChainRegistry) ChainID(alias string) (ids.ID, error) {
+	chainID, ok := registry.chainIDs[alias]
+	if !ok {
+		return ids.ID{}, stacktrace.NewError("No chain with alias %s is registered", alias)
+	}
+	return chainID, nil
+}
+
+// AvaxAssetID returns the asset ID of AVAX
+func (registry *ChainRegistry) AvaxAssetID() ids.ID {
+	return registry.avaxAssetID
+}
+
+// Blockchains returns the blockchains the P Chain reported when the registry was built, which includes every chain
+// other than the P Chain itself
+func (registry *ChainRegistry) Blockchains() []platformvm.APIBlockchain {
+	return registry.blockchains
+}
+
+// BlockchainByName returns the blockchain the P Chain reported with name [name]
+func (registry *ChainRegistry) BlockchainByName(name string) (platformvm.APIBlockchain, error) {
+	for _, blockchain := range registry.blockchains {
+		if blockchain.Name == name {
+			return blockchain, nil
+		}
+	}
+	return platformvm.APIBlockchain{}, stacktrace.NewError("No blockchain named %s is registered", name)
+}
+
+func containsBlockchain(blockchains []platformvm.APIBlockchain, chainID ids.ID) bool {
+	for _, blockchain := range blockchains {
+		if blockchain.ID == chainID {
+			return true
+		}
+	}
+	return false
+}
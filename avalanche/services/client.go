@@ -2,56 +2,71 @@ package services
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/ava-labs/avalanche-testing/utils/addressing"
 	"github.com/ava-labs/avalanchego/api/admin"
 	"github.com/ava-labs/avalanchego/api/health"
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/api/ipcs"
 	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/ava-labs/coreth/ethclient"
 	"github.com/ava-labs/coreth/plugin/evm"
+	ethrpc "github.com/ava-labs/coreth/rpc"
+	"github.com/palantir/stacktrace"
 )
 
 // Chain names
 const (
-	XChain = "X"
-	CChain = "C"
+	XChain = addressing.XChain
+	PChain = addressing.PChain
+	CChain = addressing.CChain
 )
 
 // Client is a general client for avalanche
 type Client struct {
-	admin     *admin.Client
-	xChain    *avm.Client
-	health    *health.Client
-	info      *info.Client
-	ipcs      *ipcs.Client
-	keystore  *keystore.Client
-	platform  *platformvm.Client
-	cChain    *evm.Client
-	cChainEth *ethclient.Client
-}
-
-// NewClient returns a Client for interacting with the P Chain endpoint
-func NewClient(ipAddr string, port int, requestTimeout time.Duration) (*Client, error) {
+	admin    *admin.Client
+	xChain   *avm.Client
+	health   *health.Client
+	info     *info.Client
+	ipcs     *ipcs.Client
+	keystore *keystore.Client
+	platform *platformvm.Client
+	cChain   *evm.Client
+
+	// Used for the health API's getLiveness, whose reply the health client can't decode when checks are failing
+	healthRequester rpc.EndpointRequester
+
+	// The C Chain websocket is dialed on first use rather than in NewClient, so that a node with an unreachable
+	// websocket can still be used for every other API
+	cChainEthURI  string
+	cChainEthLock sync.Mutex
+	cChainEth     *ethclient.Client
+
+	// The C Chain's JSON-RPC endpoint over HTTP
+	cChainRPCURI string
+}
+
+// NewClient returns a Client for interacting with the APIs of the node at [ipAddr]:[port]
+func NewClient(ipAddr string, port int, requestTimeout time.Duration) *Client {
 	uri := fmt.Sprintf("http://%s:%d", ipAddr, port)
-	cClient, err := ethclient.Dial(fmt.Sprintf("ws://%s:%d/ext/bc/C/ws", ipAddr, port))
-	if err != nil {
-		return nil, err
-	}
 	return &Client{
-		admin:     admin.NewClient(uri, requestTimeout),
-		xChain:    avm.NewClient(uri, XChain, requestTimeout),
-		health:    health.NewClient(uri, requestTimeout),
-		info:      info.NewClient(uri, requestTimeout),
-		ipcs:      ipcs.NewClient(uri, requestTimeout),
-		keystore:  keystore.NewClient(uri, requestTimeout),
-		platform:  platformvm.NewClient(uri, requestTimeout),
-		cChain:    evm.NewCChainClient(uri, requestTimeout),
-		cChainEth: cClient,
-	}, nil
+		admin:           admin.NewClient(uri, requestTimeout),
+		xChain:          avm.NewClient(uri, XChain, requestTimeout),
+		health:          health.NewClient(uri, requestTimeout),
+		info:            info.NewClient(uri, requestTimeout),
+		ipcs:            ipcs.NewClient(uri, requestTimeout),
+		keystore:        keystore.NewClient(uri, requestTimeout),
+		platform:        platformvm.NewClient(uri, requestTimeout),
+		cChain:          evm.NewCChainClient(uri, requestTimeout),
+		healthRequester: rpc.NewEndpointRequester(uri, "/ext/health", "health", requestTimeout),
+		cChainEthURI:    fmt.Sprintf("ws://%s:%d/ext/bc/C/ws", ipAddr, port),
+		cChainRPCURI:    fmt.Sprintf("%s/ext/bc/C/rpc", uri),
+	}
 }
 
 // PChainAPI ...
@@ -69,9 +84,68 @@ func (c *Client) CChainAPI() *evm.Client {
 	return c.cChain
 }
 
-// CChainEthAPI ...
-func (c *Client) CChainEthAPI() *ethclient.Client {
-	return c.cChainEth
+// CChainEthAPI returns an ethclient connected to the C Chain websocket, dialing it if no connection is open
+func (c *Client) CChainEthAPI() (*ethclient.Client, error) {
+	c.cChainEthLock.Lock()
+	defer c.cChainEthLock.Unlock()
+
+	if c.cChainEth == nil {
+		if err := c.dialCChainEth(); err != nil {
+			return nil, err
+		}
+	}
+	return c.cChainEth, nil
+}
+
+// CChainEthRPC returns a JSON-RPC client of the C Chain's HTTP endpoint. Unlike the ethclient, it can call any method,
+// including those the ethclient lacks such as filters and pending state, and send batches of requests. It holds no
+// connection, so it needn't be closed.
+func (c *Client) CChainEthRPC() (*ethrpc.Client, error) {
+	client, err := ethrpc.DialHTTP(c.cChainRPCURI)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to create C Chain JSON-RPC client for %s", c.cChainRPCURI)
+	}
+	return client, nil
+}
+
+// ReconnectCChainEthAPI closes the C Chain websocket connection, if one is open, and dials a new one. This should be
+// used once a connection has been dropped, for example after the node restarted.
+func (c *Client) ReconnectCChainEthAPI() (*ethclient.Client, error) {
+	c.cChainEthLock.Lock()
+	defer c.cChainEthLock.Unlock()
+
+	c.closeCChainEth()
+	if err := c.dialCChainEth(); err != nil {
+		return nil, err
+	}
+	return c.cChainEth, nil
+}
+
+// Close releases the C Chain websocket connection, if one is open. The client remains usable, and the websocket is
+// dialed again the next time it is needed.
+func (c *Client) Close() {
+	c.cChainEthLock.Lock()
+	defer c.cChainEthLock.Unlock()
+
+	c.closeCChainEth()
+}
+
+// dialCChainEth assumes [cChainEthLock] is held
+func (c *Client) dialCChainEth() error {
+	cChainEth, err := ethclient.Dial(c.cChainEthURI)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to dial C Chain websocket at %s", c.cChainEthURI)
+	}
+	c.cChainEth = cChainEth
+	return nil
+}
+
+// closeCChainEth assumes [cChainEthLock] is held
+func (c *Client) closeCChainEth() {
+	if c.cChainEth != nil {
+		c.cChainEth.Close()
+		c.cChainEth = nil
+	}
 }
 
 // InfoAPI ...
@@ -98,3 +172,8 @@ func (c *Client) KeystoreAPI() *keystore.Client {
 func (c *Client) AdminAPI() *admin.Client {
 	return c.admin
 }
+
+// Admin returns typed wrappers for the admin operations of the node
+func (c *Client) Admin() NodeAdmin {
+	return NodeAdmin{client: c.admin}
+}
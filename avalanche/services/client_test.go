@@ -0,0 +1,27 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientDialsCChainWebsocketLazily(t *testing.T) {
+	// Nothing listens on port 1, so only the websocket dial can fail
+	client := NewClient("127.0.0.1", 1, time.Second)
+
+	_, err := client.CChainEthAPI()
+	assert.Error(t, err, "Expected an error dialing an unreachable C Chain websocket")
+	_, err = client.ReconnectCChainEthAPI()
+	assert.Error(t, err, "Expected an error redialing an unreachable C Chain websocket")
+
+	// Closing a client without an open connection must be a no-op
+	client.Close()
+	client.Close()
+}
+
+func TestCheckAdminReply(t *testing.T) {
+	assert.NoError(t, checkAdminReply("lockProfile", true, nil))
+	assert.Error(t, checkAdminReply("lockProfile", false, nil), "Expected an error for an unsuccessful reply")
+}
@@ -0,0 +1,690 @@
+package fakenode
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/utils/addressing"
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/coreth/plugin/evm"
+)
+
+// Endpoints the fake node serves, which are those the clients of services.Client send requests to
+const (
+	infoEndpoint     = "/ext/info"
+	keystoreEndpoint = "/ext/keystore"
+	xChainEndpoint   = "/ext/bc/X"
+	pChainEndpoint   = "/ext/P"
+	cChainEndpoint   = "/ext/bc/C/avax"
+)
+
+// JSON-RPC error code of failed methods, which is the code avalanchego's server replies with
+const serverErrorCode = -32000
+
+// Node is an in-process stand-in for an avalanchego node that serves the info, keystore, X Chain, P Chain and C Chain
+// JSON-RPC methods used by the test helpers, so that helpers can be unit tested without Docker. It keeps no ledger:
+// transactions don't move funds, and balances, peers and the statuses transactions go through are scripted by the test.
+//
+// Transactions issued through the fake node are accepted (X Chain) or committed (P Chain) the first time their status
+// is queried, unless a script of statuses was queued for them with ScriptNextXChainTx or ScriptNextPChainTx.
+type Node struct {
+	server *httptest.Server
+
+	lock sync.Mutex
+
+	nodeID    string
+	networkID uint32
+
+	// Chain alias -> blockchain ID
+	chainIDs    map[string]ids.ID
+	avaxAssetID ids.ID
+
+	peers []network.PeerID
+
+	// Username -> password of the keystore users
+	users map[string]string
+
+	// Address -> AVAX balance
+	xChainBalances map[string]uint64
+	pChainBalances map[string]uint64
+
+	// Tx ID -> statuses the tx reports, in order, to successive status queries. The last status is then repeated.
+	xChainTxs map[ids.ID][]choices.Status
+	pChainTxs map[ids.ID][]platformvm.Status
+
+	// Scripts of statuses for the next transactions issued on each chain, in the order they will be issued
+	nextXChainTxs [][]choices.Status
+	nextPChainTxs [][]platformvm.Status
+
+	// Full method name (e.g. avm.getTxStatus) -> message of the error the method fails with
+	failures map[string]string
+
+	// Full method name -> number of requests the method received
+	calls map[string]int
+
+	// Endpoint -> full method name -> handler
+	handlers map[string]map[string]handler
+}
+
+// handler replies to a JSON-RPC request with the given params
+type handler func(params json.RawMessage) (interface{}, error)
+
+// New starts a fake node listening on a local port, with random node and chain IDs, on the local network ID. The node
+// must be closed once the test is done with it.
+func New() *Node {
+	xChainID := ids.GenerateTestID()
+	cChainID := ids.GenerateTestID()
+	node := &Node{
+		nodeID:    ids.GenerateTestShortID().PrefixedString(constants.NodeIDPrefix),
+		networkID: constants.LocalID,
+		chainIDs: map[string]ids.ID{
+			services.XChain: xChainID,
+			services.PChain: constants.PlatformChainID,
+			services.CChain: cChainID,
+		},
+		avaxAssetID:    ids.GenerateTestID(),
+		peers:          make([]network.PeerID, 0),
+		users:          make(map[string]string),
+		xChainBalances: make(map[string]uint64),
+		pChainBalances: make(map[string]uint64),
+		xChainTxs:      make(map[ids.ID][]choices.Status),
+		pChainTxs:      make(map[ids.ID][]platformvm.Status),
+		failures:       make(map[string]string),
+		calls:          make(map[string]int),
+	}
+	node.handlers = map[string]map[string]handler{
+		infoEndpoint: {
+			"info.getNodeID":       node.getNodeID,
+			"info.getNetworkID":    node.getNetworkID,
+			"info.getBlockchainID": node.getBlockchainID,
+			"info.peers":           node.getPeers,
+			"info.isBootstrapped":  node.isBootstrapped,
+			"info.getNodeVersion":  node.getNodeVersion,
+			"info.getNetworkName":  node.getNetworkName,
+			"info.getTxFee":        node.getTxFee,
+		},
+		keystoreEndpoint: {
+			"keystore.createUser": node.createUser,
+			"keystore.listUsers":  node.listUsers,
+			"keystore.deleteUser": node.deleteUser,
+		},
+		xChainEndpoint: {
+			"avm.getTxStatus":         node.getXChainTxStatus,
+			"avm.getBalance":          node.getXChainBalance,
+			"avm.getAssetDescription": node.getAssetDescription,
+			"avm.createAddress":       node.createXChainAddress,
+			"avm.importKey":           node.importXChainKey,
+			"avm.issueTx":             node.issueXChainTx,
+			"avm.send":                node.issueXChainTx,
+			"avm.export":              node.issueXChainTx,
+			"avm.import":              node.issueXChainTx,
+		},
+		pChainEndpoint: {
+			"platform.getTxStatus":    node.getPChainTxStatus,
+			"platform.getBalance":     node.getPChainBalance,
+			"platform.getBlockchains": node.getBlockchains,
+			"platform.createAddress":  node.createPChainAddress,
+			"platform.importKey":      node.importPChainKey,
+			"platform.issueTx":        node.issuePChainTx,
+			"platform.addValidator":   node.issuePChainTx,
+			"platform.addDelegator":   node.issuePChainTx,
+			"platform.exportAVAX":     node.issuePChainTx,
+			"platform.importAVAX":     node.issuePChainTx,
+		},
+		cChainEndpoint: {
+			"avax.importKey": node.importCChainKey,
+			"avax.import":    node.issueCChainTx,
+			"avax.export":    node.issueCChainTx,
+		},
+	}
+	node.server = httptest.NewServer(http.HandlerFunc(node.serveHTTP))
+	return node
+}
+
+// Close shuts the fake node down
+func (node *Node) Close() {
+	node.server.Close()
+}
+
+// URI returns the base URI of the fake node's APIs
+func (node *Node) URI() string {
+	return node.server.URL
+}
+
+// Client returns a client for the APIs of the fake node
+func (node *Node) Client(requestTimeout time.Duration) *services.Client {
+	host, portStr, err := net.SplitHostPort(node.server.Listener.Addr().String())
+	if err != nil {
+		// The address comes from a listener httptest opened, so it's always a host and port
+		panic(err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		panic(err)
+	}
+	return services.NewClient(host, port, requestTimeout)
+}
+
+// NodeID returns the prefixed node ID the fake node reports
+func (node *Node) NodeID() string {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	return node.nodeID
+}
+
+// ChainID returns the blockchain ID the fake node reports for the chain with [alias]
+func (node *Node) ChainID(alias string) ids.ID {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	return node.chainIDs[alias]
+}
+
+// AvaxAssetID returns the ID the fake node reports for the AVAX asset
+func (node *Node) AvaxAssetID() ids.ID {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	return node.avaxAssetID
+}
+
+// SetPeers sets the peers the fake node reports to the nodes with [nodeIDs]
+func (node *Node) SetPeers(nodeIDs ...string) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	node.peers = make([]network.PeerID, 0, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		node.peers = append(node.peers, network.PeerID{
+			IP:      fmt.Sprintf("127.0.0.1:%d", 9651+2*i),
+			ID:      nodeID,
+			Version: "avalanche/1.0.5",
+		})
+	}
+}
+
+// SetXChainBalance sets the AVAX balance the fake node reports for X Chain address [address]
+func (node *Node) SetXChainBalance(address string, balance uint64) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	node.xChainBalances[address] = balance
+}
+
+// SetPChainBalance sets the AVAX balance the fake node reports for P Chain address [address]
+func (node *Node) SetPChainBalance(address string, balance uint64) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	node.pChainBalances[address] = balance
+}
+
+// AddXChainTx adds a transaction to the X Chain that reports [statuses] to successive status queries, repeating the
+// last status once they're exhausted, and returns its ID. Without statuses, the transaction is accepted.
+func (node *Node) AddXChainTx(statuses ...choices.Status) ids.ID {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	txID := ids.GenerateTestID()
+	node.xChainTxs[txID] = xChainScript(statuses)
+	return txID
+}
+
+// AddPChainTx adds a transaction to the P Chain that reports [statuses] to successive status queries, repeating the
+// last status once they're exhausted, and returns its ID. Without statuses, the transaction is committed.
+func (node *Node) AddPChainTx(statuses ...platformvm.Status) ids.ID {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	txID := ids.GenerateTestID()
+	node.pChainTxs[txID] = pChainScript(statuses)
+	return txID
+}
+
+// ScriptNextXChainTx makes the next transaction issued on the X Chain, after those already scripted, report
+// [statuses] to successive status queries
+func (node *Node) ScriptNextXChainTx(statuses ...choices.Status) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	node.nextXChainTxs = append(node.nextXChainTxs, xChainScript(statuses))
+}
+
+// ScriptNextPChainTx makes the next transaction issued on the P Chain, after those already scripted, report
+// [statuses] to successive status queries
+func (node *Node) ScriptNextPChainTx(statuses ...platformvm.Status) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	node.nextPChainTxs = append(node.nextPChainTxs, pChainScript(statuses))
+}
+
+// Fail makes every request to [method], which is the full name of a JSON-RPC method such as avm.getTxStatus, fail
+// with an error with [message]. An empty message makes the method succeed again.
+func (node *Node) Fail(method string, message string) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	if message == "" {
+		delete(node.failures, method)
+		return
+	}
+	node.failures[method] = message
+}
+
+// Calls returns the number of requests [method], which is the full name of a JSON-RPC method, has received
+func (node *Node) Calls(method string) int {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	return node.calls[method]
+}
+
+// jsonRPCRequest is a JSON-RPC 2.0 request as sent by avalanchego's clients
+type jsonRPCRequest struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	ID     json.RawMessage `json:"id"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonRPCResponse struct {
+	Version string        `json:"jsonrpc"`
+	Result  interface{}   `json:"result,omitempty"`
+	Error   *jsonRPCError `json:"error,omitempty"`
+	ID      interface{}   `json:"id"`
+}
+
+func (node *Node) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST requests are served", http.StatusMethodNotAllowed)
+		return
+	}
+	endpointHandlers, found := node.handlers[strings.TrimRight(r.URL.Path, "/")]
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	request := jsonRPCRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("couldn't decode request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	response := jsonRPCResponse{Version: "2.0", ID: request.ID}
+	result, err := node.handle(endpointHandlers, request)
+	if err != nil {
+		response.Error = &jsonRPCError{Code: serverErrorCode, Message: err.Error()}
+	} else {
+		response.Result = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, fmt.Sprintf("couldn't encode response: %s", err), http.StatusInternalServerError)
+	}
+}
+
+func (node *Node) handle(endpointHandlers map[string]handler, request jsonRPCRequest) (interface{}, error) {
+	node.lock.Lock()
+	node.calls[request.Method]++
+	failure, failing := node.failures[request.Method]
+	node.lock.Unlock()
+
+	if failing {
+		return nil, fmt.Errorf("%s", failure)
+	}
+	methodHandler, found := endpointHandlers[request.Method]
+	if !found {
+		return nil, fmt.Errorf("the method %s does not exist/is not available", request.Method)
+	}
+	return methodHandler(request.Params)
+}
+
+func (node *Node) getNodeID(json.RawMessage) (interface{}, error) {
+	return info.GetNodeIDReply{NodeID: node.NodeID()}, nil
+}
+
+func (node *Node) getNetworkID(json.RawMessage) (interface{}, error) {
+	return info.GetNetworkIDReply{NetworkID: cjson.Uint32(node.networkID)}, nil
+}
+
+func (node *Node) getNetworkName(json.RawMessage) (interface{}, error) {
+	return info.GetNetworkNameReply{NetworkName: constants.NetworkName(node.networkID)}, nil
+}
+
+func (node *Node) getNodeVersion(json.RawMessage) (interface{}, error) {
+	return info.GetNodeVersionReply{Version: "avalanche/1.0.5"}, nil
+}
+
+func (node *Node) getTxFee(json.RawMessage) (interface{}, error) {
+	return info.GetTxFeeResponse{
+		CreationTxFee: cjson.Uint64(units.MilliAvax),
+		TxFee:         cjson.Uint64(units.MilliAvax),
+	}, nil
+}
+
+func (node *Node) getBlockchainID(params json.RawMessage) (interface{}, error) {
+	args := info.GetBlockchainIDArgs{}
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, err
+	}
+	chainID, found := node.lookupChain(args.Alias)
+	if !found {
+		return nil, fmt.Errorf("there is no chain with alias/ID '%s'", args.Alias)
+	}
+	return info.GetBlockchainIDReply{BlockchainID: chainID.String()}, nil
+}
+
+func (node *Node) getPeers(json.RawMessage) (interface{}, error) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	return info.PeersReply{
+		NumPeers: cjson.Uint64(len(node.peers)),
+		Peers:    node.peers,
+	}, nil
+}
+
+func (node *Node) isBootstrapped(params json.RawMessage) (interface{}, error) {
+	args := info.IsBootstrappedArgs{}
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, err
+	}
+	if _, found := node.lookupChain(args.Chain); !found {
+		return nil, fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+	}
+	return info.IsBootstrappedResponse{IsBootstrapped: true}, nil
+}
+
+func (node *Node) createUser(params json.RawMessage) (interface{}, error) {
+	user := api.UserPass{}
+	if err := json.Unmarshal(params, &user); err != nil {
+		return nil, err
+	}
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	if _, found := node.users[user.Username]; found {
+		return nil, fmt.Errorf("user already exists: %s", user.Username)
+	}
+	node.users[user.Username] = user.Password
+	return api.SuccessResponse{Success: true}, nil
+}
+
+func (node *Node) listUsers(json.RawMessage) (interface{}, error) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	users := make([]string, 0, len(node.users))
+	for username := range node.users {
+		users = append(users, username)
+	}
+	return keystore.ListUsersReply{Users: users}, nil
+}
+
+func (node *Node) deleteUser(params json.RawMessage) (interface{}, error) {
+	user := api.UserPass{}
+	if err := json.Unmarshal(params, &user); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(user); err != nil {
+		return nil, err
+	}
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	delete(node.users, user.Username)
+	return api.SuccessResponse{Success: true}, nil
+}
+
+func (node *Node) getXChainTxStatus(params json.RawMessage) (interface{}, error) {
+	args := api.JSONTxID{}
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, err
+	}
+	if args.TxID == ids.Empty {
+		return nil, fmt.Errorf("transaction ID is empty")
+	}
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	statuses, found := node.xChainTxs[args.TxID]
+	if !found {
+		return avm.GetTxStatusReply{Status: choices.Unknown}, nil
+	}
+	if len(statuses) > 1 {
+		node.xChainTxs[args.TxID] = statuses[1:]
+	}
+	return avm.GetTxStatusReply{Status: statuses[0]}, nil
+}
+
+func (node *Node) getXChainBalance(params json.RawMessage) (interface{}, error) {
+	args := avm.GetBalanceArgs{}
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, err
+	}
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	if args.AssetID != services.AvaxAssetAlias && args.AssetID != node.avaxAssetID.String() {
+		// Only AVAX balances are scripted, so the fake node holds none of any other asset
+		return avm.GetBalanceReply{UTXOIDs: []avax.UTXOID{}}, nil
+	}
+	return avm.GetBalanceReply{
+		Balance: cjson.Uint64(node.xChainBalances[args.Address]),
+		UTXOIDs: []avax.UTXOID{},
+	}, nil
+}
+
+func (node *Node) getAssetDescription(params json.RawMessage) (interface{}, error) {
+	args := avm.GetAssetDescriptionArgs{}
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, err
+	}
+	avaxAssetID := node.AvaxAssetID()
+	if args.AssetID != services.AvaxAssetAlias && args.AssetID != avaxAssetID.String() {
+		return nil, fmt.Errorf("couldn't find asset %s", args.AssetID)
+	}
+	return avm.GetAssetDescriptionReply{
+		FormattedAssetID: avm.FormattedAssetID{AssetID: avaxAssetID},
+		Name:             "Avalanche",
+		Symbol:           services.AvaxAssetAlias,
+		Denomination:     9,
+	}, nil
+}
+
+func (node *Node) createXChainAddress(params json.RawMessage) (interface{}, error) {
+	return node.createAddress(params, services.XChain)
+}
+
+func (node *Node) importXChainKey(params json.RawMessage) (interface{}, error) {
+	return node.createAddress(params, services.XChain)
+}
+
+func (node *Node) issueXChainTx(json.RawMessage) (interface{}, error) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	statuses := xChainScript(nil)
+	if len(node.nextXChainTxs) > 0 {
+		statuses = node.nextXChainTxs[0]
+		node.nextXChainTxs = node.nextXChainTxs[1:]
+	}
+	txID := ids.GenerateTestID()
+	node.xChainTxs[txID] = statuses
+	return api.JSONTxID{TxID: txID}, nil
+}
+
+func (node *Node) getPChainTxStatus(params json.RawMessage) (interface{}, error) {
+	args := platformvm.GetTxStatusArgs{}
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, err
+	}
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	statuses, found := node.pChainTxs[args.TxID]
+	if !found {
+		return platformvm.GetTxStatusResponse{Status: platformvm.Unknown}, nil
+	}
+	if len(statuses) > 1 {
+		node.pChainTxs[args.TxID] = statuses[1:]
+	}
+	response := platformvm.GetTxStatusResponse{Status: statuses[0]}
+	if args.IncludeReason && statuses[0] == platformvm.Dropped {
+		response.Reason = "dropped by the fake node's script"
+	}
+	return response, nil
+}
+
+func (node *Node) getPChainBalance(params json.RawMessage) (interface{}, error) {
+	args := api.JSONAddress{}
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, err
+	}
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	balance := cjson.Uint64(node.pChainBalances[args.Address])
+	return platformvm.GetBalanceResponse{
+		Balance:  balance,
+		Unlocked: balance,
+		UTXOIDs:  []*avax.UTXOID{},
+	}, nil
+}
+
+func (node *Node) getBlockchains(json.RawMessage) (interface{}, error) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	// Like a real P Chain, the fake one lists every chain of the primary network but itself
+	return platformvm.GetBlockchainsResponse{
+		Blockchains: []platformvm.APIBlockchain{
+			{
+				ID:       node.chainIDs[services.XChain],
+				Name:     services.XChain,
+				SubnetID: constants.PrimaryNetworkID,
+				VMID:     avm.ID,
+			},
+			{
+				ID:       node.chainIDs[services.CChain],
+				Name:     services.CChain,
+				SubnetID: constants.PrimaryNetworkID,
+				VMID:     evm.ID,
+			},
+		},
+	}, nil
+}
+
+func (node *Node) createPChainAddress(params json.RawMessage) (interface{}, error) {
+	return node.createAddress(params, services.PChain)
+}
+
+func (node *Node) importPChainKey(params json.RawMessage) (interface{}, error) {
+	return node.createAddress(params, services.PChain)
+}
+
+func (node *Node) issuePChainTx(json.RawMessage) (interface{}, error) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	statuses := pChainScript(nil)
+	if len(node.nextPChainTxs) > 0 {
+		statuses = node.nextPChainTxs[0]
+		node.nextPChainTxs = node.nextPChainTxs[1:]
+	}
+	txID := ids.GenerateTestID()
+	node.pChainTxs[txID] = statuses
+	return api.JSONTxID{TxID: txID}, nil
+}
+
+func (node *Node) importCChainKey(params json.RawMessage) (interface{}, error) {
+	return node.createAddress(params, services.CChain)
+}
+
+func (node *Node) issueCChainTx(json.RawMessage) (interface{}, error) {
+	// The C Chain has no status API for atomic transactions, so they aren't tracked
+	return api.JSONTxID{TxID: ids.GenerateTestID()}, nil
+}
+
+// createAddress authenticates the keystore user in [params] and returns a new bech32 address on [chainAlias]. Imported
+// keys are given new addresses too, since the fake node doesn't derive addresses from keys.
+func (node *Node) createAddress(params json.RawMessage, chainAlias string) (interface{}, error) {
+	user := api.UserPass{}
+	if err := json.Unmarshal(params, &user); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(user); err != nil {
+		return nil, err
+	}
+	address, err := addressing.NewFormatter(node.networkID).FormatBech32(chainAlias, ids.GenerateTestShortID())
+	if err != nil {
+		return nil, err
+	}
+	return api.JSONAddress{Address: address}, nil
+}
+
+func (node *Node) authenticate(user api.UserPass) error {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	password, found := node.users[user.Username]
+	if !found || password != user.Password {
+		return fmt.Errorf("incorrect password for user %q", user.Username)
+	}
+	return nil
+}
+
+// lookupChain returns the ID of the chain with alias or ID [chain]
+func (node *Node) lookupChain(chain string) (ids.ID, bool) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	if chainID, found := node.chainIDs[chain]; found {
+		return chainID, true
+	}
+	for _, chainID := range node.chainIDs {
+		if chainID.String() == chain {
+			return chainID, true
+		}
+	}
+	return ids.ID{}, false
+}
+
+func xChainScript(statuses []choices.Status) []choices.Status {
+	if len(statuses) == 0 {
+		return []choices.Status{choices.Accepted}
+	}
+	return append([]choices.Status(nil), statuses...)
+}
+
+func pChainScript(statuses []platformvm.Status) []platformvm.Status {
+	if len(statuses) == 0 {
+		return []platformvm.Status{platformvm.Committed}
+	}
+	return append([]platformvm.Status(nil), statuses...)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+// LivenessReply is the reply of the health API's getLiveness. Unlike the health client's reply, it can be decoded when
+// checks are failing, since failing checks report errors that don't decode into an error interface.
+type LivenessReply struct {
+	Checks  map[string]CheckResult `json:"checks"`
+	Healthy bool                   `json:"healthy"`
+}
+
+// CheckResult is the result of a single health check of a node
+type CheckResult struct {
+	Details            json.RawMessage `json:"message,omitempty"`
+	Error              json.RawMessage `json:"error,omitempty"`
+	Timestamp          time.Time       `json:"timestamp"`
+	ContiguousFailures int64           `json:"contiguousFailures"`
+	TimeOfFirstFailure *time.Time      `json:"timeOfFirstFailure"`
+}
+
+// Healthy returns whether the check passed
+func (result CheckResult) Healthy() bool {
+	return result.ContiguousFailures == 0 && isEmptyJSON(result.Error)
+}
+
+// String describes why the check failed, or returns "healthy" if it passed
+func (result CheckResult) String() string {
+	if result.Healthy() {
+		return "healthy"
+	}
+	if !isEmptyJSON(result.Error) {
+		return fmt.Sprintf("error %s after %d failures", result.Error, result.ContiguousFailures)
+	}
+	return fmt.Sprintf("details %s after %d failures", result.Details, result.ContiguousFailures)
+}
+
+// FailingChecks returns the name and result of every failing check
+func (reply LivenessReply) FailingChecks() map[string]CheckResult {
+	failing := make(map[string]CheckResult)
+	for name, result := range reply.Checks {
+		if !result.Healthy() {
+			failing[name] = result
+		}
+	}
+	return failing
+}
+
+// Liveness returns the result of every health check of the node
+func (c *Client) Liveness() (*LivenessReply, error) {
+	reply := &LivenessReply{}
+	if err := c.healthRequester.SendRequest("getLiveness", struct{}{}, reply); err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get liveness")
+	}
+	return reply, nil
+}
+
+func isEmptyJSON(raw json.RawMessage) bool {
+	switch string(raw) {
+	case "", "null", "{}", `""`:
+		return true
+	default:
+		return false
+	}
+}
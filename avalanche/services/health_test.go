@@ -0,0 +1,30 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLivenessReplyFailingChecks(t *testing.T) {
+	// Errors of failing checks are encoded from an error interface, so they may carry no fields at all
+	replyJSON := `{
+		"checks": {
+			"chains.default.bootstrapped": {"message": [], "timestamp": "2020-10-01T00:00:00Z", "contiguousFailures": 0},
+			"network.validators.heartbeat": {"message": {"heartbeat": 0}, "error": {}, "timestamp": "2020-10-01T00:00:00Z", "contiguousFailures": 3, "timeOfFirstFailure": "2020-09-30T23:59:00Z"}
+		},
+		"healthy": false
+	}`
+	reply := LivenessReply{}
+	assert.NoError(t, json.Unmarshal([]byte(replyJSON), &reply))
+	assert.False(t, reply.Healthy)
+
+	failing := reply.FailingChecks()
+	assert.Len(t, failing, 1)
+	result, ok := failing["network.validators.heartbeat"]
+	assert.True(t, ok, "Expected the heartbeat check to be failing")
+	assert.Equal(t, int64(3), result.ContiguousFailures)
+	assert.NotNil(t, result.TimeOfFirstFailure)
+	assert.Contains(t, result.String(), "after 3 failures")
+}
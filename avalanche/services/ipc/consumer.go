@@ -0,0 +1,280 @@
+package ipc
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/ipcs/socket"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Number of containers a subscriber can fall behind by before containers are dropped for it
+	subscriberBufferSize = 4096
+)
+
+// Consumer reads the containers a single node accepts from the node's IPC sockets. It records when each container was
+// first accepted, so tests can wait for acceptance without polling and measure acceptance latency.
+//
+// Containers are read as soon as the node publishes them: a node blocks on writing to its IPC sockets, so reading must
+// never wait on a slow subscriber.
+type Consumer struct {
+	client   *services.Client
+	registry *services.ChainRegistry
+
+	// Where the node creates its IPC sockets, as seen from the test suite container
+	workingDirpath string
+
+	lock sync.Mutex
+
+	// Set once Close is called, after which read errors are expected
+	closed bool
+
+	// Alias of each chain published by this consumer -> the connections to its sockets
+	published map[string][]*socket.Client
+
+	// Container ID -> the first time the container was accepted on any published chain
+	accepted map[ids.ID]AcceptedContainer
+
+	// Container ID -> channels waiting for the container to be accepted
+	waiters map[ids.ID][]chan AcceptedContainer
+
+	subscribers []chan AcceptedContainer
+
+	readers sync.WaitGroup
+}
+
+// NewConsumer creates a Consumer for the node behind [client], which must have been started with its files exposed at
+// [workingDirpath] on the test suite container. No chain is read until it's published with Publish.
+func NewConsumer(client *services.Client, registry *services.ChainRegistry, workingDirpath string) *Consumer {
+	return &Consumer{
+		client:         client,
+		registry:       registry,
+		workingDirpath: workingDirpath,
+		published:      make(map[string][]*socket.Client),
+		accepted:       make(map[ids.ID]AcceptedContainer),
+		waiters:        make(map[ids.ID][]chan AcceptedContainer),
+	}
+}
+
+// Publish asks the node to publish the chain with alias [chainAlias] over IPC and starts reading its consensus and
+// decisions sockets. Containers accepted before Publish returns may be missed.
+func (c *Consumer) Publish(chainAlias string) error {
+	chainID, err := c.registry.ChainID(chainAlias)
+	if err != nil {
+		return err
+	}
+	reply, err := c.client.IpcsAPI().PublishBlockchain(chainID.String())
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to publish the %s Chain over IPC", chainAlias)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.closed {
+		return stacktrace.NewError("Consumer is closed")
+	}
+	if _, ok := c.published[chainAlias]; ok {
+		return nil
+	}
+	socketURLs := map[EventType]string{
+		Consensus: reply.ConsensusURL,
+		Decision:  reply.DecisionsURL,
+	}
+	conns := make([]*socket.Client, 0, len(socketURLs))
+	for eventType, socketURL := range socketURLs {
+		// The node is started with its IPC sockets relative to its working directory
+		conn, err := dialSocket(filepath.Join(c.workingDirpath, socketURL))
+		if err != nil {
+			for _, conn := range conns {
+				conn.Close()
+			}
+			return stacktrace.Propagate(err, "Failed to connect to the %s socket of the %s Chain", eventType, chainAlias)
+		}
+		conns = append(conns, conn)
+		c.readers.Add(1)
+		go c.read(chainAlias, eventType, conn)
+	}
+	c.published[chainAlias] = conns
+	logrus.Debugf("Reading accepted containers of the %s Chain over IPC.", chainAlias)
+	return nil
+}
+
+// Subscribe returns a channel that receives every container accepted on a published chain after the call. The channel
+// is closed by Close. If the subscriber falls more than subscriberBufferSize containers behind, further containers are
+// dropped for it rather than holding up the node.
+func (c *Consumer) Subscribe() <-chan AcceptedContainer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	subscriber := make(chan AcceptedContainer, subscriberBufferSize)
+	if c.closed {
+		close(subscriber)
+		return subscriber
+	}
+	c.subscribers = append(c.subscribers, subscriber)
+	return subscriber
+}
+
+// AwaitAcceptance waits up to [timeout] for the container with ID [containerID] to be accepted on a published chain,
+// returning immediately if it already was
+func (c *Consumer) AwaitAcceptance(containerID ids.ID, timeout time.Duration) (AcceptedContainer, error) {
+	c.lock.Lock()
+	if container, ok := c.accepted[containerID]; ok {
+		c.lock.Unlock()
+		return container, nil
+	}
+	waiter := make(chan AcceptedContainer, 1)
+	c.waiters[containerID] = append(c.waiters[containerID], waiter)
+	c.lock.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case container := <-waiter:
+		return container, nil
+	case <-timer.C:
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	// The container may have been accepted since the timer fired, in which case the waiter was already removed
+	select {
+	case container := <-waiter:
+		return container, nil
+	default:
+	}
+	c.removeWaiter(containerID, waiter)
+	return AcceptedContainer{}, stacktrace.NewError("Timed out waiting %v for container %s to be accepted", timeout, containerID)
+}
+
+// removeWaiter stops [waiter] waiting for the container with ID [containerID]. It assumes [lock] is held.
+func (c *Consumer) removeWaiter(containerID ids.ID, waiter chan AcceptedContainer) {
+	waiters := c.waiters[containerID]
+	for i, other := range waiters {
+		if other == waiter {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(c.waiters, containerID)
+		return
+	}
+	c.waiters[containerID] = waiters
+}
+
+// Accepted returns the first acceptance of the container with ID [containerID], and whether it has been accepted
+func (c *Consumer) Accepted(containerID ids.ID) (AcceptedContainer, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	container, ok := c.accepted[containerID]
+	return container, ok
+}
+
+// Close stops publishing every chain published by this consumer, closes the sockets, and closes the channels returned
+// by Subscribe. Every chain is attempted even if an earlier one fails, and the first error is returned.
+func (c *Consumer) Close() error {
+	c.lock.Lock()
+	if c.closed {
+		c.lock.Unlock()
+		return nil
+	}
+	c.closed = true
+	published := c.published
+	c.published = nil
+	c.lock.Unlock()
+
+	var firstErr error
+	for chainAlias, conns := range published {
+		for _, conn := range conns {
+			conn.Close()
+		}
+		if err := c.unpublish(chainAlias); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	c.readers.Wait()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, subscriber := range c.subscribers {
+		close(subscriber)
+	}
+	c.subscribers = nil
+	return firstErr
+}
+
+func (c *Consumer) unpublish(chainAlias string) error {
+	chainID, err := c.registry.ChainID(chainAlias)
+	if err != nil {
+		return err
+	}
+	success, err := c.client.IpcsAPI().UnpublishBlockchain(chainID.String())
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to unpublish the %s Chain", chainAlias)
+	}
+	if !success {
+		return stacktrace.NewError("The %s Chain was not published", chainAlias)
+	}
+	return nil
+}
+
+// read dispatches every container read from [conn] until the connection is closed
+func (c *Consumer) read(chainAlias string, eventType EventType, conn *socket.Client) {
+	defer c.readers.Done()
+
+	for {
+		containerBytes, err := conn.Recv()
+		acceptedAt := time.Now()
+		if err != nil {
+			c.lock.Lock()
+			closed := c.closed
+			c.lock.Unlock()
+			if !closed {
+				logrus.Errorf("Stopped reading %s socket of the %s Chain: %v", eventType, chainAlias, err)
+			}
+			return
+		}
+
+		containerID, err := ContainerID(chainAlias, containerBytes)
+		if err != nil {
+			logrus.Errorf("Failed to decode container from %s socket of the %s Chain: %v", eventType, chainAlias, err)
+			continue
+		}
+		c.dispatch(AcceptedContainer{
+			ChainAlias:  chainAlias,
+			EventType:   eventType,
+			ContainerID: containerID,
+			Bytes:       containerBytes,
+			AcceptedAt:  acceptedAt,
+		})
+	}
+}
+
+func (c *Consumer) dispatch(container AcceptedContainer) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.accepted[container.ContainerID]; !ok {
+		c.accepted[container.ContainerID] = container
+	}
+	for _, waiter := range c.waiters[container.ContainerID] {
+		waiter <- container
+	}
+	delete(c.waiters, container.ContainerID)
+
+	for _, subscriber := range c.subscribers {
+		select {
+		case subscriber <- container:
+		default:
+			logrus.Warnf("Dropped container %s for a subscriber that fell behind.", container.ContainerID)
+		}
+	}
+}
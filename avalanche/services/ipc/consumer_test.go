@@ -0,0 +1,104 @@
+package ipc
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/ipcs/socket"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	socketFilename = "12345-chain-decisions"
+)
+
+func TestConsumerDispatchesAcceptedContainers(t *testing.T) {
+	tempDirpath, err := ioutil.TempDir("", "consumer-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDirpath)
+
+	// The node listens at a short path, but the consumer sees it at a path that's too long to dial directly, like paths
+	// on the suite execution volume
+	nodeDirpath := filepath.Join(tempDirpath, "node")
+	assert.NoError(t, os.Mkdir(nodeDirpath, 0755))
+	node := socket.NewSocket(filepath.Join(nodeDirpath, socketFilename), logging.NoLog{})
+	assert.NoError(t, node.Listen())
+	longDirpath := filepath.Join(tempDirpath, strings.Repeat("d", 60))
+	assert.NoError(t, os.Mkdir(longDirpath, 0755))
+	socketDirpath := filepath.Join(longDirpath, strings.Repeat("d", 60))
+	assert.NoError(t, os.Symlink(nodeDirpath, socketDirpath))
+	socketPath := filepath.Join(socketDirpath, socketFilename)
+	assert.True(t, len(socketPath) > maxSocketPathLength)
+
+	consumer := NewConsumer(nil, nil, socketDirpath)
+	subscriber := consumer.Subscribe()
+	conn, err := dialSocket(socketPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	consumer.readers.Add(1)
+	go consumer.read(services.XChain, Decision, conn)
+
+	// The node only sends to connections it has accepted, which happens asynchronously, so resend until one arrives
+	firstTx := []byte("first tx")
+	firstTxID := ids.ID(hashing.ComputeHash256Array(firstTx))
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, node.Send(firstTx))
+		if _, ok := consumer.Accepted(firstTxID); ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	accepted, err := consumer.AwaitAcceptance(firstTxID, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, services.XChain, accepted.ChainAlias)
+	assert.Equal(t, Decision, accepted.EventType)
+	assert.Equal(t, firstTx, accepted.Bytes)
+	assert.Equal(t, firstTxID, (<-subscriber).ContainerID)
+
+	secondTx := []byte("second tx")
+	secondTxID := ids.ID(hashing.ComputeHash256Array(secondTx))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		node.Send(secondTx)
+	}()
+	accepted, err = consumer.AwaitAcceptance(secondTxID, 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, secondTxID, accepted.ContainerID)
+
+	_, err = consumer.AwaitAcceptance(ids.GenerateTestID(), 10*time.Millisecond)
+	assert.Error(t, err, "Expected a timeout waiting for a container that was never accepted")
+	consumer.lock.Lock()
+	assert.Empty(t, consumer.waiters, "Expected the waiter to be removed once it timed out")
+	consumer.lock.Unlock()
+
+	consumer.lock.Lock()
+	consumer.closed = true
+	consumer.lock.Unlock()
+	conn.Close()
+	consumer.readers.Wait()
+}
+
+func TestCChainContainerID(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	blockBytes, err := rlp.EncodeToBytes(block)
+	assert.NoError(t, err)
+
+	containerID, err := ContainerID(services.CChain, blockBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, ids.ID(block.Hash()), containerID)
+
+	_, err = ContainerID(services.CChain, []byte("not a block"))
+	assert.Error(t, err, "Expected an error decoding invalid C Chain block bytes")
+}
@@ -0,0 +1,50 @@
+package ipc
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/palantir/stacktrace"
+)
+
+// EventType is the kind of IPC socket a container was published on
+type EventType string
+
+// Event types
+const (
+	// Consensus sockets publish the containers accepted by the consensus engine: vertices on the X Chain, and blocks on
+	// the P and C Chains
+	Consensus EventType = "consensus"
+
+	// Decision sockets publish accepted decisions: transactions on the X Chain, and blocks on the P and C Chains
+	Decision EventType = "decisions"
+)
+
+// AcceptedContainer is a container that a node published on one of its IPC sockets after accepting it
+type AcceptedContainer struct {
+	ChainAlias  string
+	EventType   EventType
+	ContainerID ids.ID
+	Bytes       []byte
+
+	// When the container was read from the socket, which the node writes to synchronously on acceptance
+	AcceptedAt time.Time
+}
+
+// ContainerID returns the ID of the container [containerBytes] published by the chain with alias [chainAlias]
+func ContainerID(chainAlias string, containerBytes []byte) (ids.ID, error) {
+	if chainAlias != services.CChain {
+		// Vertices, transactions and P Chain blocks are identified by the hash of their bytes
+		return hashing.ComputeHash256Array(containerBytes), nil
+	}
+	// C Chain blocks are RLP encoded Ethereum blocks, identified by their Ethereum block hash
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(containerBytes, block); err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to decode C Chain block")
+	}
+	return ids.ID(block.Hash()), nil
+}
@@ -0,0 +1,39 @@
+package ipc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/ipcs/socket"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// Longest path that a unix socket can be dialed at on Linux, excluding the null terminator
+	maxSocketPathLength = 107
+)
+
+// dialSocket connects to the IPC socket at [socketPath]. Paths on the suite execution volume can be too long to dial
+// directly, in which case the socket is dialed through a short-lived symlink to its directory.
+func dialSocket(socketPath string) (*socket.Client, error) {
+	if len(socketPath) <= maxSocketPathLength {
+		return socket.Dial(socketPath)
+	}
+
+	tempDirpath, err := ioutil.TempDir("", "ipc")
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to create directory for socket symlink")
+	}
+	defer os.RemoveAll(tempDirpath)
+
+	symlinkPath := filepath.Join(tempDirpath, "s")
+	if err := os.Symlink(filepath.Dir(socketPath), symlinkPath); err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to symlink socket directory of %s", socketPath)
+	}
+	shortPath := filepath.Join(symlinkPath, filepath.Base(socketPath))
+	if len(shortPath) > maxSocketPathLength {
+		return nil, stacktrace.NewError("Socket path %s is too long to dial, even through symlink %s", socketPath, shortPath)
+	}
+	return socket.Dial(shortPath)
+}
@@ -35,6 +35,16 @@ type AvalancheService struct {
 	jsonRPCPort int
 }
 
+// NewAvalancheService returns the service of the node at [ipAddr] serving its APIs on [jsonRPCPort] and staking
+// connections on [stakingPort]
+func NewAvalancheService(ipAddr string, jsonRPCPort int, stakingPort int) AvalancheService {
+	return AvalancheService{
+		ipAddr:      ipAddr,
+		stakingPort: stakingPort,
+		jsonRPCPort: jsonRPCPort,
+	}
+}
+
 // GetStakingSocket implements AvalancheService
 func (service AvalancheService) GetStakingSocket() ServiceSocket {
 	return *NewServiceSocket(service.ipAddr, service.stakingPort)
@@ -3,12 +3,14 @@ package services
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/kurtosis-tech/kurtosis-go/lib/services"
 
 	"github.com/ava-labs/avalanche-testing/avalanche/services/certs"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
 )
@@ -27,15 +29,61 @@ const (
 // AvalancheLogLevel specifies the log level for an Avalanche client
 type AvalancheLogLevel string
 
-// Log levels
+// Log levels, from the most to the least verbose
 const (
 	VERBOSE AvalancheLogLevel = "verbo"
 	DEBUG   AvalancheLogLevel = "debug"
 	INFO    AvalancheLogLevel = "info"
+	WARN    AvalancheLogLevel = "warn"
+	ERROR   AvalancheLogLevel = "error"
+	FATAL   AvalancheLogLevel = "fatal"
+	OFF     AvalancheLogLevel = "off"
 )
 
+var allAvalancheLogLevels = []AvalancheLogLevel{VERBOSE, DEBUG, INFO, WARN, ERROR, FATAL, OFF}
+
+// ParseAvalancheLogLevel returns the log level named [str], ignoring case
+func ParseAvalancheLogLevel(str string) (AvalancheLogLevel, error) {
+	for _, level := range allAvalancheLogLevels {
+		if strings.EqualFold(str, string(level)) {
+			return level, nil
+		}
+	}
+	return "", stacktrace.NewError("Unknown Avalanche log level '%s', expected one of %v", str, allAvalancheLogLevels)
+}
+
+// NodeLogLevels are the levels a node logs at. Levels left empty are not passed to the node.
+type NodeLogLevels struct {
+	// Level of the lines the node writes to its log files
+	File AvalancheLogLevel
+
+	// Level of the lines the node writes to its output, which defaults to the level of its log files
+	Display AvalancheLogLevel
+}
+
+// NodeLaunch describes where a node is launched from and the addresses it's reached at, which depend on the backend
+// executing the node
+type NodeLaunch struct {
+	// Path of the avalanchego binary, as seen by the node
+	BinaryPath string
+
+	// IP that the node advertises to its peers
+	PublicIP string
+
+	// Ports the node serves its APIs and staking connections on
+	HTTPPort    int
+	StakingPort int
+
+	// Directories that the node keeps its database in and loads VM plugins from, or empty for avalanchego's defaults
+	DBDirpath     string
+	PluginDirpath string
+}
+
 // AvalancheServiceInitializerCore implements Kurtosis' services.ServiceInitializerCore used to initialize an Avalanche service
 type AvalancheServiceInitializerCore struct {
+	// The ID of the network the node should join
+	networkID uint32
+
 	// Snow protocol sample size
 	snowSampleSize int
 
@@ -64,10 +112,24 @@ type AvalancheServiceInitializerCore struct {
 
 	// Log level that the Avalanche service should start with
 	logLevel AvalancheLogLevel
+
+	// Log level of the lines the Avalanche service writes to its output, or empty to use [logLevel]
+	displayLogLevel AvalancheLogLevel
+
+	// Whether the node should run in its own working directory on the test volume with the IPC API enabled, so that the
+	// test suite can collect the profiles its admin API writes and connect to its IPC sockets
+	exposeNodeFiles bool
+
+	// The network snapshot, and the node in it, that the node is restored from, or empty to start the node from an
+	// empty database
+	restoreSnapshotName string
+	restoreNodeName     string
 }
 
 // NewAvalancheServiceInitializerCore creates a new Avalanche service initializer core with the following parameters:
 // Args:
+// 		networkID: The ID of the network the node will join. Well known network IDs are passed by name (e.g. local), and
+// 			any other network ID starts the node with the local genesis under that network ID
 // 		snowSampleSize: Sample size for Snow consensus protocol
 // 		snowQuroumSize: Quorum size for Snow consensus protocol
 // 		stakingEnabled: Whether this node will use staking
@@ -77,9 +139,13 @@ type AvalancheServiceInitializerCore struct {
 // 			the user is required to manually specify the node IDs of the nodese it's connecting to.
 // 		certProvider: Provides the certs used by the Avalanche services generated by this core
 // 		logLevel: The loglevel that the Avalanche node should output at.
+// 		displayLogLevel: The loglevel of the lines the Avalanche node writes to its output, or empty to use logLevel
+// 		exposeNodeFiles: Whether the node runs in a working directory on the test volume, found with NodeWorkingDirpath,
+// 			that holds the profiles written by its admin API, the sockets of its IPC API, and its log files
 // Returns:
 // 		An intializer core for creating Avalanche nodes with the specified parameers.
 func NewAvalancheServiceInitializerCore(
+	networkID uint32,
 	snowSampleSize int,
 	snowQuorumSize int,
 	txFee uint64,
@@ -88,7 +154,9 @@ func NewAvalancheServiceInitializerCore(
 	additionalCLIArgs map[string]string,
 	bootstrapperNodeIDs []string,
 	certProvider certs.AvalancheCertProvider,
-	logLevel AvalancheLogLevel) *AvalancheServiceInitializerCore {
+	logLevel AvalancheLogLevel,
+	displayLogLevel AvalancheLogLevel,
+	exposeNodeFiles bool) *AvalancheServiceInitializerCore {
 	// Defensive copy
 	bootstrapperIDsCopy := make([]string, 0, len(bootstrapperNodeIDs))
 	for _, nodeID := range bootstrapperNodeIDs {
@@ -96,6 +164,7 @@ func NewAvalancheServiceInitializerCore(
 	}
 
 	return &AvalancheServiceInitializerCore{
+		networkID:             networkID,
 		snowSampleSize:        snowSampleSize,
 		snowQuorumSize:        snowQuorumSize,
 		txFee:                 txFee,
@@ -105,7 +174,32 @@ func NewAvalancheServiceInitializerCore(
 		bootstrapperNodeIDs:   bootstrapperIDsCopy,
 		certProvider:          certProvider,
 		logLevel:              logLevel,
+		displayLogLevel:       displayLogLevel,
+		exposeNodeFiles:       exposeNodeFiles,
+	}
+}
+
+// RestoredFrom returns a copy of the core whose nodes start from the database and staking cert and key of the node
+// named [nodeName] in the network snapshot named [snapshotName], rather than from an empty database and the core's
+// certs. Restored nodes always run with their files exposed, so that they can be snapshotted again.
+func (core AvalancheServiceInitializerCore) RestoredFrom(snapshotName string, nodeName string) *AvalancheServiceInitializerCore {
+	core.restoreSnapshotName = snapshotName
+	core.restoreNodeName = nodeName
+	core.exposeNodeFiles = true
+	if core.stakingEnabled {
+		certFilepath, keyFilepath := WorkingDirStakingFilepaths(SnapshotNodeDirpath(snapshotName, nodeName))
+		core.certProvider = certs.NewFileAvalancheCertProvider(certFilepath, keyFilepath)
+	}
+	return &core
+}
+
+// RestoreDBDirpath returns the path, on the test suite container, of the database that nodes of the core start from, or
+// empty if they start from an empty database
+func (core AvalancheServiceInitializerCore) RestoreDBDirpath() string {
+	if core.restoreSnapshotName == "" {
+		return ""
 	}
+	return WorkingDirDBDirpath(SnapshotNodeDirpath(core.restoreSnapshotName, core.restoreNodeName))
 }
 
 // GetUsedPorts implements services.ServiceInitializerCore to declare the ports used by the node
@@ -147,6 +241,38 @@ func (core AvalancheServiceInitializerCore) InitializeMountedFiles(osFiles map[s
 // GetStartCommand implements services.ServiceInitializerCore to build the command line that will be used to launch an Avalanche node
 // The IP placeholder is a string that can be used in place of the IP, since we don't yet know the IP when we ask to start a new service
 func (core AvalancheServiceInitializerCore) GetStartCommand(mountedFileFilepaths map[string]string, ipPlaceholder string, dependencies []services.Service) ([]string, error) {
+	launch := NodeLaunch{
+		BinaryPath:  avalancheBinary,
+		PublicIP:    ipPlaceholder,
+		HTTPPort:    httpPort,
+		StakingPort: stakingPort,
+	}
+	if core.exposeNodeFiles {
+		// Keeping the database in the working directory lets it be snapshotted
+		launch.DBDirpath = nodeDBDirname
+	}
+	commandList, err := core.NodeCommand(launch, mountedFileFilepaths, dependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	if core.exposeNodeFiles {
+		restoreDBDirpath := ""
+		if core.restoreSnapshotName != "" {
+			restoreDBDirpath = filepath.Join(testVolumeMountpoint, snapshotsDirname, core.restoreSnapshotName, core.restoreNodeName, nodeDBDirname)
+		}
+		commandList = wrapInWorkingDir(commandList, ipPlaceholder, mountedFileFilepaths, restoreDBDirpath)
+	}
+
+	logrus.Debugf("Command list: %+v", commandList)
+	return commandList, nil
+}
+
+// NodeCommand builds the command line that launches an Avalanche node as described by [launch], whatever backend
+// executes it. [mountedFileFilepaths] maps the IDs of the files returned by GetFilesToMount to their paths as seen by
+// the node, and [dependencies] are the already started nodes this node bootstraps from. If the core exposes node files,
+// the command must be run from the node's own working directory.
+func (core AvalancheServiceInitializerCore) NodeCommand(launch NodeLaunch, mountedFileFilepaths map[string]string, dependencies []services.Service) ([]string, error) {
 	numBootNodeIDs := len(core.bootstrapperNodeIDs)
 	numDependencies := len(dependencies)
 	if numDependencies > numBootNodeIDs {
@@ -157,14 +283,14 @@ func (core AvalancheServiceInitializerCore) GetStartCommand(mountedFileFilepaths
 		)
 	}
 
-	publicIPFlag := fmt.Sprintf("--public-ip=%s", ipPlaceholder)
+	publicIPFlag := fmt.Sprintf("--public-ip=%s", launch.PublicIP)
 	commandList := []string{
-		avalancheBinary,
+		launch.BinaryPath,
 		publicIPFlag,
-		"--network-id=local",
-		fmt.Sprintf("--http-port=%d", httpPort),
+		fmt.Sprintf("--network-id=%s", constants.NetworkName(core.networkID)),
+		fmt.Sprintf("--http-port=%d", launch.HTTPPort),
 		"--http-host=", // Leave empty to make API openly accessible
-		fmt.Sprintf("--staking-port=%d", stakingPort),
+		fmt.Sprintf("--staking-port=%d", launch.StakingPort),
 		fmt.Sprintf("--log-level=%s", core.logLevel),
 		fmt.Sprintf("--snow-sample-size=%d", core.snowSampleSize),
 		fmt.Sprintf("--snow-quorum-size=%d", core.snowQuorumSize),
@@ -173,6 +299,16 @@ func (core AvalancheServiceInitializerCore) GetStartCommand(mountedFileFilepaths
 		fmt.Sprintf("--network-initial-timeout=%s", core.networkInitialTimeout),
 	}
 
+	if core.displayLogLevel != "" {
+		commandList = append(commandList, fmt.Sprintf("--log-display-level=%s", core.displayLogLevel))
+	}
+	if launch.DBDirpath != "" {
+		commandList = append(commandList, fmt.Sprintf("--db-dir=%s", launch.DBDirpath))
+	}
+	if launch.PluginDirpath != "" {
+		commandList = append(commandList, fmt.Sprintf("--plugin-dir=%s", launch.PluginDirpath))
+	}
+
 	if core.stakingEnabled {
 		certFilepath, found := mountedFileFilepaths[stakingTLSCertFileID]
 		if !found {
@@ -213,18 +349,20 @@ func (core AvalancheServiceInitializerCore) GetStartCommand(mountedFileFilepaths
 		commandList = append(commandList, fmt.Sprintf("--%s=%s", param, argument))
 	}
 
-	logrus.Debugf("Command list: %+v", commandList)
+	if core.exposeNodeFiles {
+		// IPC sockets are created relative to the working directory, since absolute paths on the test volume can
+		// exceed the maximum length of a unix socket path
+		commandList = append(commandList, "--api-ipcs-enabled=true", "--ipcs-path=.")
+		commandList = append(commandList, fmt.Sprintf("--log-dir=%s", nodeLogsDirname))
+	}
+
 	return commandList, nil
 }
 
 // GetServiceFromIp implements services.ServiceInitializerCore function to take the IP address of the Docker container that Kurtosis
 // launches the Avalanche node inside and wrap it with our AvalancheService implementation of NodeService
 func (core AvalancheServiceInitializerCore) GetServiceFromIp(ipAddr string) services.Service {
-	return AvalancheService{
-		ipAddr:      ipAddr,
-		stakingPort: stakingPort,
-		jsonRPCPort: httpPort,
-	}
+	return NewAvalancheService(ipAddr, httpPort, stakingPort)
 }
 
 // GetTestVolumeMountpoint implements services.ServiceInitializerCore to declare the path on the Avalanche Docker image where the test
@@ -9,6 +9,7 @@ import (
 	"github.com/kurtosis-tech/kurtosis-go/lib/services"
 
 	"github.com/ava-labs/avalanche-testing/avalanche/services/certs"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,6 +19,7 @@ const (
 
 func TestNoDepsStartCommand(t *testing.T) {
 	initializerCore := NewAvalancheServiceInitializerCore(
+		constants.LocalID,
 		1,
 		1,
 		0,
@@ -27,6 +29,8 @@ func TestNoDepsStartCommand(t *testing.T) {
 		[]string{},
 		certs.NewStaticAvalancheCertProvider(bytes.Buffer{}, bytes.Buffer{}),
 		INFO,
+		"",
+		false,
 	)
 
 	expected := []string{
@@ -56,6 +60,7 @@ func TestWithDepsStartCommand(t *testing.T) {
 		testNodeID,
 	}
 	initializerCore := NewAvalancheServiceInitializerCore(
+		constants.LocalID,
 		1,
 		1,
 		0,
@@ -65,6 +70,8 @@ func TestWithDepsStartCommand(t *testing.T) {
 		bootstrapperNodeIDs,
 		certs.NewStaticAvalancheCertProvider(bytes.Buffer{}, bytes.Buffer{}),
 		INFO,
+		"",
+		false,
 	)
 
 	expected := []string{
@@ -95,3 +102,92 @@ func TestWithDepsStartCommand(t *testing.T) {
 	assert.NoError(t, err, "An error occurred getting the start command")
 	assert.Equal(t, expected, actual)
 }
+
+func TestCustomNetworkIDStartCommand(t *testing.T) {
+	initializerCore := NewAvalancheServiceInitializerCore(
+		1337,
+		1,
+		1,
+		0,
+		false,
+		2*time.Second,
+		make(map[string]string),
+		[]string{},
+		certs.NewStaticAvalancheCertProvider(bytes.Buffer{}, bytes.Buffer{}),
+		INFO,
+		"",
+		false,
+	)
+
+	actual, err := initializerCore.GetStartCommand(make(map[string]string), ipPlaceholder, make([]services.Service, 0))
+	assert.NoError(t, err, "An error occurred getting the start command")
+	assert.Contains(t, actual, "--network-id=network-1337")
+}
+
+func TestExposeNodeFilesStartCommand(t *testing.T) {
+	initializerCore := NewAvalancheServiceInitializerCore(
+		constants.LocalID,
+		1,
+		1,
+		0,
+		false,
+		2*time.Second,
+		make(map[string]string),
+		[]string{},
+		certs.NewStaticAvalancheCertProvider(bytes.Buffer{}, bytes.Buffer{}),
+		INFO,
+		WARN,
+		true,
+	)
+
+	actual, err := initializerCore.GetStartCommand(make(map[string]string), ipPlaceholder, make([]services.Service, 0))
+	assert.NoError(t, err, "An error occurred getting the start command")
+	assert.Len(t, actual, 3)
+	assert.Equal(t, []string{"/bin/sh", "-c"}, actual[:2])
+	workingDirpath := "'/shared/nodes/" + ipPlaceholder + "'"
+	expectedPrefix := fmt.Sprintf("mkdir -p %s && cd %s && rm -rf 'db' && exec '%s' '--public-ip=%s'", workingDirpath, workingDirpath, avalancheBinary, ipPlaceholder)
+	assert.Equal(t, expectedPrefix, actual[2][:len(expectedPrefix)])
+	assert.Contains(t, actual[2], "'--http-host='")
+	assert.Contains(t, actual[2], "'--db-dir=db'")
+	assert.Contains(t, actual[2], "'--ipcs-path=.'")
+	assert.Contains(t, actual[2], "'--log-dir=logs'")
+	assert.Contains(t, actual[2], "'--log-display-level=warn'")
+}
+
+func TestRestoredStartCommand(t *testing.T) {
+	initializerCore := NewAvalancheServiceInitializerCore(
+		constants.LocalID,
+		1,
+		1,
+		0,
+		true,
+		2*time.Second,
+		make(map[string]string),
+		[]string{},
+		certs.NewStaticAvalancheCertProvider(bytes.Buffer{}, bytes.Buffer{}),
+		INFO,
+		"",
+		false,
+	).RestoredFrom("stakers", "validator-1")
+
+	mountedFileFilepaths := map[string]string{
+		stakingTLSCertFileID: "/shared/files/cert",
+		stakingTLSKeyFileID:  "/shared/files/key",
+	}
+	actual, err := initializerCore.GetStartCommand(mountedFileFilepaths, ipPlaceholder, make([]services.Service, 0))
+	assert.NoError(t, err, "An error occurred getting the start command")
+	assert.Len(t, actual, 3)
+	expectedSteps := "rm -rf 'db' && cp -R '/shared/snapshots/stakers/validator-1/db' 'db' && " +
+		"cp '/shared/files/cert' 'staking-tls-cert' && cp '/shared/files/key' 'staking-tls-key' && exec "
+	assert.Contains(t, actual[2], expectedSteps)
+	assert.Equal(t, "/suite-execution/snapshots/stakers/validator-1/db", initializerCore.RestoreDBDirpath())
+}
+
+func TestParseAvalancheLogLevel(t *testing.T) {
+	level, err := ParseAvalancheLogLevel("VERBO")
+	assert.NoError(t, err)
+	assert.Equal(t, VERBOSE, level)
+
+	_, err = ParseAvalancheLogLevel("verbose")
+	assert.Error(t, err, "Expected an error parsing an unknown log level")
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// The path where the test suite container mounts the suite execution volume, which nodes mount at testVolumeMountpoint
+	suiteExecutionVolumeMountpoint = "/suite-execution"
+
+	// Directory on the suite execution volume holding one working directory per node with exposed files, named after
+	// its IP. This is kept short since the working directory contains the node's IPC sockets.
+	nodeWorkingDirsDirname = "nodes"
+
+	// Directory on the suite execution volume holding one directory of artifacts per test
+	resultsDirname = "results"
+
+	// Directory on the suite execution volume holding one directory per network snapshot
+	snapshotsDirname = "snapshots"
+
+	// Directory, relative to a node's working directory, where the node writes its log files
+	nodeLogsDirname = "logs"
+
+	// Directory, relative to a node's working directory, where the node keeps its database
+	nodeDBDirname = "db"
+)
+
+// suiteExecutionDirpath is where the test suite finds the suite execution volume, unless the suite runs outside of
+// Kurtosis and keeps its files elsewhere
+var suiteExecutionDirpath = suiteExecutionVolumeMountpoint
+
+// SetSuiteExecutionDirpath makes the test suite keep the files it would write to the suite execution volume, such as
+// test results, under [dirpath] instead. This is for running tests outside of Kurtosis, where there's no such volume.
+func SetSuiteExecutionDirpath(dirpath string) {
+	suiteExecutionDirpath = dirpath
+}
+
+// SuiteExecutionPath returns the path, on the test suite container, of [relativePath] on the suite execution volume
+func SuiteExecutionPath(relativePath string) string {
+	return filepath.Join(suiteExecutionDirpath, relativePath)
+}
+
+// NodeWorkingDirpath returns the path, on the test suite container, of the working directory of the node at [ipAddr].
+// Nodes only run in this directory if they were started with their files exposed, in which case it's where their admin
+// API writes profiles and where their IPC sockets are created.
+func NodeWorkingDirpath(ipAddr string) string {
+	return filepath.Join(suiteExecutionDirpath, nodeWorkingDirsDirname, ipAddr)
+}
+
+// NodeLogsDirpath returns the path, on the test suite container, of the log directory of the node at [ipAddr]. Nodes
+// only write their logs here if they were started with their files exposed.
+func NodeLogsDirpath(ipAddr string) string {
+	return WorkingDirLogsDirpath(NodeWorkingDirpath(ipAddr))
+}
+
+// WorkingDirLogsDirpath returns the path of the log directory of a node running in the working directory at
+// [workingDirpath], which is where it writes its logs if it was started with its files exposed
+func WorkingDirLogsDirpath(workingDirpath string) string {
+	return filepath.Join(workingDirpath, nodeLogsDirname)
+}
+
+// WorkingDirDBDirpath returns the path of the database directory of a node running in the working directory at
+// [workingDirpath], which is where it keeps its database if it was started with its files exposed
+func WorkingDirDBDirpath(workingDirpath string) string {
+	return filepath.Join(workingDirpath, nodeDBDirname)
+}
+
+// WorkingDirStakingFilepaths returns the paths of the staking cert and key of a node running in the working directory at
+// [workingDirpath], where they're copied to if the node was started with its files exposed and staking enabled
+func WorkingDirStakingFilepaths(workingDirpath string) (certFilepath string, keyFilepath string) {
+	return filepath.Join(workingDirpath, stakingTLSCertFileID), filepath.Join(workingDirpath, stakingTLSKeyFileID)
+}
+
+// SnapshotDirpath returns the path, on the test suite container, of the directory holding the network snapshot named
+// [snapshotName]. It's on the suite execution volume, so every test of the suite execution can start from it.
+func SnapshotDirpath(snapshotName string) string {
+	return filepath.Join(suiteExecutionDirpath, snapshotsDirname, snapshotName)
+}
+
+// SnapshotNodeDirpath returns the path, on the test suite container, of the directory holding the database and staking
+// cert and key of the node named [nodeName] in the network snapshot named [snapshotName]. It's laid out like the node's
+// working directory.
+func SnapshotNodeDirpath(snapshotName string, nodeName string) string {
+	return filepath.Join(SnapshotDirpath(snapshotName), nodeName)
+}
+
+// ResultsDirpath returns the path, on the test suite container, where the test named [testName] should store artifacts.
+// It's on the suite execution volume, so it outlives the test network.
+func ResultsDirpath(testName string) string {
+	return filepath.Join(suiteExecutionDirpath, resultsDirname, testName)
+}
+
+// wrapInWorkingDir returns a command that starts [commandList] from the node's working directory on the test volume,
+// creating the directory first. [ipPlaceholder] is replaced by Kurtosis with the node's IP, which names the directory.
+// The database left by an earlier node with the same IP is removed, and replaced by a copy of [restoreDBDirpath] unless
+// it's empty. The files in [mountedFileFilepaths] are copied into the working directory, named by their IDs, so that
+// the directory holds everything a snapshot of the node needs.
+func wrapInWorkingDir(commandList []string, ipPlaceholder string, mountedFileFilepaths map[string]string, restoreDBDirpath string) []string {
+	workingDirpath := filepath.Join(testVolumeMountpoint, nodeWorkingDirsDirname, ipPlaceholder)
+	steps := []string{
+		"mkdir -p " + shellQuote(workingDirpath),
+		"cd " + shellQuote(workingDirpath),
+		"rm -rf " + shellQuote(nodeDBDirname),
+	}
+	if restoreDBDirpath != "" {
+		steps = append(steps, fmt.Sprintf("cp -R %s %s", shellQuote(restoreDBDirpath), shellQuote(nodeDBDirname)))
+	}
+	fileIDs := make([]string, 0, len(mountedFileFilepaths))
+	for fileID := range mountedFileFilepaths {
+		fileIDs = append(fileIDs, fileID)
+	}
+	sort.Strings(fileIDs)
+	for _, fileID := range fileIDs {
+		steps = append(steps, fmt.Sprintf("cp %s %s", shellQuote(mountedFileFilepaths[fileID]), shellQuote(fileID)))
+	}
+
+	quotedCommand := make([]string, 0, len(commandList))
+	for _, arg := range commandList {
+		quotedCommand = append(quotedCommand, shellQuote(arg))
+	}
+	steps = append(steps, "exec "+strings.Join(quotedCommand, " "))
+	return []string{"/bin/sh", "-c", strings.Join(steps, " && ")}
+}
+
+func shellQuote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'"'"'`) + "'"
+}
@@ -9,6 +9,23 @@ KURTOSIS_CORE_CHANNEL="1.0.3"
 INITIALIZER_IMAGE="kurtosistech/kurtosis-core_initializer:${KURTOSIS_CORE_CHANNEL}"
 API_IMAGE="kurtosistech/kurtosis-core_api:${KURTOSIS_CORE_CHANNEL}"
 
+# Format of the test suite's logs, either text or json (for ingestion by CI)
+LOG_FORMAT="${LOG_FORMAT:-text}"
+
+# Node log level overrides, passed to the test suite (see the README); empty keeps the levels each test configures
+NODE_LOG_LEVEL="${NODE_LOG_LEVEL:-}"
+NODE_DISPLAY_LOG_LEVEL="${NODE_DISPLAY_LOG_LEVEL:-}"
+TEST_NODE_LOG_LEVELS="${TEST_NODE_LOG_LEVELS:-}"
+
+# Comma-separated tags selecting the tests to run (see the README); empty runs every test. The soak tests, which run
+# for hours, only run when TEST_TAGS includes soak.
+TEST_TAGS="${TEST_TAGS:-}"
+EXCLUDED_TEST_TAGS="${EXCLUDED_TEST_TAGS:-}"
+
+# Test parameter overrides (see the README); empty runs every test with its default parameters
+TEST_PARAMS_FILE="${TEST_PARAMS_FILE:-}"
+TEST_PARAMS="${TEST_PARAMS:-}"
+
 # As of 2020-09-16, if we run with higher parallelism then we start to get timeouts (maybe worth upping the timeouts??)
 PARALLELISM=2
 
@@ -88,7 +105,7 @@ if "${do_run}"; then
     docker volume create "${suite_execution_volume}"
 
     # Docker only allows you to have spaces in the variable if you escape them or use a Docker env file
-    custom_env_vars_json_flag="CUSTOM_ENV_VARS_JSON={\"AVALANCHE_IMAGE\":\"${AVALANCHE_IMAGE}\",\"BYZANTINE_IMAGE\":\"${BYZANTINE_IMAGE}\"}"
+    custom_env_vars_json_flag="CUSTOM_ENV_VARS_JSON={\"AVALANCHE_IMAGE\":\"${AVALANCHE_IMAGE}\",\"BYZANTINE_IMAGE\":\"${BYZANTINE_IMAGE}\",\"LOG_FORMAT\":\"${LOG_FORMAT}\",\"NODE_LOG_LEVEL\":\"${NODE_LOG_LEVEL}\",\"NODE_DISPLAY_LOG_LEVEL\":\"${NODE_DISPLAY_LOG_LEVEL}\",\"TEST_NODE_LOG_LEVELS\":\"${TEST_NODE_LOG_LEVELS}\",\"TEST_TAGS\":\"${TEST_TAGS}\",\"EXCLUDED_TEST_TAGS\":\"${EXCLUDED_TEST_TAGS}\",\"TEST_PARAMS_FILE\":\"${TEST_PARAMS_FILE}\",\"TEST_PARAMS\":\"${TEST_PARAMS}\"}"
 
     echo "${custom_env_vars_json_flag}"
     docker run \
@@ -0,0 +1,145 @@
+package chaos
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTargets() map[networks.ServiceID]Target {
+	return map[networks.ServiceID]Target{
+		"validator-0": {ConfigurationID: "config-0", Stake: 100},
+		"validator-1": {ConfigurationID: "config-1", Stake: 100},
+		"validator-2": {ConfigurationID: "config-2", Stake: 100},
+		"validator-3": {ConfigurationID: "config-3", Stake: 200},
+		"observer":    {ConfigurationID: "observer-config"},
+	}
+}
+
+func testScheduleConfig() ScheduleConfig {
+	return ScheduleConfig{
+		Seed:                    7,
+		Duration:                time.Hour,
+		MeanInterval:            30 * time.Second,
+		MinFaultDuration:        10 * time.Second,
+		MaxFaultDuration:        2 * time.Minute,
+		RecoveryTime:            30 * time.Second,
+		Kinds:                   []FaultKind{RestartFault, ReaddFault},
+		MaxFaultedStakeFraction: 0.4,
+		TotalStake:              500,
+	}
+}
+
+func TestNewScheduleRespectsStakeBudget(t *testing.T) {
+	config := testScheduleConfig()
+	targets := testTargets()
+	schedule, err := NewSchedule(config, targets)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, schedule.Faults)
+
+	again, err := NewSchedule(config, targets)
+	assert.NoError(t, err)
+	assert.Equal(t, schedule, again, "Expected the same seed to generate the same schedule")
+
+	// Every instant at which a fault starts is where the faulted stake can grow
+	for i, fault := range schedule.Faults {
+		assert.True(t, canInject(fault.Kind, targets[fault.ServiceID]), "%s fault injected into %s", fault.Kind, fault.ServiceID)
+		faultedStake := uint64(0)
+		faulted := make(map[networks.ServiceID]bool)
+		for _, earlier := range schedule.Faults[:i+1] {
+			if earlier.At+earlier.Duration+config.RecoveryTime <= fault.At {
+				continue
+			}
+			assert.False(t, faulted[earlier.ServiceID], "%s faulted twice at %v", earlier.ServiceID, fault.At)
+			faulted[earlier.ServiceID] = true
+			faultedStake += targets[earlier.ServiceID].Stake
+		}
+		assert.LessOrEqual(t, faultedStake, uint64(200), "Faulted stake exceeds the budget at %v", fault.At)
+	}
+}
+
+func TestScheduleRoundTrip(t *testing.T) {
+	schedule, err := NewSchedule(testScheduleConfig(), testTargets())
+	assert.NoError(t, err)
+
+	scheduleFilepath := filepath.Join(t.TempDir(), "chaos", "schedule.json")
+	assert.NoError(t, schedule.Write(scheduleFilepath))
+	read, err := ReadSchedule(scheduleFilepath)
+	assert.NoError(t, err)
+	assert.Equal(t, schedule, read)
+}
+
+// fakeNetwork records the faults injected into it
+type fakeNetwork struct {
+	lock   sync.Mutex
+	events []string
+}
+
+func (network *fakeNetwork) record(event string) {
+	network.lock.Lock()
+	defer network.lock.Unlock()
+	network.events = append(network.events, event)
+}
+
+type upCore struct{}
+
+func (upCore) IsServiceUp(services.Service, []services.Service) bool { return true }
+func (upCore) GetTimeout() time.Duration                             { return time.Second }
+
+func (network *fakeNetwork) AddService(configurationID networks.ConfigurationID, serviceID networks.ServiceID) (*services.ServiceAvailabilityChecker, error) {
+	network.record("add " + string(serviceID) + " from " + string(configurationID))
+	return services.NewServiceAvailabilityChecker(context.Background(), upCore{}, nil, nil), nil
+}
+
+func (network *fakeNetwork) RemoveService(serviceID networks.ServiceID) error {
+	network.record("remove " + string(serviceID))
+	return nil
+}
+
+func TestEngineInjectsAndRecoversFaults(t *testing.T) {
+	network := &fakeNetwork{}
+	schedule := Schedule{Faults: []Fault{
+		{At: 0, Kind: RestartFault, ServiceID: "validator-3", Duration: 20 * time.Millisecond},
+		// Delayed until validator-3 recovers, since both together exceed the budget
+		{At: 5 * time.Millisecond, Kind: RestartFault, ServiceID: "validator-0", Duration: 10 * time.Millisecond},
+		{At: 10 * time.Millisecond, Kind: ReaddFault, ServiceID: "observer", Duration: time.Hour},
+	}}
+	engine, err := NewEngine(network, testTargets(), schedule, 0.4, 500)
+	assert.NoError(t, err)
+	assert.Equal(t, map[networks.ServiceID]bool{"validator-3": true, "validator-0": true, "observer": true}, engine.FaultedServiceIDs())
+
+	assert.NoError(t, engine.Start(filepath.Join(t.TempDir(), "schedule.json")))
+	time.Sleep(budgetPollInterval + 500*time.Millisecond)
+	assert.NoError(t, engine.Stop())
+
+	// Faults are injected in order, so the observer's waits for validator-0's, and is only recovered once the engine stops
+	events := network.events
+	assert.Equal(t, []string{"remove validator-3", "add validator-3 from config-3", "remove validator-0"}, events[:3])
+	assert.Equal(t, "add observer from observer-config", events[len(events)-1])
+	sort.Strings(events[3:5])
+	assert.Equal(t, []string{"add validator-0 from config-0", "remove observer"}, events[3:5])
+	assert.Len(t, events, 6)
+}
+
+func TestNewEngineRejectsUnsupportedFaults(t *testing.T) {
+	network := &fakeNetwork{}
+	for _, fault := range []Fault{
+		// Kurtosis can't pause containers
+		{Kind: FaultKind("pause"), ServiceID: "validator-0", Duration: time.Second},
+		{Kind: ReaddFault, ServiceID: "validator-0", Duration: time.Second},
+		{Kind: RestartFault, ServiceID: "observer", Duration: time.Second},
+		{Kind: RestartFault, ServiceID: "unknown", Duration: time.Second},
+		// Exceeds the budget on its own
+		{Kind: RestartFault, ServiceID: "validator-3", Duration: time.Second},
+	} {
+		_, err := NewEngine(network, testTargets(), Schedule{Faults: []Fault{fault}}, 0.3, 500)
+		assert.Error(t, err, "Expected %s fault of %s to be rejected", fault.Kind, fault.ServiceID)
+	}
+}
@@ -0,0 +1,220 @@
+package chaos
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/services"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/logging"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Time between checks of whether a fault that's due fits in the stake budget yet
+	budgetPollInterval = time.Second
+)
+
+// Network is the part of a TestAvalancheNetwork the engine injects faults through
+type Network interface {
+	AddService(configurationID networks.ConfigurationID, serviceID networks.ServiceID) (*services.ServiceAvailabilityChecker, error)
+	RemoveService(serviceID networks.ServiceID) error
+}
+
+// Engine injects the faults of a schedule into a network in the background, while a test runs a workload against it.
+// A fault that's due while the validators already faulted hold too much stake is delayed until it fits, so that the
+// stake budget holds even when nodes take longer to recover than the schedule expected.
+type Engine struct {
+	network         Network
+	targets         map[networks.ServiceID]Target
+	schedule        Schedule
+	maxFaultedStake uint64
+
+	lock         sync.Mutex
+	faulted      map[networks.ServiceID]bool
+	faultedStake uint64
+	firstErr     error
+
+	stop       chan struct{}
+	done       chan struct{}
+	recoveries sync.WaitGroup
+}
+
+// NewEngine creates an engine that injects the faults of [schedule] into [targets] of [network], never letting
+// validators holding more than [maxFaultedStakeFraction] of [totalStake] be faulted at once
+func NewEngine(network Network, targets map[networks.ServiceID]Target, schedule Schedule, maxFaultedStakeFraction float64, totalStake uint64) (*Engine, error) {
+	maxFaultedStake := maxFaultedStake(maxFaultedStakeFraction, totalStake)
+	for i, fault := range schedule.Faults {
+		target, found := targets[fault.ServiceID]
+		if !found {
+			return nil, stacktrace.NewError("Fault %d is injected into %s, which isn't a target", i, fault.ServiceID)
+		}
+		if !isKnownKind(fault.Kind) {
+			return nil, stacktrace.NewError("Fault %d is of unknown kind %q", i, fault.Kind)
+		}
+		if !canInject(fault.Kind, target) {
+			return nil, stacktrace.NewError("Fault %d is a %s fault, which can't be injected into %s with stake %d", i, fault.Kind, fault.ServiceID, target.Stake)
+		}
+		if target.Stake > maxFaultedStake {
+			return nil, stacktrace.NewError("Fault %d is injected into %s, whose stake %d exceeds the budget of %d", i, fault.ServiceID, target.Stake, maxFaultedStake)
+		}
+		if fault.Duration <= 0 {
+			return nil, stacktrace.NewError("Fault %d doesn't have a positive duration", i)
+		}
+		if i > 0 && fault.At < schedule.Faults[i-1].At {
+			return nil, stacktrace.NewError("Fault %d is injected before the fault preceding it", i)
+		}
+	}
+	return &Engine{
+		network:         network,
+		targets:         targets,
+		schedule:        schedule,
+		maxFaultedStake: maxFaultedStake,
+		faulted:         make(map[networks.ServiceID]bool),
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}, nil
+}
+
+// FaultedServiceIDs returns the service IDs of the nodes the schedule injects faults into, which a test should exclude
+// from its assertions on the nodes' health
+func (e *Engine) FaultedServiceIDs() map[networks.ServiceID]bool {
+	result := make(map[networks.ServiceID]bool)
+	for _, fault := range e.schedule.Faults {
+		result[fault.ServiceID] = true
+	}
+	return result
+}
+
+// Start writes the schedule to [scheduleFilepath], so that a failed run can be replayed with ReadSchedule, then starts
+// injecting its faults until Stop is called
+func (e *Engine) Start(scheduleFilepath string) error {
+	if err := e.schedule.Write(scheduleFilepath); err != nil {
+		return stacktrace.Propagate(err, "Failed to write the chaos schedule")
+	}
+	logrus.Infof("Injecting %d faults, scheduled with seed %d and written to %s.", len(e.schedule.Faults), e.schedule.Seed, scheduleFilepath)
+	go e.run()
+	return nil
+}
+
+// Stop stops injecting faults, recovers every node that's still faulted, and returns the first error that occurred
+// injecting or recovering from a fault
+func (e *Engine) Stop() error {
+	close(e.stop)
+	<-e.done
+	e.recoveries.Wait()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.firstErr
+}
+
+// run injects the faults of the schedule as they become due
+func (e *Engine) run() {
+	defer close(e.done)
+
+	start := time.Now()
+	for _, fault := range e.schedule.Faults {
+		select {
+		case <-e.stop:
+			return
+		case <-time.After(time.Until(start.Add(fault.At))):
+		}
+		for delayed := false; !e.reserve(fault); delayed = true {
+			if !delayed {
+				logging.ForService(string(fault.ServiceID)).Infof("Delaying %s fault of %s until it fits in the stake budget.", fault.Kind, fault.ServiceID)
+			}
+			select {
+			case <-e.stop:
+				return
+			case <-time.After(budgetPollInterval):
+			}
+		}
+
+		if err := e.inject(fault); err != nil {
+			e.fail(stacktrace.Propagate(err, "Failed to inject %s fault into %s", fault.Kind, fault.ServiceID))
+			e.release(fault)
+			continue
+		}
+		e.recoveries.Add(1)
+		go e.recoverAfter(fault)
+	}
+}
+
+// reserve marks the node of [fault] as faulted and returns true, unless it's already faulted or faulting it would
+// exceed the stake budget
+func (e *Engine) reserve(fault Fault) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	stake := e.targets[fault.ServiceID].Stake
+	if e.faulted[fault.ServiceID] || e.faultedStake+stake > e.maxFaultedStake {
+		return false
+	}
+	e.faulted[fault.ServiceID] = true
+	e.faultedStake += stake
+	return true
+}
+
+// release marks the node of [fault] as no longer faulted
+func (e *Engine) release(fault Fault) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	delete(e.faulted, fault.ServiceID)
+	e.faultedStake -= e.targets[fault.ServiceID].Stake
+}
+
+// fail records [err], logging it so that it's visible when it happens rather than only once the engine stops
+func (e *Engine) fail(err error) {
+	logrus.Error(err)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.firstErr == nil {
+		e.firstErr = err
+	}
+}
+
+// inject injects [fault] into its node
+func (e *Engine) inject(fault Fault) error {
+	logging.ForService(string(fault.ServiceID)).Infof("Injecting %s fault into %s for %v.", fault.Kind, fault.ServiceID, fault.Duration)
+	switch fault.Kind {
+	case RestartFault, ReaddFault:
+		return e.network.RemoveService(fault.ServiceID)
+	default:
+		return stacktrace.NewError("Unknown fault kind %q", fault.Kind)
+	}
+}
+
+// recoverAfter recovers the node of [fault] once the fault has lasted its duration, or as soon as the engine is stopped
+func (e *Engine) recoverAfter(fault Fault) {
+	defer e.recoveries.Done()
+	defer e.release(fault)
+
+	select {
+	case <-e.stop:
+	case <-time.After(fault.Duration):
+	}
+	if err := e.recover(fault); err != nil {
+		e.fail(stacktrace.Propagate(err, "Failed to recover %s from %s fault", fault.ServiceID, fault.Kind))
+		return
+	}
+	logging.ForService(string(fault.ServiceID)).Infof("Recovered %s from %s fault.", fault.ServiceID, fault.Kind)
+}
+
+// recover undoes [fault], waiting for a node that's added back to finish bootstrapping
+func (e *Engine) recover(fault Fault) error {
+	switch fault.Kind {
+	case RestartFault, ReaddFault:
+		checker, err := e.network.AddService(e.targets[fault.ServiceID].ConfigurationID, fault.ServiceID)
+		if err != nil {
+			return err
+		}
+		return checker.WaitForStartup()
+	default:
+		return stacktrace.NewError("Unknown fault kind %q", fault.Kind)
+	}
+}
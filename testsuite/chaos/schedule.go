@@ -0,0 +1,226 @@
+package chaos
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+
+	"github.com/palantir/stacktrace"
+)
+
+// FaultKind is a kind of fault the engine injects into a node
+type FaultKind string
+
+const (
+	// RestartFault stops a validator and adds it back with the same staking key once the fault ends
+	RestartFault FaultKind = "restart"
+
+	// ReaddFault removes a node that isn't a validator and adds it back, with an empty database, once the fault ends
+	ReaddFault FaultKind = "readd"
+)
+
+// Fault is a fault injected into one node
+type Fault struct {
+	// How long after the engine starts the fault is injected
+	At time.Duration `json:"at"`
+
+	Kind FaultKind `json:"kind"`
+
+	ServiceID networks.ServiceID `json:"serviceID"`
+
+	// How long the fault lasts before the node is recovered
+	Duration time.Duration `json:"duration"`
+}
+
+// Schedule is the faults an engine injects, in the order they're injected
+type Schedule struct {
+	// The seed the schedule was generated from, or 0 if it was written by hand
+	Seed int64 `json:"seed"`
+
+	Faults []Fault `json:"faults"`
+}
+
+// Target is a node the engine may inject faults into
+type Target struct {
+	// The configuration the node is added back from after a RestartFault or ReaddFault. The configuration of a
+	// validator must launch it with the same staking key, as the boot node configurations do.
+	ConfigurationID networks.ConfigurationID
+
+	// The stake of the node, delegations included, or 0 if it isn't a validator
+	Stake uint64
+}
+
+// ScheduleConfig is the parameters schedules are generated from
+type ScheduleConfig struct {
+	// Seed of the schedule's random choices, so that the same schedule can be generated again
+	Seed int64
+
+	// How long faults are injected for. The last fault may end after this.
+	Duration time.Duration
+
+	// Mean time between the injection of consecutive faults
+	MeanInterval time.Duration
+
+	// Bounds of how long each fault lasts
+	MinFaultDuration time.Duration
+	MaxFaultDuration time.Duration
+
+	// How long a node that's added back is expected to take to bootstrap, during which it still counts as faulted
+	RecoveryTime time.Duration
+
+	// The kinds of faults to inject, each picked with equal probability
+	Kinds []FaultKind
+
+	// The largest fraction of TotalStake that may belong to faulted validators at any time
+	MaxFaultedStakeFraction float64
+
+	// The stake of all the validators of the network, including those that aren't targets
+	TotalStake uint64
+}
+
+// Validate returns an error if [config] can't generate a schedule
+func (config ScheduleConfig) Validate() error {
+	if config.Duration <= 0 || config.MeanInterval <= 0 {
+		return stacktrace.NewError("Duration and MeanInterval must be positive")
+	}
+	if config.MinFaultDuration <= 0 || config.MaxFaultDuration < config.MinFaultDuration {
+		return stacktrace.NewError("MinFaultDuration must be positive and no greater than MaxFaultDuration")
+	}
+	if config.RecoveryTime < 0 {
+		return stacktrace.NewError("RecoveryTime must not be negative")
+	}
+	if len(config.Kinds) == 0 {
+		return stacktrace.NewError("At least one kind of fault must be injected")
+	}
+	for _, kind := range config.Kinds {
+		if !isKnownKind(kind) {
+			return stacktrace.NewError("Unknown fault kind %q", kind)
+		}
+	}
+	if config.MaxFaultedStakeFraction < 0 || config.MaxFaultedStakeFraction > 1 {
+		return stacktrace.NewError("MaxFaultedStakeFraction must be between 0 and 1")
+	}
+	return nil
+}
+
+// maxFaultedStake returns the largest stake that may belong to faulted validators at any time
+func maxFaultedStake(fraction float64, totalStake uint64) uint64 {
+	return uint64(fraction * float64(totalStake))
+}
+
+// NewSchedule generates a random schedule of faults in [targets] from [config]. A node is never faulted twice at once,
+// and the validators that are faulted at any time never hold more than [config.MaxFaultedStakeFraction] of the stake.
+// Times at which no target can be faulted within these limits are skipped.
+func NewSchedule(config ScheduleConfig, targets map[networks.ServiceID]Target) (Schedule, error) {
+	if err := config.Validate(); err != nil {
+		return Schedule{}, stacktrace.Propagate(err, "Invalid schedule config")
+	}
+	budget := maxFaultedStake(config.MaxFaultedStakeFraction, config.TotalStake)
+
+	// Sorted so that the same seed always generates the same schedule
+	serviceIDs := make([]networks.ServiceID, 0, len(targets))
+	for serviceID := range targets {
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	sort.Slice(serviceIDs, func(i, j int) bool { return serviceIDs[i] < serviceIDs[j] })
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	schedule := Schedule{Seed: config.Seed}
+
+	// Service ID -> when the node's fault, including its recovery, ends
+	faultedUntil := make(map[networks.ServiceID]time.Duration)
+	for at := time.Duration(0); ; {
+		at += time.Duration(rng.ExpFloat64() * float64(config.MeanInterval))
+		if at >= config.Duration {
+			break
+		}
+		kind := config.Kinds[rng.Intn(len(config.Kinds))]
+
+		faultedStake := uint64(0)
+		for serviceID, until := range faultedUntil {
+			if until <= at {
+				delete(faultedUntil, serviceID)
+				continue
+			}
+			faultedStake += targets[serviceID].Stake
+		}
+		candidates := make([]networks.ServiceID, 0, len(serviceIDs))
+		for _, serviceID := range serviceIDs {
+			target := targets[serviceID]
+			if _, faulted := faultedUntil[serviceID]; faulted || !canInject(kind, target) {
+				continue
+			}
+			if faultedStake+target.Stake > budget {
+				continue
+			}
+			candidates = append(candidates, serviceID)
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		fault := Fault{
+			At:        at.Round(time.Millisecond),
+			Kind:      kind,
+			ServiceID: candidates[rng.Intn(len(candidates))],
+			Duration:  (config.MinFaultDuration + time.Duration(rng.Int63n(int64(config.MaxFaultDuration-config.MinFaultDuration)+1))).Round(time.Millisecond),
+		}
+		faultedUntil[fault.ServiceID] = fault.At + fault.Duration + config.RecoveryTime
+		schedule.Faults = append(schedule.Faults, fault)
+	}
+	return schedule, nil
+}
+
+// isKnownKind returns whether [kind] is a kind of fault the engine can inject. Both kinds remove a node and add it
+// back, since that's the only fault every backend, Kurtosis included, can inject.
+func isKnownKind(kind FaultKind) bool {
+	return kind == RestartFault || kind == ReaddFault
+}
+
+// canInject returns whether a fault of kind [kind] may be injected into [target]. Only validators are restarted, and
+// only nodes that aren't validators are removed and added back, since either can come back with an empty database
+// but a validator must keep its staking key.
+func canInject(kind FaultKind, target Target) bool {
+	switch kind {
+	case RestartFault:
+		return target.Stake > 0
+	case ReaddFault:
+		return target.Stake == 0
+	default:
+		return false
+	}
+}
+
+// Write writes [schedule] as JSON to [scheduleFilepath], creating its directory if needed
+func (schedule Schedule) Write(scheduleFilepath string) error {
+	bytes, err := json.MarshalIndent(schedule, "", "  ")
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to serialize schedule")
+	}
+	if err := os.MkdirAll(filepath.Dir(scheduleFilepath), 0755); err != nil {
+		return stacktrace.Propagate(err, "Failed to create directory of %s", scheduleFilepath)
+	}
+	if err := ioutil.WriteFile(scheduleFilepath, bytes, 0644); err != nil {
+		return stacktrace.Propagate(err, "Failed to write schedule to %s", scheduleFilepath)
+	}
+	return nil
+}
+
+// ReadSchedule reads a schedule written by Schedule.Write, so that the faults of a failed run can be replayed
+func ReadSchedule(scheduleFilepath string) (Schedule, error) {
+	bytes, err := ioutil.ReadFile(scheduleFilepath)
+	if err != nil {
+		return Schedule{}, stacktrace.Propagate(err, "Failed to read schedule from %s", scheduleFilepath)
+	}
+	var schedule Schedule
+	if err := json.Unmarshal(bytes, &schedule); err != nil {
+		return Schedule{}, stacktrace.Propagate(err, "Failed to parse schedule in %s", scheduleFilepath)
+	}
+	return schedule, nil
+}
@@ -0,0 +1,105 @@
+package chaos
+
+import (
+	"encoding/json"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/palantir/stacktrace"
+)
+
+// CurrentStakes returns the stake of every current validator of the primary network, delegations included, keyed by
+// node ID, as reported by [client]
+func CurrentStakes(client *services.Client) (map[string]uint64, error) {
+	rawValidators, err := client.PChainAPI().GetCurrentValidators(constants.PrimaryNetworkID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get current validators")
+	}
+	// The client returns the validators as generic JSON values, so they're converted back to their API type
+	bytes, err := json.Marshal(rawValidators)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to serialize current validators")
+	}
+	var validators []platformvm.APIPrimaryValidator
+	if err := json.Unmarshal(bytes, &validators); err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to parse current validators")
+	}
+
+	stakes := make(map[string]uint64, len(validators))
+	for _, validator := range validators {
+		stake := stakerWeight(validator.APIStaker)
+		for _, delegator := range validator.Delegators {
+			stake += stakerWeight(delegator.APIStaker)
+		}
+		stakes[validator.NodeID] += stake
+	}
+	return stakes, nil
+}
+
+// stakerWeight returns the weight of [staker], which the API reports as a stake amount on the primary network
+func stakerWeight(staker platformvm.APIStaker) uint64 {
+	switch {
+	case staker.StakeAmount != nil:
+		return uint64(*staker.StakeAmount)
+	case staker.Weight != nil:
+		return uint64(*staker.Weight)
+	default:
+		return 0
+	}
+}
+
+// BootNodeTargets returns the boot nodes of [network] as targets, each with its current stake, along with the stake
+// of all the validators of the network
+func BootNodeTargets(network avalancheNetwork.TestAvalancheNetwork) (map[networks.ServiceID]Target, uint64, error) {
+	return Targets(network, network.GetBootNodeConfigurationIDs())
+}
+
+// Targets returns the nodes of [network] with the service IDs [configIDs] is keyed by as targets, each added back
+// from its configuration in [configIDs] and with its current stake, along with the stake of all the validators of the
+// network. Nodes added to the network after it started, which aren't validators unless they've been added as such,
+// become targets of ReaddFaults this way.
+func Targets(network avalancheNetwork.TestAvalancheNetwork, configIDs map[networks.ServiceID]networks.ConfigurationID) (map[networks.ServiceID]Target, uint64, error) {
+	serviceIDs := make(map[networks.ServiceID]bool, len(configIDs))
+	for serviceID := range configIDs {
+		serviceIDs[serviceID] = true
+	}
+	clients, err := network.GetAvalancheClients(serviceIDs)
+	if err != nil {
+		return nil, 0, stacktrace.Propagate(err, "Failed to get clients of the targets")
+	}
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+
+	var stakes map[string]uint64
+	nodeIDs := make(map[networks.ServiceID]string, len(clients))
+	for serviceID, client := range clients {
+		if stakes == nil {
+			if stakes, err = CurrentStakes(client); err != nil {
+				return nil, 0, stacktrace.Propagate(err, "Failed to get stakes from %s", serviceID)
+			}
+		}
+		if nodeIDs[serviceID], err = client.InfoAPI().GetNodeID(); err != nil {
+			return nil, 0, stacktrace.Propagate(err, "Failed to get node ID of %s", serviceID)
+		}
+	}
+
+	totalStake := uint64(0)
+	for _, stake := range stakes {
+		totalStake += stake
+	}
+	targets := make(map[networks.ServiceID]Target, len(configIDs))
+	for serviceID, configID := range configIDs {
+		targets[serviceID] = Target{
+			ConfigurationID: configID,
+			Stake:           stakes[nodeIDs[serviceID]],
+		}
+	}
+	return targets, totalStake, nil
+}
@@ -0,0 +1,639 @@
+package helpers
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/codec"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateFixedCapAsset creates a fixed cap asset distributed to [holders], blocks until the transaction is accepted, and
+// verifies that every holder received its initial balance
+func (runner RPCWorkFlowRunner) CreateFixedCapAsset(
+	name string,
+	symbol string,
+	denomination byte,
+	holders []*avm.Holder) (ids.ID, error) {
+	assetID, err := runner.client.XChainAPI().CreateFixedCapAsset(
+		runner.userPass,
+		nil, // from addrs
+		"",  // change addr
+		name,
+		symbol,
+		denomination,
+		holders,
+	)
+	if err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to create fixed cap asset %s", name)
+	}
+	if err := runner.AwaitXChainTransactionAcceptance(assetID); err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to accept CreateAssetTx: %s", assetID)
+	}
+
+	// The same address may be listed as a holder more than once
+	expectedBalances := make(map[string]uint64)
+	for _, holder := range holders {
+		expectedBalances[holder.Address] += uint64(holder.Amount)
+	}
+	for address, expectedBalance := range expectedBalances {
+		if err := runner.VerifyXChainAssetBalance(address, assetID, expectedBalance); err != nil {
+			return ids.ID{}, stacktrace.Propagate(err, "Unexpected initial balance of fixed cap asset %s", assetID)
+		}
+	}
+	logrus.Debugf("Created fixed cap asset %s (%s) with %d holders", name, assetID, len(holders))
+	return assetID, nil
+}
+
+// CreateVariableCapAsset creates a variable cap asset that can be minted by [minters] and blocks until the
+// transaction is accepted
+func (runner RPCWorkFlowRunner) CreateVariableCapAsset(
+	name string,
+	symbol string,
+	denomination byte,
+	minters []avm.Owners) (ids.ID, error) {
+	assetID, err := runner.client.XChainAPI().CreateVariableCapAsset(
+		runner.userPass,
+		nil, // from addrs
+		"",  // change addr
+		name,
+		symbol,
+		denomination,
+		minters,
+	)
+	if err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to create variable cap asset %s", name)
+	}
+	if err := runner.AwaitXChainTransactionAcceptance(assetID); err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to accept CreateAssetTx: %s", assetID)
+	}
+	logrus.Debugf("Created variable cap asset %s (%s)", name, assetID)
+	return assetID, nil
+}
+
+// MintAsset mints [amount] of the variable cap asset [assetID] to [to], blocks until the transaction is accepted, and
+// verifies that the balance of [to] increased by [amount]
+func (runner RPCWorkFlowRunner) MintAsset(assetID ids.ID, amount uint64, to string) error {
+	startingBalance, err := runner.getXChainBalance(to, assetID.String())
+	if err != nil {
+		return err
+	}
+	txID, err := runner.client.XChainAPI().Mint(
+		runner.userPass,
+		nil, // from addrs
+		"",  // change addr
+		amount,
+		assetID.String(),
+		to,
+	)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to mint %d of asset %s", amount, assetID)
+	}
+	if err := runner.AwaitXChainTransactionAcceptance(txID); err != nil {
+		return stacktrace.Propagate(err, "Failed to accept Mint Tx: %s", txID)
+	}
+	return runner.VerifyXChainAssetBalance(to, assetID, startingBalance+amount)
+}
+
+// CreateNFTFamily creates an NFT asset that can be minted by [minters] and blocks until the transaction is accepted
+func (runner RPCWorkFlowRunner) CreateNFTFamily(name string, symbol string, minters []avm.Owners) (ids.ID, error) {
+	assetID, err := runner.client.XChainAPI().CreateNFTAsset(
+		runner.userPass,
+		nil, // from addrs
+		"",  // change addr
+		name,
+		symbol,
+		minters,
+	)
+	if err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to create NFT family %s", name)
+	}
+	if err := runner.AwaitXChainTransactionAcceptance(assetID); err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to accept CreateNFTAssetTx: %s", assetID)
+	}
+	logrus.Debugf("Created NFT family %s (%s)", name, assetID)
+	return assetID, nil
+}
+
+// MintNFT mints an NFT of family [assetID] carrying [payload] to [to], blocks until the transaction is accepted, and
+// verifies that [to] holds one more NFT of the family
+func (runner RPCWorkFlowRunner) MintNFT(assetID ids.ID, payload []byte, to string) error {
+	startingCount, err := runner.countXChainOutputs(to, assetID, isNFTOutput)
+	if err != nil {
+		return err
+	}
+	txID, err := runner.client.XChainAPI().MintNFT(
+		runner.userPass,
+		nil, // from addrs
+		"",  // change addr
+		assetID.String(),
+		payload,
+		to,
+	)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to mint NFT of family %s", assetID)
+	}
+	if err := runner.AwaitXChainTransactionAcceptance(txID); err != nil {
+		return stacktrace.Propagate(err, "Failed to accept MintNFT Tx: %s", txID)
+	}
+	return runner.verifyXChainOutputCount(to, assetID, isNFTOutput, startingCount+1)
+}
+
+// SendNFT transfers an NFT in group [groupID] of family [assetID] to [to], blocks until the transaction is accepted, and
+// verifies that the transaction created an NFT of the family held by [to]. The transaction's output is checked rather
+// than the number of NFTs [to] holds, which doesn't change when [to] is one of the runner's own addresses.
+func (runner RPCWorkFlowRunner) SendNFT(assetID ids.ID, groupID uint32, to string) error {
+	txID, err := runner.client.XChainAPI().SendNFT(
+		runner.userPass,
+		nil, // from addrs
+		"",  // change addr
+		assetID.String(),
+		groupID,
+		to,
+	)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to send NFT of family %s", assetID)
+	}
+	if err := runner.AwaitXChainTransactionAcceptance(txID); err != nil {
+		return stacktrace.Propagate(err, "Failed to accept SendNFT Tx: %s", txID)
+	}
+	c, err := CreateXChainCodec()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to initialize codec.")
+	}
+	utxos, err := runner.getXChainUTXOs(c, to)
+	if err != nil {
+		return err
+	}
+	for _, utxo := range utxos {
+		if utxo.TxID == txID && utxo.AssetID() == assetID && isNFTOutput(utxo.Out) {
+			return nil
+		}
+	}
+	return stacktrace.NewError("SendNFT Tx %s created no NFT of family %s held by %s", txID, assetID, to)
+}
+
+// CreatePropertyAsset creates an asset using the property fx whose minter is [ownerAddress], paying the fee from
+// [ownerAddress], and blocks until the transaction is accepted.
+// Note: the AVM API has no endpoint for the property fx, so the transaction is built and signed locally using the key of
+// [ownerAddress], which must be controlled by this runner's user.
+func (runner RPCWorkFlowRunner) CreatePropertyAsset(ownerAddress string, name string, symbol string) (ids.ID, error) {
+	c, ownerKey, err := runner.getCodecAndKey(ownerAddress)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	baseTx, feeSigners, err := runner.createFeePayingBaseTx(c, ownerKey)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	tx := &avm.Tx{UnsignedTx: &avm.CreateAssetTx{
+		BaseTx:       baseTx,
+		Name:         name,
+		Symbol:       symbol,
+		Denomination: 0,
+		States: []*avm.InitialState{{
+			FxID: PropertyFxID,
+			Outs: []verify.State{
+				&propertyfx.MintOutput{OutputOwners: singleOwner(ownerKey.PublicKey().Address())},
+			},
+		}},
+	}}
+	if err := signXChainTx(c, tx, feeSigners, nil); err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to sign property fx CreateAssetTx.")
+	}
+	assetID, err := runner.issueAndAwaitXChainTx(tx)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	logrus.Debugf("Created property fx asset %s (%s)", name, assetID)
+	return assetID, nil
+}
+
+// MintProperty mints a property of [assetID] owned by [to] using the mint output held by [ownerAddress], blocks until
+// the transaction is accepted, and verifies that [to] holds one more property of [assetID]
+func (runner RPCWorkFlowRunner) MintProperty(ownerAddress string, assetID ids.ID, to string) error {
+	formatter, err := runner.AddressFormatter()
+	if err != nil {
+		return err
+	}
+	toAddr, err := formatter.ParseBech32(services.XChain, to)
+	if err != nil {
+		return err
+	}
+	startingCount, err := runner.countXChainOutputs(to, assetID, isPropertyOutput)
+	if err != nil {
+		return err
+	}
+	c, ownerKey, err := runner.getCodecAndKey(ownerAddress)
+	if err != nil {
+		return err
+	}
+	mintUTXO, err := runner.findXChainUTXO(c, ownerAddress, assetID, func(out verify.State) bool {
+		_, ok := out.(*propertyfx.MintOutput)
+		return ok
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to find property fx mint output of asset %s owned by %s", assetID, ownerAddress)
+	}
+	mintOutput := mintUTXO.Out.(*propertyfx.MintOutput)
+	sigIndices, err := getSigIndices(&mintOutput.OutputOwners, ownerKey)
+	if err != nil {
+		return err
+	}
+
+	baseTx, feeSigners, err := runner.createFeePayingBaseTx(c, ownerKey)
+	if err != nil {
+		return err
+	}
+	tx := &avm.Tx{UnsignedTx: &avm.OperationTx{
+		BaseTx: baseTx,
+		Ops: []*avm.Operation{{
+			Asset:   avax.Asset{ID: assetID},
+			UTXOIDs: []*avax.UTXOID{&mintUTXO.UTXOID},
+			Op: &propertyfx.MintOperation{
+				MintInput:   secp256k1fx.Input{SigIndices: sigIndices},
+				MintOutput:  propertyfx.MintOutput{OutputOwners: mintOutput.OutputOwners},
+				OwnedOutput: propertyfx.OwnedOutput{OutputOwners: singleOwner(toAddr)},
+			},
+		}},
+	}}
+	if err := signXChainTx(c, tx, feeSigners, [][]*crypto.PrivateKeySECP256K1R{{ownerKey}}); err != nil {
+		return stacktrace.Propagate(err, "Failed to sign property fx mint OperationTx.")
+	}
+	if _, err := runner.issueAndAwaitXChainTx(tx); err != nil {
+		return err
+	}
+	return runner.verifyXChainOutputCount(to, assetID, isPropertyOutput, startingCount+1)
+}
+
+// BurnProperty burns a property of [assetID] owned by [holderAddress], paying the transaction fee with AVAX owned by
+// [feePayerAddress], blocks until the transaction is accepted, and verifies that [holderAddress] holds one less
+// property of [assetID]. Both addresses must be controlled by this runner's user.
+func (runner RPCWorkFlowRunner) BurnProperty(holderAddress string, feePayerAddress string, assetID ids.ID) error {
+	startingCount, err := runner.countXChainOutputs(holderAddress, assetID, isPropertyOutput)
+	if err != nil {
+		return err
+	}
+	c, holderKey, err := runner.getCodecAndKey(holderAddress)
+	if err != nil {
+		return err
+	}
+	ownedUTXO, err := runner.findXChainUTXO(c, holderAddress, assetID, isPropertyOutput)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to find property of asset %s owned by %s", assetID, holderAddress)
+	}
+	ownedOutput := ownedUTXO.Out.(*propertyfx.OwnedOutput)
+	sigIndices, err := getSigIndices(&ownedOutput.OutputOwners, holderKey)
+	if err != nil {
+		return err
+	}
+
+	// The holder of a property needn't hold any AVAX, so the fee is paid separately
+	_, feePayerKey, err := runner.getCodecAndKey(feePayerAddress)
+	if err != nil {
+		return err
+	}
+	baseTx, feeSigners, err := runner.createFeePayingBaseTx(c, feePayerKey)
+	if err != nil {
+		return err
+	}
+	tx := &avm.Tx{UnsignedTx: &avm.OperationTx{
+		BaseTx: baseTx,
+		Ops: []*avm.Operation{{
+			Asset:   avax.Asset{ID: assetID},
+			UTXOIDs: []*avax.UTXOID{&ownedUTXO.UTXOID},
+			Op:      &propertyfx.BurnOperation{Input: secp256k1fx.Input{SigIndices: sigIndices}},
+		}},
+	}}
+	if err := signXChainTx(c, tx, feeSigners, [][]*crypto.PrivateKeySECP256K1R{{holderKey}}); err != nil {
+		return stacktrace.Propagate(err, "Failed to sign property fx burn OperationTx.")
+	}
+	if _, err := runner.issueAndAwaitXChainTx(tx); err != nil {
+		return err
+	}
+	return runner.verifyXChainOutputCount(holderAddress, assetID, isPropertyOutput, startingCount-1)
+}
+
+// TransferAssetXChainToCChain exports [amount] of [assetID] from the X Chain to [cChainBech32Address], imports it to
+// [cChainHexAddress] on the C Chain, and verifies that the C Chain asset balance of [cChainHexAddress] increased by
+// [amount]. The key for [cChainBech32Address] must be imported into this runner's user on the C Chain.
+func (runner RPCWorkFlowRunner) TransferAssetXChainToCChain(
+	assetID ids.ID,
+	amount uint64,
+	cChainBech32Address string,
+	cChainHexAddress string) error {
+	ctx := context.Background()
+	hexAddr := common.HexToAddress(cChainHexAddress)
+	cEthClient, err := runner.client.CChainEthAPI()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to connect to the C Chain websocket")
+	}
+	startingBalance, err := cEthClient.AssetBalanceAt(ctx, hexAddr, assetID, nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to get C Chain balance of asset %s", assetID)
+	}
+
+	txID, err := runner.client.XChainAPI().Export(
+		runner.userPass,
+		nil, // from addrs
+		"",  // change addr
+		amount,
+		cChainBech32Address,
+		assetID.String(),
+	)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to export asset %s to C Chain address %s", assetID, cChainBech32Address)
+	}
+	if err := runner.AwaitXChainTransactionAcceptance(txID); err != nil {
+		return stacktrace.Propagate(err, "Failed to accept ExportTx: %s", txID)
+	}
+
+	importTxID, err := runner.client.CChainAPI().Import(runner.userPass, cChainHexAddress, services.XChain)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to import asset %s to C Chain address %s", assetID, cChainHexAddress)
+	}
+	if err := runner.AwaitCChainAtomicTransactionAcceptance(importTxID); err != nil {
+		return stacktrace.Propagate(err, "Failed to accept C Chain ImportTx: %s", importTxID)
+	}
+
+	expectedBalance := new(big.Int).Add(startingBalance, new(big.Int).SetUint64(amount))
+	balance, err := cEthClient.AssetBalanceAt(ctx, hexAddr, assetID, nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to get C Chain balance of asset %s", assetID)
+	}
+	if balance.Cmp(expectedBalance) != 0 {
+		return stacktrace.NewError("Found unexpected C Chain balance of asset %s for address: %s. Expected: %v, found: %v", assetID, cChainHexAddress, expectedBalance, balance)
+	}
+	return nil
+}
+
+// VerifyXChainAssetBalance verifies that the balance of [assetID] held by X Chain Address: [address] is [expectedBalance]
+func (runner RPCWorkFlowRunner) VerifyXChainAssetBalance(address string, assetID ids.ID, expectedBalance uint64) error {
+	actualBalance, err := runner.getXChainBalance(address, assetID.String())
+	if err != nil {
+		return err
+	}
+	if actualBalance != expectedBalance {
+		return stacktrace.NewError("Found unexpected X Chain balance of asset %s for address: %s. Expected: %v, found: %v", assetID, address, expectedBalance, actualBalance)
+	}
+	return nil
+}
+
+// ================= Helper functions ===================
+
+func (runner RPCWorkFlowRunner) getXChainBalance(address string, assetID string) (uint64, error) {
+	balance, err := runner.client.XChainAPI().GetBalance(address, assetID)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to retrieve X Chain balance of asset %s.", assetID)
+	}
+	return uint64(balance.Balance), nil
+}
+
+// getCodecAndKey returns an X Chain codec and the private key of [address], exported from this runner's user
+func (runner RPCWorkFlowRunner) getCodecAndKey(address string) (codec.Manager, *crypto.PrivateKeySECP256K1R, error) {
+	c, err := CreateXChainCodec()
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "Failed to initialize codec.")
+	}
+	pkStr, err := runner.client.XChainAPI().ExportKey(runner.userPass, address)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "Failed to export key of address %s", address)
+	}
+	key, err := ParsePrivateKey(pkStr)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "Failed to parse key of address %s", address)
+	}
+	return c, key, nil
+}
+
+// createFeePayingBaseTx returns a BaseTx that consumes a single AVAX UTXO owned by [key] to pay the transaction fee and
+// returns the change to [key], along with the signers of its inputs
+func (runner RPCWorkFlowRunner) createFeePayingBaseTx(c codec.Manager, key *crypto.PrivateKeySECP256K1R) (avm.BaseTx, [][]*crypto.PrivateKeySECP256K1R, error) {
+	txFeeResponse, err := runner.client.InfoAPI().GetTxFee()
+	if err != nil {
+		return avm.BaseTx{}, nil, stacktrace.Propagate(err, "Failed to get the network's transaction fee.")
+	}
+	txFee := uint64(txFeeResponse.TxFee)
+
+	registry, err := runner.ChainRegistry()
+	if err != nil {
+		return avm.BaseTx{}, nil, err
+	}
+	address := key.PublicKey().Address()
+	xChainAddress, err := registry.AddressFormatter().XChainAddress(address)
+	if err != nil {
+		return avm.BaseTx{}, nil, err
+	}
+	feeUTXO, err := runner.findXChainUTXO(c, xChainAddress, registry.AvaxAssetID(), func(out verify.State) bool {
+		transferOut, ok := out.(*secp256k1fx.TransferOutput)
+		return ok && transferOut.Amt >= txFee
+	})
+	if err != nil {
+		return avm.BaseTx{}, nil, stacktrace.Propagate(err, "Failed to find an AVAX UTXO to pay the transaction fee of %d", txFee)
+	}
+	feeOut := feeUTXO.Out.(*secp256k1fx.TransferOutput)
+	sigIndices, err := getSigIndices(&feeOut.OutputOwners, key)
+	if err != nil {
+		return avm.BaseTx{}, nil, err
+	}
+
+	outs := []*avax.TransferableOutput{}
+	if change := feeOut.Amt - txFee; change > 0 {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: registry.AvaxAssetID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          change,
+				OutputOwners: singleOwner(address),
+			},
+		})
+	}
+	ins := []*avax.TransferableInput{{
+		UTXOID: feeUTXO.UTXOID,
+		Asset:  avax.Asset{ID: registry.AvaxAssetID()},
+		In: &secp256k1fx.TransferInput{
+			Amt:   feeOut.Amt,
+			Input: secp256k1fx.Input{SigIndices: sigIndices},
+		},
+	}}
+	baseTx := avm.BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    registry.NetworkID(),
+		BlockchainID: registry.XChainID(),
+		Outs:         outs,
+		Ins:          ins,
+	}}
+	return baseTx, [][]*crypto.PrivateKeySECP256K1R{{key}}, nil
+}
+
+// issueAndAwaitXChainTx issues [tx] to the X Chain and blocks until it has been accepted
+func (runner RPCWorkFlowRunner) issueAndAwaitXChainTx(tx *avm.Tx) (ids.ID, error) {
+	txID, err := runner.client.XChainAPI().IssueTx(tx.Bytes())
+	if err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to issue transaction.")
+	}
+	if err := runner.AwaitXChainTransactionAcceptance(txID); err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to accept Tx: %s", txID)
+	}
+	return txID, nil
+}
+
+// findXChainUTXO returns the first UTXO of [assetID] held by [address] whose output satisfies [matches]
+func (runner RPCWorkFlowRunner) findXChainUTXO(c codec.Manager, address string, assetID ids.ID, matches func(verify.State) bool) (*avax.UTXO, error) {
+	utxos, err := runner.getXChainUTXOs(c, address)
+	if err != nil {
+		return nil, err
+	}
+	for _, utxo := range utxos {
+		if utxo.AssetID() == assetID && matches(utxo.Out) {
+			return utxo, nil
+		}
+	}
+	return nil, stacktrace.NewError("No matching UTXO of asset %s held by %s", assetID, address)
+}
+
+// countXChainOutputs returns the number of UTXOs of [assetID] held by [address] whose output satisfies [matches]
+func (runner RPCWorkFlowRunner) countXChainOutputs(address string, assetID ids.ID, matches func(verify.State) bool) (int, error) {
+	c, err := CreateXChainCodec()
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to initialize codec.")
+	}
+	utxos, err := runner.getXChainUTXOs(c, address)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, utxo := range utxos {
+		if utxo.AssetID() == assetID && matches(utxo.Out) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (runner RPCWorkFlowRunner) verifyXChainOutputCount(address string, assetID ids.ID, matches func(verify.State) bool, expectedCount int) error {
+	actualCount, err := runner.countXChainOutputs(address, assetID, matches)
+	if err != nil {
+		return err
+	}
+	if actualCount != expectedCount {
+		return stacktrace.NewError("Found unexpected number of outputs of asset %s held by address: %s. Expected: %d, found: %d", assetID, address, expectedCount, actualCount)
+	}
+	return nil
+}
+
+// getXChainUTXOs returns every UTXO held by [address], decoded with [c]
+func (runner RPCWorkFlowRunner) getXChainUTXOs(c codec.Manager, address string) ([]*avax.UTXO, error) {
+	utxosBytes, err := fetchXChainUTXOBytes(runner.client.XChainAPI(), address)
+	if err != nil {
+		return nil, err
+	}
+	utxos := make([]*avax.UTXO, len(utxosBytes))
+	for i, utxoBytes := range utxosBytes {
+		utxo := &avax.UTXO{}
+		if _, err := c.Unmarshal(utxoBytes, utxo); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to unmarshal utxo bytes.")
+		}
+		utxos[i] = utxo
+	}
+	return utxos, nil
+}
+
+// fetchXChainUTXOBytes pages through the X Chain API to return every UTXO held by [address]
+func fetchXChainUTXOBytes(xChainAPI *avm.Client, address string) ([][]byte, error) {
+	utxos := [][]byte{}
+	startAddress, startUTXOID := "", ""
+	for {
+		utxosBytes, endIndex, err := xChainAPI.GetUTXOs([]string{address}, maxUTXOsPerRequest, startAddress, startUTXOID)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to retrieve X Chain UTXOs.")
+		}
+		utxos = append(utxos, utxosBytes...)
+		if len(utxosBytes) < maxUTXOsPerRequest {
+			return utxos, nil
+		}
+		startAddress, startUTXOID = endIndex.Address, endIndex.UTXO
+	}
+}
+
+// signXChainTx signs the base inputs of [tx] with secp256k1fx credentials from [inputSigners] and its operations with
+// property fx credentials from [opSigners], in that order, and initializes the signed bytes of [tx]
+func signXChainTx(c codec.Manager, tx *avm.Tx, inputSigners, opSigners [][]*crypto.PrivateKeySECP256K1R) error {
+	unsignedBytes, err := c.Marshal(xChainCodecVersion, &tx.UnsignedTx)
+	if err != nil {
+		return err
+	}
+	hash := hashing.ComputeHash256(unsignedBytes)
+
+	signCredential := func(keys []*crypto.PrivateKeySECP256K1R) (secp256k1fx.Credential, error) {
+		cred := secp256k1fx.Credential{Sigs: make([][crypto.SECP256K1RSigLen]byte, len(keys))}
+		for i, key := range keys {
+			sig, err := key.SignHash(hash)
+			if err != nil {
+				return cred, err
+			}
+			copy(cred.Sigs[i][:], sig)
+		}
+		return cred, nil
+	}
+	for _, keys := range inputSigners {
+		cred, err := signCredential(keys)
+		if err != nil {
+			return err
+		}
+		tx.Creds = append(tx.Creds, &cred)
+	}
+	for _, keys := range opSigners {
+		cred, err := signCredential(keys)
+		if err != nil {
+			return err
+		}
+		tx.Creds = append(tx.Creds, &propertyfx.Credential{Credential: cred})
+	}
+
+	signedBytes, err := c.Marshal(xChainCodecVersion, tx)
+	if err != nil {
+		return err
+	}
+	tx.Initialize(unsignedBytes, signedBytes)
+	return nil
+}
+
+// getSigIndices returns the index of [key]'s address within [owners]
+func getSigIndices(owners *secp256k1fx.OutputOwners, key *crypto.PrivateKeySECP256K1R) ([]uint32, error) {
+	address := key.PublicKey().Address()
+	for i, addr := range owners.Addrs {
+		if addr == address {
+			return []uint32{uint32(i)}, nil
+		}
+	}
+	return nil, stacktrace.NewError("Address %s is not an owner of the output", address)
+}
+
+func singleOwner(address ids.ShortID) secp256k1fx.OutputOwners {
+	return secp256k1fx.OutputOwners{
+		Locktime:  0,
+		Threshold: 1,
+		Addrs:     []ids.ShortID{address},
+	}
+}
+
+func isNFTOutput(out verify.State) bool {
+	_, ok := out.(*nftfx.TransferOutput)
+	return ok
+}
+
+func isPropertyOutput(out verify.State) bool {
+	_, ok := out.(*propertyfx.OwnedOutput)
+	return ok
+}
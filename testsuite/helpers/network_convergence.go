@@ -0,0 +1,195 @@
+package helpers
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// The maximum number of UTXOs the X Chain will return from a single getUTXOs call
+	maxUTXOsPerRequest = 1024
+)
+
+// nodeBalanceObservation is the last balance and UTXO set that a node reported for an address
+type nodeBalanceObservation struct {
+	balance uint64
+	// Hex encoded UTXO bytes, sorted so that observations from different nodes can be compared directly
+	utxos []string
+	err   error
+}
+
+// observeBalanceFunc queries a single node for the balance and UTXO set of an address
+type observeBalanceFunc func(client *services.Client, address string) (uint64, []string, error)
+
+// VerifyXChainAVABalanceOnAllNodes verifies that every node in [clients] eventually reports [expectedBalance] for
+// X Chain Address: [address] and that all of the nodes agree on the UTXO set held by [address]
+func (runner RPCWorkFlowRunner) VerifyXChainAVABalanceOnAllNodes(
+	clients map[networks.ServiceID]*services.Client,
+	address string,
+	expectedBalance uint64) error {
+	return runner.awaitBalanceConvergence("X Chain", clients, address, expectedBalance, observeXChainAVABalance)
+}
+
+// VerifyPChainBalanceOnAllNodes verifies that every node in [clients] eventually reports [expectedBalance] for
+// P Chain Address: [address] and that all of the nodes agree on the UTXO set held by [address]
+func (runner RPCWorkFlowRunner) VerifyPChainBalanceOnAllNodes(
+	clients map[networks.ServiceID]*services.Client,
+	address string,
+	expectedBalance uint64) error {
+	return runner.awaitBalanceConvergence("P Chain", clients, address, expectedBalance, observePChainBalance)
+}
+
+// awaitBalanceConvergence polls every node in [clients] until they all report [expectedBalance] and an identical UTXO
+// set for [address], or [networkAcceptanceTimeout] elapses. On timeout, the returned error lists every lagging node
+// along with the last value it reported.
+func (runner RPCWorkFlowRunner) awaitBalanceConvergence(
+	chainName string,
+	clients map[networks.ServiceID]*services.Client,
+	address string,
+	expectedBalance uint64,
+	observe observeBalanceFunc) error {
+	if len(clients) == 0 {
+		return stacktrace.NewError("No clients provided to verify %s balance of address %s", chainName, address)
+	}
+
+	// Iterate in a fixed order so the reference node and the error messages are deterministic
+	serviceIDs := make([]networks.ServiceID, 0, len(clients))
+	for serviceID := range clients {
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	sort.Slice(serviceIDs, func(i, j int) bool { return serviceIDs[i] < serviceIDs[j] })
+
+	var observations map[networks.ServiceID]nodeBalanceObservation
+	var lagging []networks.ServiceID
+	pollStartTime := time.Now()
+	for {
+		observations = make(map[networks.ServiceID]nodeBalanceObservation, len(serviceIDs))
+		for _, serviceID := range serviceIDs {
+			balance, utxos, err := observe(clients[serviceID], address)
+			observations[serviceID] = nodeBalanceObservation{
+				balance: balance,
+				utxos:   utxos,
+				err:     err,
+			}
+		}
+
+		lagging = findLaggingNodes(serviceIDs, observations, expectedBalance)
+		if len(lagging) == 0 {
+			logrus.Debugf("%d nodes agree on %s balance %d for address %s", len(serviceIDs), chainName, expectedBalance, address)
+			return nil
+		}
+		if time.Since(pollStartTime) >= runner.networkAcceptanceTimeout {
+			break
+		}
+		logrus.Tracef("Waiting on %d nodes to converge on %s balance for address %s", len(lagging), chainName, address)
+		time.Sleep(time.Second)
+	}
+
+	laggingDescriptions := make([]string, 0, len(lagging))
+	for _, serviceID := range lagging {
+		observation := observations[serviceID]
+		if observation.err != nil {
+			laggingDescriptions = append(laggingDescriptions, fmt.Sprintf("%s (error: %v)", serviceID, observation.err))
+			continue
+		}
+		laggingDescriptions = append(
+			laggingDescriptions,
+			fmt.Sprintf("%s (balance: %d, %d UTXOs)", serviceID, observation.balance, len(observation.utxos)),
+		)
+	}
+	return stacktrace.NewError(
+		"Timed out waiting for %d nodes to converge on %s balance %d for address %s. Lagging nodes: %s",
+		len(serviceIDs),
+		chainName,
+		expectedBalance,
+		address,
+		strings.Join(laggingDescriptions, ", "),
+	)
+}
+
+// findLaggingNodes returns the service IDs of the nodes that either failed to report a balance, reported a balance other
+// than [expectedBalance], or reported a different UTXO set than the first node that has the expected balance
+func findLaggingNodes(
+	serviceIDs []networks.ServiceID,
+	observations map[networks.ServiceID]nodeBalanceObservation,
+	expectedBalance uint64) []networks.ServiceID {
+	var reference []string
+	foundReference := false
+	for _, serviceID := range serviceIDs {
+		observation := observations[serviceID]
+		if observation.err == nil && observation.balance == expectedBalance {
+			reference = observation.utxos
+			foundReference = true
+			break
+		}
+	}
+
+	lagging := []networks.ServiceID{}
+	for _, serviceID := range serviceIDs {
+		observation := observations[serviceID]
+		if !foundReference || observation.err != nil || observation.balance != expectedBalance || !equalUTXOSets(reference, observation.utxos) {
+			lagging = append(lagging, serviceID)
+		}
+	}
+	return lagging
+}
+
+// equalUTXOSets returns true if [a] and [b], which must both be sorted, contain the same UTXOs
+func equalUTXOSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// observeXChainAVABalance returns the AVAX balance and the full UTXO set of [address] as reported by [client]
+func observeXChainAVABalance(client *services.Client, address string) (uint64, []string, error) {
+	xChainAPI := client.XChainAPI()
+	balance, err := xChainAPI.GetBalance(address, services.AvaxAssetAlias)
+	if err != nil {
+		return 0, nil, stacktrace.Propagate(err, "Failed to retrieve X Chain balance.")
+	}
+
+	utxosBytes, err := fetchXChainUTXOBytes(xChainAPI, address)
+	if err != nil {
+		return 0, nil, err
+	}
+	utxos := make([]string, len(utxosBytes))
+	for i, utxoBytes := range utxosBytes {
+		utxos[i] = hex.EncodeToString(utxoBytes)
+	}
+	sort.Strings(utxos)
+	return uint64(balance.Balance), utxos, nil
+}
+
+// observePChainBalance returns the balance and the UTXO set of [address] as reported by [client]
+func observePChainBalance(client *services.Client, address string) (uint64, []string, error) {
+	pChainAPI := client.PChainAPI()
+	balance, err := pChainAPI.GetBalance(address)
+	if err != nil {
+		return 0, nil, stacktrace.Propagate(err, "Failed to retrieve P Chain balance.")
+	}
+	utxosBytes, _, err := pChainAPI.GetUTXOs([]string{address})
+	if err != nil {
+		return 0, nil, stacktrace.Propagate(err, "Failed to retrieve P Chain UTXOs.")
+	}
+	utxos := make([]string, len(utxosBytes))
+	for i, utxoBytes := range utxosBytes {
+		utxos[i] = hex.EncodeToString(utxoBytes)
+	}
+	sort.Strings(utxos)
+	return uint64(balance.Balance), utxos, nil
+}
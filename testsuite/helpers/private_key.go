@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"strings"
+
+	avalancheConstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/palantir/stacktrace"
+)
+
+// ParsePrivateKey parses a private key in the format returned by the keystore's exportKey, "PrivateKey-<CB58>"
+func ParsePrivateKey(privateKey string) (*crypto.PrivateKeySECP256K1R, error) {
+	if !strings.HasPrefix(privateKey, avalancheConstants.SecretKeyPrefix) {
+		return nil, stacktrace.NewError("Private key missing %s prefix", avalancheConstants.SecretKeyPrefix)
+	}
+	pkBytes, err := formatting.Decode(formatting.CB58, strings.TrimPrefix(privateKey, avalancheConstants.SecretKeyPrefix))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to decode private key")
+	}
+	factory := crypto.FactorySECP256K1R{}
+	skIntf, err := factory.ToPrivateKey(pkBytes)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to parse private key")
+	}
+	return skIntf.(*crypto.PrivateKeySECP256K1R), nil
+}
@@ -2,11 +2,12 @@ package helpers
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
 	"github.com/ava-labs/avalanche-testing/avalanche/services"
-	"github.com/ava-labs/avalanche-testing/utils/constants"
+	"github.com/ava-labs/avalanche-testing/utils/addressing"
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -18,13 +19,15 @@ import (
 
 // Basic Cnostants
 const (
-	AvaxAssetID                         = "AVAX"
 	DefaultStakingDelay                 = 20 * time.Second
 	DefaultStakingPeriod                = 72 * time.Hour
 	DefaultDelegationDelay              = 20 * time.Second // Time until delegation period should begin
 	stakingPeriodSynchronyDelay         = 3 * time.Second
 	DefaultDelegationPeriod             = 36 * time.Hour
 	DefaultDelegationFeeRate    float32 = 2
+
+	// Time between queries of the status of a transaction that hasn't been decided yet
+	defaultTxStatusPollInterval = time.Second
 )
 
 // RPCWorkFlowRunner executes standard testing workflows like funding accounts from
@@ -40,6 +43,17 @@ type RPCWorkFlowRunner struct {
 	// This timeout represents the time the RPCWorkFlowRunner will wait for some state change to be accepted
 	// and implemented by the underlying client.
 	networkAcceptanceTimeout time.Duration
+
+	// Time between queries of the status of a transaction that hasn't been decided yet
+	txStatusPollInterval time.Duration
+
+	// Built from the client the first time it is needed and shared by copies of this runner
+	chainRegistry *chainRegistryCache
+}
+
+type chainRegistryCache struct {
+	lock     sync.Mutex
+	registry *services.ChainRegistry
 }
 
 // NewRPCWorkFlowRunner ...
@@ -51,6 +65,8 @@ func NewRPCWorkFlowRunner(
 		client:                   client,
 		userPass:                 user,
 		networkAcceptanceTimeout: networkAcceptanceTimeout,
+		txStatusPollInterval:     defaultTxStatusPollInterval,
+		chainRegistry:            &chainRegistryCache{},
 	}
 }
 
@@ -59,6 +75,31 @@ func (runner RPCWorkFlowRunner) User() api.UserPass {
 	return runner.userPass
 }
 
+// ChainRegistry returns the registry of chain IDs of the network this runner talks to, building it from the runner's
+// client on first use
+func (runner RPCWorkFlowRunner) ChainRegistry() (*services.ChainRegistry, error) {
+	runner.chainRegistry.lock.Lock()
+	defer runner.chainRegistry.lock.Unlock()
+
+	if runner.chainRegistry.registry == nil {
+		registry, err := services.NewChainRegistry(runner.client)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to build chain registry.")
+		}
+		runner.chainRegistry.registry = registry
+	}
+	return runner.chainRegistry.registry, nil
+}
+
+// AddressFormatter returns a formatter for bech32 addresses on the network of the node this runner talks to
+func (runner RPCWorkFlowRunner) AddressFormatter() (addressing.Formatter, error) {
+	registry, err := runner.ChainRegistry()
+	if err != nil {
+		return addressing.Formatter{}, err
+	}
+	return registry.AddressFormatter(), nil
+}
+
 // ImportGenesisFunds imports the genesis private key to this user's keystore
 func (runner RPCWorkFlowRunner) ImportGenesisFunds() (string, error) {
 	client := runner.client
@@ -184,7 +225,7 @@ func (runner RPCWorkFlowRunner) FundXChainAddresses(addresses []string, amount u
 			nil, // from addrs
 			"",  // change addr
 			amount,
-			AvaxAssetID,
+			services.AvaxAssetAlias,
 			address,
 			"",
 		)
@@ -206,7 +247,7 @@ func (runner RPCWorkFlowRunner) SendAVAX(to string, amount uint64) (ids.ID, erro
 		nil, // from addrs
 		"",  // change addr
 		amount,
-		AvaxAssetID,
+		services.AvaxAssetAlias,
 		to,
 		"", // memo field
 	)
@@ -240,7 +281,7 @@ func (runner RPCWorkFlowRunner) SendAVAXBackAndForth(to string, amount, txFee, n
 			nil, // from addrs
 			"",  // change addr
 			amount-txFee*uint64(i),
-			AvaxAssetID,
+			services.AvaxAssetAlias,
 			to,
 			"", // memo field
 		)
@@ -279,7 +320,7 @@ func (runner RPCWorkFlowRunner) TransferAvaXChainToPChain(pChainAddress string,
 		nil, // from addrs
 		"",  // change addr
 		pChainAddress,
-		constants.XChainID.String(),
+		services.XChain,
 	)
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed import AVAX to pchainAddress %s", pChainAddress)
@@ -312,7 +353,7 @@ func (runner RPCWorkFlowRunner) TransferAvaPChainToXChain(
 		return stacktrace.Propagate(err, "Failed to accept ExportTx: %s", exportTxID)
 	}
 
-	txID, err := client.XChainAPI().ImportAVAX(runner.userPass, xChainAddress, constants.PlatformChainID.String())
+	txID, err := client.XChainAPI().ImportAVAX(runner.userPass, xChainAddress, services.PChain)
 	err = runner.AwaitXChainTransactionAcceptance(txID)
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to wait for acceptance of transaction on XChain.")
@@ -353,7 +394,7 @@ func (runner RPCWorkFlowRunner) AwaitXChainTransactionAcceptance(txID ids.ID) er
 		if status == choices.Rejected {
 			return stacktrace.NewError("Transaciton %s was rejected", txID)
 		}
-		time.Sleep(time.Second)
+		time.Sleep(runner.txStatusPollInterval)
 	}
 
 	return stacktrace.NewError("Timed out waiting for transaction %s to be accepted on the XChain.", txID)
@@ -401,7 +442,7 @@ func (runner RPCWorkFlowRunner) AwaitPChainTransactionAcceptance(txID ids.ID) er
 		if statusRes.Status == platformvm.Dropped || statusRes.Status == platformvm.Aborted {
 			return stacktrace.NewError("Abandoned Tx: %s because it had status: %s. Reason: %s", txID, statusRes.Status, statusRes.Reason)
 		}
-		time.Sleep(time.Second)
+		time.Sleep(runner.txStatusPollInterval)
 	}
 
 	return stacktrace.NewError("Timed out waiting for transaction %s to be accepted on the PChain.", txID)
@@ -433,7 +474,7 @@ func (runner RPCWorkFlowRunner) VerifyPChainBalance(address string, expectedBala
 // VerifyXChainAVABalance verifies that the balance of X Chain Address: [address] is [expectedBalance]
 func (runner RPCWorkFlowRunner) VerifyXChainAVABalance(address string, expectedBalance uint64) error {
 	client := runner.client.XChainAPI()
-	balance, err := client.GetBalance(address, AvaxAssetID)
+	balance, err := client.GetBalance(address, services.AvaxAssetAlias)
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to retrieve X Chain balance.")
 	}
@@ -467,8 +508,15 @@ func (runner RPCWorkFlowRunner) FundCChainAddresses(addrs []common.Address, avax
 		return err
 	}
 
+	formatter, err := runner.AddressFormatter()
+	if err != nil {
+		return err
+	}
+	cChainBech32, err := formatter.ConvertChain(xAddr, services.XChain, services.CChain)
+	if err != nil {
+		return err
+	}
 	for _, addr := range addrs {
-		cChainBech32 := fmt.Sprintf("C%s", xAddr[1:])
 		txID, err := avmClient.ExportAVAX(runner.userPass, nil, "", avaxAmount, cChainBech32)
 		if err != nil {
 			return fmt.Errorf("Failed to export AVAX to C-Chain: %w", err)
@@ -0,0 +1,133 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services/fakenode"
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRequestTimeout = 5 * time.Second
+
+func newFakeNodeRunner(node *fakenode.Node, acceptanceTimeout time.Duration) *RPCWorkFlowRunner {
+	runner := NewRPCWorkFlowRunner(
+		node.Client(testRequestTimeout),
+		api.UserPass{Username: "runner", Password: "9e3q2jb2ow48b12KLG8hv!"},
+		acceptanceTimeout,
+	)
+	runner.txStatusPollInterval = time.Millisecond
+	return runner
+}
+
+func TestAwaitXChainTransactionAcceptance(t *testing.T) {
+	node := fakenode.New()
+	defer node.Close()
+	runner := newFakeNodeRunner(node, 5*time.Second)
+
+	accepted := node.AddXChainTx(choices.Processing, choices.Processing, choices.Accepted)
+	assert.NoError(t, runner.AwaitXChainTransactionAcceptance(accepted))
+	assert.Equal(t, 3, node.Calls("avm.getTxStatus"))
+
+	rejected := node.AddXChainTx(choices.Processing, choices.Rejected)
+	assert.Error(t, runner.AwaitXChainTransactionAcceptance(rejected), "Expected an error for a rejected tx")
+
+	node.Fail("avm.getTxStatus", "database closed")
+	err := runner.AwaitXChainTransactionAcceptance(accepted)
+	if assert.Error(t, err, "Expected an error when the status can't be queried") {
+		assert.Contains(t, err.Error(), "database closed")
+	}
+}
+
+func TestAwaitXChainTransactionAcceptanceTimesOut(t *testing.T) {
+	node := fakenode.New()
+	defer node.Close()
+	runner := newFakeNodeRunner(node, 50*time.Millisecond)
+
+	processing := node.AddXChainTx(choices.Processing)
+	err := runner.AwaitXChainTransactionAcceptance(processing)
+	if assert.Error(t, err, "Expected a tx that never leaves processing to time out") {
+		assert.Contains(t, err.Error(), "Timed out")
+	}
+}
+
+func TestAwaitPChainTransactionAcceptance(t *testing.T) {
+	node := fakenode.New()
+	defer node.Close()
+	runner := newFakeNodeRunner(node, 5*time.Second)
+
+	committed := node.AddPChainTx(platformvm.Processing, platformvm.Committed)
+	assert.NoError(t, runner.AwaitPChainTransactionAcceptance(committed))
+
+	dropped := node.AddPChainTx(platformvm.Processing, platformvm.Dropped)
+	err := runner.AwaitPChainTransactionAcceptance(dropped)
+	if assert.Error(t, err, "Expected an error for a dropped tx") {
+		assert.Contains(t, err.Error(), "Dropped")
+	}
+
+	aborted := node.AddPChainTx(platformvm.Aborted)
+	assert.Error(t, runner.AwaitPChainTransactionAcceptance(aborted), "Expected an error for an aborted tx")
+
+	// Statuses of later txs don't leak into earlier ones
+	assert.NoError(t, runner.AwaitPChainTxs(committed))
+	assert.Error(t, runner.AwaitPChainTxs(committed, dropped), "Expected an error if any tx was dropped")
+}
+
+func TestTransferAvaXChainToPChainFailsOnDroppedImport(t *testing.T) {
+	node := fakenode.New()
+	defer node.Close()
+	runner := newFakeNodeRunner(node, 5*time.Second)
+
+	_, pChainAddress, err := runner.CreateDefaultAddresses()
+	assert.NoError(t, err)
+
+	node.ScriptNextXChainTx(choices.Processing, choices.Accepted)
+	node.ScriptNextPChainTx(platformvm.Processing, platformvm.Dropped)
+	err = runner.TransferAvaXChainToPChain(pChainAddress, 1000)
+	assert.Error(t, err, "Expected an error when the import tx is dropped")
+	assert.Equal(t, 1, node.Calls("avm.export"))
+	assert.Equal(t, 1, node.Calls("platform.importAVAX"))
+
+	// Unscripted txs are accepted, so the next transfer succeeds
+	assert.NoError(t, runner.TransferAvaXChainToPChain(pChainAddress, 1000))
+}
+
+func TestVerifyBalances(t *testing.T) {
+	node := fakenode.New()
+	defer node.Close()
+	runner := newFakeNodeRunner(node, 5*time.Second)
+
+	xChainAddress, pChainAddress, err := runner.CreateDefaultAddresses()
+	assert.NoError(t, err)
+	node.SetXChainBalance(xChainAddress, 5000)
+	node.SetPChainBalance(pChainAddress, 7000)
+
+	assert.NoError(t, runner.VerifyXChainAVABalance(xChainAddress, 5000))
+	assert.Error(t, runner.VerifyXChainAVABalance(xChainAddress, 4999), "Expected an error for a mismatched X Chain balance")
+	assert.NoError(t, runner.VerifyPChainBalance(pChainAddress, 7000))
+	assert.Error(t, runner.VerifyPChainBalance(pChainAddress, 0), "Expected an error for a mismatched P Chain balance")
+
+	node.Fail("platform.getBalance", "not bootstrapped")
+	assert.Error(t, runner.VerifyPChainBalance(pChainAddress, 7000), "Expected an error when the balance can't be queried")
+}
+
+func TestChainRegistryFromFakeNode(t *testing.T) {
+	node := fakenode.New()
+	defer node.Close()
+	runner := newFakeNodeRunner(node, 5*time.Second)
+
+	registry, err := runner.ChainRegistry()
+	assert.NoError(t, err)
+	assert.Equal(t, node.ChainID("X"), registry.XChainID())
+	assert.Equal(t, node.ChainID("C"), registry.CChainID())
+	assert.Equal(t, node.AvaxAssetID(), registry.AvaxAssetID())
+
+	// The registry is built once and shared by copies of the runner
+	runnerCopy := *runner
+	_, err = runnerCopy.AddressFormatter()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, node.Calls("info.getNetworkID"))
+}
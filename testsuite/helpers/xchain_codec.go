@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"github.com/ava-labs/avalanchego/utils/codec"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// X Chain Fx IDs, in the order the Fxs are registered with the AVM
+const (
+	SECP256K1FxID uint32 = iota
+	NFTFxID
+	PropertyFxID
+)
+
+const (
+	xChainCodecVersion = uint16(0)
+)
+
+// CreateXChainCodec returns a codec that can marshal and unmarshal X Chain transactions and UTXOs
+// Note: types must be registered in exactly the same order as the AVM registers them, so that the
+// type IDs written by this codec match the type IDs the nodes expect
+func CreateXChainCodec() (codec.Manager, error) {
+	c := codec.NewDefault()
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&avm.BaseTx{}),
+		c.RegisterType(&avm.CreateAssetTx{}),
+		c.RegisterType(&avm.OperationTx{}),
+		c.RegisterType(&avm.ImportTx{}),
+		c.RegisterType(&avm.ExportTx{}),
+
+		c.RegisterType(&secp256k1fx.TransferInput{}),
+		c.RegisterType(&secp256k1fx.MintOutput{}),
+		c.RegisterType(&secp256k1fx.TransferOutput{}),
+		c.RegisterType(&secp256k1fx.MintOperation{}),
+		c.RegisterType(&secp256k1fx.Credential{}),
+
+		c.RegisterType(&nftfx.MintOutput{}),
+		c.RegisterType(&nftfx.TransferOutput{}),
+		c.RegisterType(&nftfx.MintOperation{}),
+		c.RegisterType(&nftfx.TransferOperation{}),
+		c.RegisterType(&nftfx.Credential{}),
+
+		c.RegisterType(&propertyfx.MintOutput{}),
+		c.RegisterType(&propertyfx.OwnedOutput{}),
+		c.RegisterType(&propertyfx.MintOperation{}),
+		c.RegisterType(&propertyfx.BurnOperation{}),
+		c.RegisterType(&propertyfx.Credential{}),
+	)
+
+	codecManager := codec.NewDefaultManager()
+	errs.Add(codecManager.RegisterCodec(xChainCodecVersion, c))
+
+	return codecManager, errs.Err
+}
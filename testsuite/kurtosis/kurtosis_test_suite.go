@@ -5,11 +5,14 @@ import (
 
 	"github.com/kurtosis-tech/kurtosis-go/lib/testsuite"
 
+	"github.com/ava-labs/avalanche-testing/testsuite/tests/assets"
 	"github.com/ava-labs/avalanche-testing/testsuite/tests/bombard"
 	"github.com/ava-labs/avalanche-testing/testsuite/tests/cchain"
 	"github.com/ava-labs/avalanche-testing/testsuite/tests/conflictvtx"
 	"github.com/ava-labs/avalanche-testing/testsuite/tests/connected"
 	"github.com/ava-labs/avalanche-testing/testsuite/tests/duplicate"
+	"github.com/ava-labs/avalanche-testing/testsuite/tests/multisig"
+	"github.com/ava-labs/avalanche-testing/testsuite/tests/soak"
 	"github.com/ava-labs/avalanche-testing/testsuite/tests/spamchits"
 	"github.com/ava-labs/avalanche-testing/testsuite/tests/workflow"
 	"github.com/ava-labs/avalanche-testing/testsuite/verifier"
@@ -25,40 +28,203 @@ const (
 type AvalancheTestSuite struct {
 	ByzantineImageName string
 	NormalImageName    string
+
+	// The levels each test's nodes log at, overriding the levels the tests configure
+	NodeLogLevels NodeLogLevels
+
+	// Selects the tests to run by their tags
+	Filter TestFilter
+
+	// The parameters of the tests, which should start from DefaultTestParams
+	Params TestParams
 }
 
 // GetTests implements the Kurtosis TestSuite interface
 func (a AvalancheTestSuite) GetTests() map[string]testsuite.Test {
 	result := make(map[string]testsuite.Test)
+	for testName, registered := range a.getSelectedTests() {
+		test := registered.test
+		if levels, found := a.NodeLogLevels.forTest(testName); found {
+			test = nodeLogLevelsTest{
+				Test:   test,
+				levels: levels,
+			}
+		}
+		result[testName] = test
+	}
+	return result
+}
 
-	if a.ByzantineImageName != "" {
-		result["chitSpammerTest"] = spamchits.StakingNetworkUnrequestedChitSpammerTest{
+// GetTestMetadata returns the metadata of the tests that GetTests returns
+func (a AvalancheTestSuite) GetTestMetadata() map[string]TestMetadata {
+	result := make(map[string]TestMetadata)
+	for testName, registered := range a.getSelectedTests() {
+		result[testName] = registered.metadata
+	}
+	return result
+}
+
+// getSelectedTests returns the tests that the filter selects and whose required images are available
+func (a AvalancheTestSuite) getSelectedTests() map[string]registeredTest {
+	availableImages := map[Image]bool{
+		NormalImage:    a.NormalImageName != "",
+		ByzantineImage: a.ByzantineImageName != "",
+	}
+	selected := make(map[string]registeredTest)
+	for testName, registered := range a.getAllTests() {
+		if !a.Filter.Selects(registered.metadata) {
+			continue
+		}
+		hasImages := true
+		for _, image := range registered.metadata.RequiredImages {
+			hasImages = hasImages && availableImages[image]
+		}
+		if hasImages {
+			selected[testName] = registered
+		}
+	}
+	return selected
+}
+
+func (a AvalancheTestSuite) getAllTests() map[string]registeredTest {
+	result := make(map[string]registeredTest)
+
+	result["chitSpammerTest"] = registeredTest{
+		test: spamchits.StakingNetworkUnrequestedChitSpammerTest{
 			ByzantineImageName: a.ByzantineImageName,
 			NormalImageName:    a.NormalImageName,
-		}
-		result["conflictingTxsVertexTest"] = conflictvtx.StakingNetworkConflictingTxsVertexTest{
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{ByzantineTag, StakingTag},
+			RequiredImages:    []Image{NormalImage, ByzantineImage},
+			EstimatedDuration: 8 * time.Minute,
+		},
+	}
+	result["conflictingTxsVertexTest"] = registeredTest{
+		test: conflictvtx.StakingNetworkConflictingTxsVertexTest{
 			ByzantineImageName: a.ByzantineImageName,
 			NormalImageName:    a.NormalImageName,
-		}
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{ByzantineTag},
+			RequiredImages:    []Image{NormalImage, ByzantineImage},
+			EstimatedDuration: time.Minute,
+		},
+	}
+	result["bombardXChainTest"] = registeredTest{
+		test: bombard.StakingNetworkBombardTest{
+			ImageName: a.NormalImageName,
+			Params:    a.Params.BombardXChain,
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{LoadTag, StakingTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 6 * time.Minute,
+		},
+	}
+	result["fullyConnectedNetworkTest"] = registeredTest{
+		test: connected.StakingNetworkFullyConnectedTest{
+			ImageName: a.NormalImageName,
+			Verifier:  verifier.NetworkStateVerifier{},
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{SmokeTag, StakingTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 2 * time.Minute,
+		},
+	}
+	result["duplicateNodeIDTest"] = registeredTest{
+		test: duplicate.DuplicateNodeIDTest{
+			ImageName: a.NormalImageName,
+			Verifier:  verifier.NetworkStateVerifier{},
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{StakingTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 3 * time.Minute,
+		},
+	}
+	result["rpcWorkflowTest"] = registeredTest{
+		test: workflow.StakingNetworkRPCWorkflowTest{
+			ImageName: a.NormalImageName,
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{SmokeTag, StakingTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 2 * time.Minute,
+		},
+	}
+	result["assetWorkflowTest"] = registeredTest{
+		test: assets.StakingNetworkAssetWorkflowTest{
+			ImageName: a.NormalImageName,
+			Params:    a.Params.AssetWorkflow,
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{SmokeTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 2 * time.Minute,
+		},
+	}
+	result["multisigTest"] = registeredTest{
+		test: multisig.StakingNetworkMultisigTest{
+			ImageName: a.NormalImageName,
+			Params:    a.Params.Multisig,
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{SmokeTag, StakingTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 2 * time.Minute,
+		},
+	}
+	result["virtuousCorethTest"] = registeredTest{
+		test: cchain.NewVirtuousCChainTest(a.NormalImageName, a.Params.VirtuousCoreth),
+		metadata: TestMetadata{
+			Tags:              []Tag{CChainTag, LoadTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 3 * time.Minute,
+		},
 	}
-	result["bombardXChainTest"] = bombard.StakingNetworkBombardTest{
-		ImageName:         a.NormalImageName,
-		NumTxs:            1000,
-		TxFee:             1000000,
-		AcceptanceTimeout: 10 * time.Second,
+	result["cChainContractTest"] = registeredTest{
+		test: cchain.ContractTest{
+			ImageName: a.NormalImageName,
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{CChainTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 2 * time.Minute,
+		},
 	}
-	result["fullyConnectedNetworkTest"] = connected.StakingNetworkFullyConnectedTest{
-		ImageName: a.NormalImageName,
-		Verifier:  verifier.NetworkStateVerifier{},
+	result["cChainRPCConformanceTest"] = registeredTest{
+		test: cchain.RPCConformanceTest{
+			ImageName: a.NormalImageName,
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{CChainTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 2 * time.Minute,
+		},
 	}
-	result["duplicateNodeIDTest"] = duplicate.DuplicateNodeIDTest{
-		ImageName: a.NormalImageName,
-		Verifier:  verifier.NetworkStateVerifier{},
+	result["cChainTxPoolTest"] = registeredTest{
+		test: cchain.TxPoolTest{
+			ImageName: a.NormalImageName,
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{CChainTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: 2 * time.Minute,
+		},
 	}
-	result["rpcWorkflowTest"] = workflow.StakingNetworkRPCWorkflowTest{
-		ImageName: a.NormalImageName,
+	result["soakTest"] = registeredTest{
+		test: soak.StakingNetworkSoakTest{
+			ImageName: a.NormalImageName,
+			Params:    a.Params.Soak,
+		},
+		metadata: TestMetadata{
+			Tags:              []Tag{SoakTag, LoadTag, CChainTag, StakingTag},
+			RequiredImages:    []Image{NormalImage},
+			EstimatedDuration: a.Params.Soak.Duration,
+		},
 	}
-	result["virtuousCorethTest"] = cchain.NewVirtuousCChainTest(a.NormalImageName, 100, 3, 1000000, 3*time.Second)
 
 	return result
 }
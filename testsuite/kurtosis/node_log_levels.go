@@ -0,0 +1,112 @@
+package kurtosis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/testsuite"
+
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/palantir/stacktrace"
+)
+
+// NodeLogLevels chooses the levels the nodes of each test log at, overriding the levels the tests configure their
+// networks with. The zero value keeps every test's own levels.
+type NodeLogLevels struct {
+	// Level that the nodes of every test write to their log files, or empty to keep each test's own level
+	Default avalancheService.AvalancheLogLevel
+
+	// Test name -> level that the nodes of the test write to their log files, overriding Default
+	PerTest map[string]avalancheService.AvalancheLogLevel
+
+	// Level that the nodes of every test write to their output, or empty to use the level of their log files
+	Display avalancheService.AvalancheLogLevel
+}
+
+// ParseNodeLogLevels parses the node log level options of the suite, where [perTest] is a comma-separated list of
+// <test name>=<log level> pairs naming registered tests. Empty options are left unset.
+func ParseNodeLogLevels(defaultLevel string, displayLevel string, perTest string) (NodeLogLevels, error) {
+	levels := NodeLogLevels{PerTest: make(map[string]avalancheService.AvalancheLogLevel)}
+	if defaultLevel != "" {
+		level, err := avalancheService.ParseAvalancheLogLevel(defaultLevel)
+		if err != nil {
+			return NodeLogLevels{}, stacktrace.Propagate(err, "Failed to parse the default node log level")
+		}
+		levels.Default = level
+	}
+	if displayLevel != "" {
+		level, err := avalancheService.ParseAvalancheLogLevel(displayLevel)
+		if err != nil {
+			return NodeLogLevels{}, stacktrace.Propagate(err, "Failed to parse the node display log level")
+		}
+		levels.Display = level
+	}
+	if perTest == "" {
+		return levels, nil
+	}
+	// The registered tests don't depend on the suite's options, so the zero suite has them all
+	registeredTests := AvalancheTestSuite{}.getAllTests()
+	for _, pair := range strings.Split(perTest, ",") {
+		testName, levelStr, found := cutPair(pair)
+		if !found || testName == "" {
+			return NodeLogLevels{}, stacktrace.NewError("Per-test node log level '%s' is not of the form <test name>=<log level>", pair)
+		}
+		if _, registered := registeredTests[testName]; !registered {
+			testNames := make([]string, 0, len(registeredTests))
+			for registeredName := range registeredTests {
+				testNames = append(testNames, registeredName)
+			}
+			sort.Strings(testNames)
+			return NodeLogLevels{}, stacktrace.NewError("Per-test node log level names unknown test '%s', expected one of %v", testName, testNames)
+		}
+		level, err := avalancheService.ParseAvalancheLogLevel(levelStr)
+		if err != nil {
+			return NodeLogLevels{}, stacktrace.Propagate(err, "Failed to parse the node log level of test %s", testName)
+		}
+		levels.PerTest[testName] = level
+	}
+	return levels, nil
+}
+
+// forTest returns the overrides of the levels the nodes of the test named [testName] log at, and whether there are any
+func (levels NodeLogLevels) forTest(testName string) (avalancheService.NodeLogLevels, bool) {
+	overrides := avalancheService.NodeLogLevels{
+		File:    levels.Default,
+		Display: levels.Display,
+	}
+	if level, found := levels.PerTest[testName]; found {
+		overrides.File = level
+	}
+	return overrides, overrides.File != "" || overrides.Display != ""
+}
+
+// nodeLogLevelsTest runs a test with the log levels of its nodes overridden
+type nodeLogLevelsTest struct {
+	testsuite.Test
+
+	levels avalancheService.NodeLogLevels
+}
+
+// GetNetworkLoader implements the Kurtosis Test interface
+func (test nodeLogLevelsTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	loader, err := test.Test.GetNetworkLoader()
+	if err != nil {
+		return nil, err
+	}
+	avalancheLoader, ok := loader.(*avalancheNetwork.TestAvalancheNetworkLoader)
+	if !ok {
+		return nil, stacktrace.NewError("Node log levels can only be overridden for tests of Avalanche networks")
+	}
+	avalancheLoader.OverrideNodeLogLevels(test.levels)
+	return avalancheLoader, nil
+}
+
+func cutPair(pair string) (string, string, bool) {
+	index := strings.Index(pair, "=")
+	if index < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(pair[:index]), strings.TrimSpace(pair[index+1:]), true
+}
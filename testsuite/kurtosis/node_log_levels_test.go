@@ -0,0 +1,32 @@
+package kurtosis
+
+import (
+	"testing"
+
+	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNodeLogLevels(t *testing.T) {
+	levels, err := ParseNodeLogLevels("warn", "", "chitSpammerTest=verbo, bombardXChainTest=off")
+	assert.NoError(t, err)
+
+	overrides, found := levels.forTest("chitSpammerTest")
+	assert.True(t, found)
+	assert.Equal(t, avalancheService.NodeLogLevels{File: avalancheService.VERBOSE}, overrides)
+	overrides, found = levels.forTest("rpcWorkflowTest")
+	assert.True(t, found)
+	assert.Equal(t, avalancheService.NodeLogLevels{File: avalancheService.WARN}, overrides)
+
+	_, found = NodeLogLevels{}.forTest("rpcWorkflowTest")
+	assert.False(t, found, "Expected no overrides without node log level options")
+
+	_, err = ParseNodeLogLevels("", "", "chitSpammerTest")
+	assert.Error(t, err, "Expected an error for a per-test level without a test name")
+	_, err = ParseNodeLogLevels("", "loud", "")
+	assert.Error(t, err, "Expected an error for an unknown display level")
+	_, err = ParseNodeLogLevels("", "", "chitSpamerTest=verbo")
+	assert.Error(t, err, "Expected an error for a level of an unknown test")
+	_, err = ParseNodeLogLevels("", "", "chitSpammerTest=trace")
+	assert.Error(t, err, "Expected an error for a level avalanchego v1.0.5 doesn't support")
+}
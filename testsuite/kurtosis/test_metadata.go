@@ -0,0 +1,163 @@
+package kurtosis
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/testsuite"
+
+	"github.com/palantir/stacktrace"
+)
+
+// Tag categorizes tests, so that a subset of the suite can be selected
+type Tag string
+
+// Tags that tests can carry
+const (
+	// Fast tests that cover the main workflows, suitable for running on every commit
+	SmokeTag Tag = "smoke"
+
+	// Tests that run nodes with Byzantine behaviour
+	ByzantineTag Tag = "byzantine"
+
+	// Tests that put a network under heavy load
+	LoadTag Tag = "load"
+
+	// Tests of the C Chain
+	CChainTag Tag = "cchain"
+
+	// Tests of staking and validator sets
+	StakingTag Tag = "staking"
+
+	// Tests that run for hours, which are only selected when the tag is explicitly included
+	SoakTag Tag = "soak"
+)
+
+var allTags = []Tag{SmokeTag, ByzantineTag, LoadTag, CChainTag, StakingTag, SoakTag}
+
+// Tags that a test carrying them is only selected with when they're in the included tags, even when every test would
+// otherwise be selected
+var optInTags = []Tag{SoakTag}
+
+// Image is a Docker image that tests may require to launch their nodes
+type Image string
+
+// Images that tests can require
+const (
+	// The Avalanche Go image that normal nodes are launched from
+	NormalImage Image = "avalanche-go"
+
+	// The Byzantine Avalanche Go image that nodes with Byzantine behaviour are launched from
+	ByzantineImage Image = "byzantine-go"
+)
+
+// TestMetadata describes a test of the suite
+type TestMetadata struct {
+	// Tags categorizing the test
+	Tags []Tag
+
+	// Images the test launches nodes from, without which the test can't run
+	RequiredImages []Image
+
+	// Rough time the test takes to run once its network is set up
+	EstimatedDuration time.Duration
+}
+
+// HasTag returns whether the test carries [tag]
+func (metadata TestMetadata) HasTag(tag Tag) bool {
+	for _, testTag := range metadata.Tags {
+		if testTag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// TestFilter selects the tests of the suite to run by their tags. The zero value selects every test except those
+// carrying an opt-in tag, such as the soak tests.
+type TestFilter struct {
+	// Tests are selected only if they carry at least one of these tags, unless it's empty
+	IncludedTags []Tag
+
+	// Tests carrying any of these tags are never selected
+	ExcludedTags []Tag
+}
+
+// ParseTestFilter parses a filter from comma-separated lists of the tags to include and exclude
+func ParseTestFilter(includedTags string, excludedTags string) (TestFilter, error) {
+	included, err := parseTags(includedTags)
+	if err != nil {
+		return TestFilter{}, stacktrace.Propagate(err, "Failed to parse the included tags")
+	}
+	excluded, err := parseTags(excludedTags)
+	if err != nil {
+		return TestFilter{}, stacktrace.Propagate(err, "Failed to parse the excluded tags")
+	}
+	return TestFilter{
+		IncludedTags: included,
+		ExcludedTags: excluded,
+	}, nil
+}
+
+// Selects returns whether the filter selects a test described by [metadata]
+func (filter TestFilter) Selects(metadata TestMetadata) bool {
+	for _, tag := range filter.ExcludedTags {
+		if metadata.HasTag(tag) {
+			return false
+		}
+	}
+	for _, tag := range optInTags {
+		if metadata.HasTag(tag) && !filter.includes(tag) {
+			return false
+		}
+	}
+	if len(filter.IncludedTags) == 0 {
+		return true
+	}
+	for _, tag := range filter.IncludedTags {
+		if metadata.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// includes returns whether [tag] is one of the included tags
+func (filter TestFilter) includes(tag Tag) bool {
+	for _, includedTag := range filter.IncludedTags {
+		if includedTag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// registeredTest is a test of the suite along with its metadata
+type registeredTest struct {
+	test     testsuite.Test
+	metadata TestMetadata
+}
+
+func parseTags(tagsStr string) ([]Tag, error) {
+	tags := make([]Tag, 0)
+	if tagsStr == "" {
+		return tags, nil
+	}
+	for _, tagStr := range strings.Split(tagsStr, ",") {
+		tag := Tag(strings.ToLower(strings.TrimSpace(tagStr)))
+		if !isKnownTag(tag) {
+			return nil, stacktrace.NewError("Unknown test tag '%s', expected one of %v", tagStr, allTags)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func isKnownTag(tag Tag) bool {
+	for _, knownTag := range allTags {
+		if tag == knownTag {
+			return true
+		}
+	}
+	return false
+}
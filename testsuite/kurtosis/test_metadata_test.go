@@ -0,0 +1,50 @@
+package kurtosis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestFilter(t *testing.T) {
+	filter, err := ParseTestFilter("smoke, cchain", "load")
+	assert.NoError(t, err)
+
+	assert.True(t, filter.Selects(TestMetadata{Tags: []Tag{SmokeTag, StakingTag}}))
+	assert.False(t, filter.Selects(TestMetadata{Tags: []Tag{CChainTag, LoadTag}}), "Expected excluded tags to win over included tags")
+	assert.False(t, filter.Selects(TestMetadata{Tags: []Tag{StakingTag}}))
+	assert.True(t, TestFilter{}.Selects(TestMetadata{}), "Expected the zero filter to select untagged tests")
+
+	soakTest := TestMetadata{Tags: []Tag{SoakTag, LoadTag}}
+	assert.False(t, TestFilter{}.Selects(soakTest), "Expected soak tests to be skipped unless their tag is included")
+	assert.False(t, TestFilter{IncludedTags: []Tag{LoadTag}}.Selects(soakTest), "Expected soak tests to be skipped unless their tag is included")
+	assert.True(t, TestFilter{IncludedTags: []Tag{SoakTag}}.Selects(soakTest))
+
+	_, err = ParseTestFilter("nightly", "")
+	assert.Error(t, err, "Expected an error for an unknown tag")
+}
+
+func TestGetTestsSkipsTestsWithoutImages(t *testing.T) {
+	suite := AvalancheTestSuite{
+		NormalImageName: "avaplatform/avalanchego",
+		Filter:          TestFilter{IncludedTags: []Tag{ByzantineTag, SmokeTag}},
+	}
+	tests := suite.GetTests()
+	assert.NotContains(t, tests, "chitSpammerTest")
+	assert.Contains(t, tests, "rpcWorkflowTest")
+	assert.NotContains(t, tests, "bombardXChainTest")
+	assert.NotContains(t, AvalancheTestSuite{NormalImageName: "avaplatform/avalanchego"}.GetTests(), "soakTest")
+
+	suite.ByzantineImageName = "avaplatform/avalanche-byzantine"
+	assert.Contains(t, suite.GetTests(), "chitSpammerTest")
+}
+
+func TestEveryTestHasATag(t *testing.T) {
+	suite := AvalancheTestSuite{
+		NormalImageName:    "avaplatform/avalanchego",
+		ByzantineImageName: "avaplatform/avalanche-byzantine",
+	}
+	for testName, registered := range suite.getAllTests() {
+		assert.NotEmpty(t, registered.metadata.Tags, "Expected test %s to carry at least one tag", testName)
+	}
+}
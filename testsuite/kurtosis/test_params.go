@@ -0,0 +1,57 @@
+package kurtosis
+
+import (
+	"github.com/ava-labs/avalanche-testing/testsuite/params"
+	"github.com/ava-labs/avalanche-testing/testsuite/tests/assets"
+	"github.com/ava-labs/avalanche-testing/testsuite/tests/bombard"
+	"github.com/ava-labs/avalanche-testing/testsuite/tests/cchain"
+	"github.com/ava-labs/avalanche-testing/testsuite/tests/multisig"
+	"github.com/ava-labs/avalanche-testing/testsuite/tests/soak"
+	"github.com/palantir/stacktrace"
+)
+
+// TestParams are the parameters of the suite's tests that take any
+type TestParams struct {
+	BombardXChain  bombard.Params
+	AssetWorkflow  assets.Params
+	Multisig       multisig.Params
+	VirtuousCoreth cchain.Params
+	Soak           soak.Params
+}
+
+// DefaultTestParams returns the parameters the suite's tests run with unless they're overridden
+func DefaultTestParams() TestParams {
+	return TestParams{
+		BombardXChain:  bombard.DefaultParams(),
+		AssetWorkflow:  assets.DefaultParams(),
+		Multisig:       multisig.DefaultParams(),
+		VirtuousCoreth: cchain.DefaultParams(),
+		Soak:           soak.DefaultParams(),
+	}
+}
+
+// Apply applies [overrides] to the parameters of the tests they name, validating the resulting parameters
+func (p *TestParams) Apply(overrides params.Overrides) error {
+	testParams := p.byTestName()
+	for testName, fields := range overrides {
+		target, found := testParams[testName]
+		if !found {
+			return stacktrace.NewError("Test %s doesn't exist or takes no parameters", testName)
+		}
+		if err := params.Apply(target, fields); err != nil {
+			return stacktrace.Propagate(err, "Failed to apply the parameters of test %s", testName)
+		}
+	}
+	return nil
+}
+
+// byTestName returns pointers to the parameters of each test that takes any, keyed by test name
+func (p *TestParams) byTestName() map[string]interface{} {
+	return map[string]interface{}{
+		"bombardXChainTest":  &p.BombardXChain,
+		"assetWorkflowTest":  &p.AssetWorkflow,
+		"multisigTest":       &p.Multisig,
+		"virtuousCorethTest": &p.VirtuousCoreth,
+		"soakTest":           &p.Soak,
+	}
+}
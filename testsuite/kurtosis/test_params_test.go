@@ -0,0 +1,24 @@
+package kurtosis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/testsuite/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestParamsScaleExecutionTimeout(t *testing.T) {
+	testParams := DefaultTestParams()
+	suite := AvalancheTestSuite{NormalImageName: "avaplatform/avalanchego", Params: testParams}
+	defaultTimeout := suite.GetTests()["bombardXChainTest"].GetExecutionTimeout()
+
+	assert.NoError(t, testParams.Apply(params.Overrides{"bombardXChainTest": {"NumTxs": "50000"}}))
+	assert.Equal(t, uint64(50000), testParams.BombardXChain.NumTxs)
+	assert.Equal(t, 10*time.Second, testParams.BombardXChain.AcceptanceTimeout, "Expected parameters without overrides to keep their defaults")
+	suite.Params = testParams
+	assert.True(t, suite.GetTests()["bombardXChainTest"].GetExecutionTimeout() > defaultTimeout)
+
+	assert.Error(t, testParams.Apply(params.Overrides{"chitSpammerTest": {"NumTxs": "1"}}), "Expected an error for a test without parameters")
+	assert.Error(t, testParams.Apply(params.Overrides{"bombardXChainTest": {"NumTxs": "0"}}), "Expected an error for invalid parameters")
+}
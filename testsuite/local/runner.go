@@ -0,0 +1,90 @@
+package local
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/testsuite"
+
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Directory, in a test's directory, that holds the working directories of the test's nodes
+	nodesDirname = "nodes"
+)
+
+// Runner runs tests of the suite against networks of local avalanchego processes rather than Docker containers, so
+// that they can run from go test without Docker or Kurtosis
+type Runner struct {
+	// Image name -> avalanchego binary that nodes of the image are launched from
+	BinaryPaths map[string]string
+
+	// Directory that every test gets a directory in, holding its nodes' working directories. Files that the suite
+	// keeps on the suite execution volume under Kurtosis, like test results, are kept here too.
+	RootDirpath string
+}
+
+// Run launches the network of [test], named [testName], runs the test against it, and stops the network again. An error
+// is returned if the network can't be launched or the test fails or doesn't finish within its execution timeout.
+func (runner Runner) Run(testName string, test testsuite.Test) error {
+	loader, err := test.GetNetworkLoader()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to get the network loader of test %v", testName)
+	}
+	avalancheLoader, ok := loader.(*avalancheNetwork.TestAvalancheNetworkLoader)
+	if !ok {
+		return stacktrace.NewError("Test %v doesn't run against an Avalanche network, so it can't run locally", testName)
+	}
+	services.SetSuiteExecutionDirpath(runner.RootDirpath)
+
+	nodesDirpath := filepath.Join(runner.RootDirpath, testName, nodesDirname)
+	localNetwork, err := avalancheNetwork.NewLocalNetwork(avalancheLoader, runner.BinaryPaths, nodesDirpath)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create the local network of test %v", testName)
+	}
+	defer localNetwork.Close()
+
+	logrus.Infof("Launching the nodes of test %v in %v...", testName, nodesDirpath)
+	network, err := localNetwork.Launch()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to launch the local network of test %v", testName)
+	}
+	logrus.Infof("Launched the nodes of test %v; running the test...", testName)
+	return runTest(test, network, test.GetExecutionTimeout())
+}
+
+// runTest runs [test] against [network], turning the panic that fails a Kurtosis test into an error. Like under
+// Kurtosis, a test that times out is left running.
+func runTest(test testsuite.Test, network networks.Network, timeout time.Duration) error {
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				result <- testFailure(recovered)
+			}
+		}()
+		test.Run(network, testsuite.TestContext{})
+		result <- nil
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(timeout):
+		return stacktrace.NewError("Test didn't finish within its execution timeout of %v", timeout)
+	}
+}
+
+// testFailure returns the error a test failed with, given the value it panicked with
+func testFailure(recovered interface{}) error {
+	if err, ok := recovered.(error); ok {
+		return stacktrace.Propagate(err, "Test failed")
+	}
+	return stacktrace.NewError("Test failed: %v", fmt.Sprint(recovered))
+}
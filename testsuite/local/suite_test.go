@@ -0,0 +1,102 @@
+package local
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+
+	suite "github.com/ava-labs/avalanche-testing/testsuite/kurtosis"
+	"github.com/stretchr/testify/assert"
+)
+
+// Environment variables configuring TestSuite
+const (
+	// Path of the avalanchego binary that normal nodes run; the suite is skipped if it's unset
+	binaryEnvVar = "AVALANCHEGO_BINARY"
+
+	// Path of the Byzantine avalanchego binary; tests requiring Byzantine nodes are skipped if it's unset
+	byzantineBinaryEnvVar = "BYZANTINE_AVALANCHEGO_BINARY"
+
+	// Directory that the tests' nodes are kept in, which defaults to a new temporary directory
+	rootDirEnvVar = "LOCAL_TEST_DIR"
+
+	// Comma-separated tags selecting the tests to run, as passed to the suite's --tags flag
+	tagsEnvVar = "TEST_TAGS"
+
+	// Comma-separated tags excluding tests, as passed to the suite's --exclude-tags flag
+	excludedTagsEnvVar = "EXCLUDED_TEST_TAGS"
+)
+
+// TestSuite runs the tests of the suite against local avalanchego processes. A single test can be selected with -run,
+// e.g. go test ./testsuite/local -run TestSuite/rpcWorkflowTest -timeout 30m
+func TestSuite(t *testing.T) {
+	binaryPath := os.Getenv(binaryEnvVar)
+	if binaryPath == "" {
+		t.Skipf("%v isn't set to an avalanchego binary", binaryEnvVar)
+	}
+	filter, err := suite.ParseTestFilter(os.Getenv(tagsEnvVar), os.Getenv(excludedTagsEnvVar))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	binaryPaths := map[string]string{string(suite.NormalImage): binaryPath}
+	avalancheSuite := suite.AvalancheTestSuite{
+		NormalImageName: string(suite.NormalImage),
+		Filter:          filter,
+		Params:          suite.DefaultTestParams(),
+	}
+	if byzantineBinaryPath := os.Getenv(byzantineBinaryEnvVar); byzantineBinaryPath != "" {
+		binaryPaths[string(suite.ByzantineImage)] = byzantineBinaryPath
+		avalancheSuite.ByzantineImageName = string(suite.ByzantineImage)
+	}
+
+	rootDirpath := os.Getenv(rootDirEnvVar)
+	if rootDirpath == "" {
+		rootDirpath, err = ioutil.TempDir("", "avalanche-testing")
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+	t.Logf("Keeping the nodes of the tests in %v", rootDirpath)
+	runner := Runner{
+		BinaryPaths: binaryPaths,
+		RootDirpath: rootDirpath,
+	}
+
+	tests := avalancheSuite.GetTests()
+	testNames := make([]string, 0, len(tests))
+	for testName := range tests {
+		testNames = append(testNames, testName)
+	}
+	sort.Strings(testNames)
+	for _, testName := range testNames {
+		test := tests[testName]
+		t.Run(testName, func(t *testing.T) {
+			assert.NoError(t, runner.Run(testName, test))
+		})
+	}
+}
+
+func TestRunRejectsMissingBinaries(t *testing.T) {
+	tests := suite.AvalancheTestSuite{
+		NormalImageName: string(suite.NormalImage),
+		Params:          suite.DefaultTestParams(),
+	}.GetTests()
+	runner := Runner{
+		BinaryPaths: map[string]string{string(suite.NormalImage): "/nonexistent/avalanchego"},
+		RootDirpath: t.TempDir(),
+	}
+	testNames := make([]string, 0, len(tests))
+	for testName := range tests {
+		testNames = append(testNames, testName)
+	}
+	sort.Strings(testNames)
+	assert.NotEmpty(t, testNames)
+	for _, testName := range testNames {
+		err := runner.Run(testName, tests[testName])
+		if assert.Error(t, err, "Expected an error for a missing binary running %v", testName) {
+			assert.Contains(t, err.Error(), "avalanchego binary", "Unexpected error running %v", testName)
+		}
+	}
+}
@@ -3,10 +3,18 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/logging"
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
 	testsuite "github.com/ava-labs/avalanche-testing/testsuite/kurtosis"
+	"github.com/ava-labs/avalanche-testing/testsuite/params"
 	"github.com/kurtosis-tech/kurtosis-go/lib/client"
 	"github.com/sirupsen/logrus"
-	"os"
 )
 
 func main() {
@@ -31,7 +39,7 @@ func main() {
 	logLevelArg := flag.String(
 		"log-level",
 		"",
-		"String corresponding to Logrus log level that the test suite will output with",
+		fmt.Sprintf("Log level that the test suite will output with, which must be one of %v", logging.GetAcceptableStrings()),
 	)
 	servicesDirpathArg := flag.String(
 		"services-relative-dirpath",
@@ -39,6 +47,10 @@ func main() {
 		"Dirpath, relative to the root of the suite execution volume, where directories for each service should be created")
 
 	// ----------------------- Avalanche testing-custom params ---------------------------------
+	logFormatArg := flag.String(
+		"log-format",
+		logging.TextFormat,
+		fmt.Sprintf("Format that the test suite will output logs in, which must be one of %v", []string{logging.TextFormat, logging.JSONFormat}))
 	avalancheGoImageArg := flag.String(
 		"avalanche-go-image",
 		"",
@@ -47,21 +59,138 @@ func main() {
 		"byzantine-go-image",
 		"",
 		"Name of Byzantine Avalanche Go Docker image that will be used to launch Avalanche Go nodes with Byzantine behaviour")
+	nodeLogLevelArg := flag.String(
+		"node-log-level",
+		"",
+		"Log level that the Avalanche Go nodes of every test write to their log files, overriding the level each test configures")
+	nodeDisplayLogLevelArg := flag.String(
+		"node-display-log-level",
+		"",
+		"Log level that the Avalanche Go nodes of every test write to their output, which defaults to the level of their log files")
+	testNodeLogLevelsArg := flag.String(
+		"test-node-log-levels",
+		"",
+		"Comma-separated list of <test name>=<log level> pairs overriding the node log level of single tests")
+
+	tagsArg := flag.String(
+		"tags",
+		"",
+		"Comma-separated list of tags, of which tests must carry at least one to run (e.g. smoke); empty runs every test")
+	excludedTagsArg := flag.String(
+		"exclude-tags",
+		"",
+		"Comma-separated list of tags that tests must not carry to run (e.g. load,byzantine)")
+
+	testParamsFileArg := flag.String(
+		"test-params-file",
+		"",
+		"Path of a JSON or YAML (.yaml or .yml) file mapping test names to the parameters that replace their defaults, e.g. {\"bombardXChainTest\": {\"NumTxs\": 50000}}")
+	var testParamArgs testParamFlags
+	flag.Var(
+		&testParamArgs,
+		"test-param",
+		"Test parameter of the form <test name>.<field name>=<value> replacing its default and any value in the parameter file; can be repeated, or hold a comma-separated list")
 
 	flag.Parse()
 
-	level, err := logrus.ParseLevel(*logLevelArg)
+	level := logging.LevelFromString(*logLevelArg)
+	if level == nil {
+		fmt.Fprintf(os.Stderr, "Log level '%s' is not one of %s\n", *logLevelArg, strings.Join(logging.GetAcceptableStrings(), ", "))
+		os.Exit(1)
+	}
+
+	// The suite is also run without a test to report its metadata, in which case there's no test to log for
+	logFilepath := ""
+	if *testArg != "" {
+		logFilepath = services.SuiteExecutionPath(filepath.Join(*servicesDirpathArg, *testArg+".log"))
+	}
+	logFile, err := logging.ConfigureTestLogger(logrus.StandardLogger(), *testArg, *level, *logFormatArg, logFilepath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "An error occurred parsing the log level string: %v\n", err)
+		fmt.Fprintf(os.Stderr, "An error occurred configuring the test suite's logs: %v\n", err)
+		os.Exit(1)
+	}
+
+	nodeLogLevels, err := testsuite.ParseNodeLogLevels(*nodeLogLevelArg, *nodeDisplayLogLevelArg, *testNodeLogLevelsArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "An error occurred parsing the node log levels: %v\n", err)
+		os.Exit(1)
+	}
+
+	filter, err := testsuite.ParseTestFilter(*tagsArg, *excludedTagsArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "An error occurred parsing the test filter: %v\n", err)
+		os.Exit(1)
+	}
+
+	testParams, err := loadTestParams(*testParamsFileArg, testParamArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "An error occurred loading the test parameters: %v\n", err)
 		os.Exit(1)
 	}
-	logrus.SetLevel(level)
 
 	logrus.Debugf("Byzantine image name: %s", *byzantineGoImageArg)
 	testSuite := testsuite.AvalancheTestSuite{
 		ByzantineImageName: *byzantineGoImageArg,
 		NormalImageName:    *avalancheGoImageArg,
+		NodeLogLevels:      nodeLogLevels,
+		Filter:             filter,
+		Params:             testParams,
+	}
+	if *testArg == "" {
+		logSelectedTests(testSuite)
 	}
 	exitCode := client.Run(testSuite, *metadataFilepath, *servicesDirpathArg, *testArg, *kurtosisApiIpArg)
+	logFile.Close()
 	os.Exit(exitCode)
 }
+
+// logSelectedTests logs the tests of [testSuite] that will run, and how long they're expected to take
+func logSelectedTests(testSuite testsuite.AvalancheTestSuite) {
+	metadata := testSuite.GetTestMetadata()
+	testNames := make([]string, 0, len(metadata))
+	var estimatedDuration time.Duration
+	for testName, testMetadata := range metadata {
+		testNames = append(testNames, testName)
+		estimatedDuration += testMetadata.EstimatedDuration
+	}
+	sort.Strings(testNames)
+	logrus.Infof("Selected %d tests, estimated to take %s in total: %s", len(testNames), estimatedDuration, strings.Join(testNames, ", "))
+}
+
+// testParamFlags collects the values of every --test-param flag
+type testParamFlags []string
+
+func (flags *testParamFlags) String() string {
+	return strings.Join(*flags, ",")
+}
+
+func (flags *testParamFlags) Set(value string) error {
+	for _, param := range strings.Split(value, ",") {
+		if param = strings.TrimSpace(param); param != "" {
+			*flags = append(*flags, param)
+		}
+	}
+	return nil
+}
+
+// loadTestParams returns the default test parameters overridden by the parameter file at [paramsFilepath], if any,
+// and then by [paramFlags]
+func loadTestParams(paramsFilepath string, paramFlags testParamFlags) (testsuite.TestParams, error) {
+	overrides := params.Overrides{}
+	if paramsFilepath != "" {
+		fileOverrides, err := params.LoadFile(paramsFilepath)
+		if err != nil {
+			return testsuite.TestParams{}, err
+		}
+		overrides = fileOverrides
+	}
+	flagOverrides, err := params.ParseFlags(paramFlags)
+	if err != nil {
+		return testsuite.TestParams{}, err
+	}
+	testParams := testsuite.DefaultTestParams()
+	if err := testParams.Apply(overrides.Merge(flagOverrides)); err != nil {
+		return testsuite.TestParams{}, err
+	}
+	return testParams, nil
+}
@@ -0,0 +1,188 @@
+package monitoring
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/logging"
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// Name under which a failure to reach a node's health API is reported
+	unreachableCheckName = "getLiveness"
+)
+
+// Transition is a change in the liveness of a node, as seen by a HealthMonitor
+type Transition struct {
+	ServiceID networks.ServiceID
+	Time      time.Time
+	Healthy   bool
+
+	// Name -> description of every check that was failing when the node became unhealthy
+	FailingChecks map[string]string
+}
+
+// String implements the fmt.Stringer interface
+func (transition Transition) String() string {
+	if transition.Healthy {
+		return fmt.Sprintf("%s became healthy at %s", transition.ServiceID, transition.Time.Format(time.RFC3339))
+	}
+	checkNames := make([]string, 0, len(transition.FailingChecks))
+	for name := range transition.FailingChecks {
+		checkNames = append(checkNames, name)
+	}
+	sort.Strings(checkNames)
+	failures := make([]string, 0, len(checkNames))
+	for _, name := range checkNames {
+		failures = append(failures, fmt.Sprintf("%s: %s", name, transition.FailingChecks[name]))
+	}
+	return fmt.Sprintf("%s became unhealthy at %s (%s)", transition.ServiceID, transition.Time.Format(time.RFC3339), strings.Join(failures, "; "))
+}
+
+// HealthMonitor polls the health API of nodes in the background and records every time a node becomes unhealthy or
+// recovers, so that a test can assert at the end that its honest nodes stayed healthy throughout
+type HealthMonitor struct {
+	network      avalancheNetwork.TestAvalancheNetwork
+	pollInterval time.Duration
+
+	lock        sync.Mutex
+	watched     map[networks.ServiceID]bool
+	transitions []Transition
+	stopped     bool
+
+	stop    chan struct{}
+	pollers sync.WaitGroup
+}
+
+// NewHealthMonitor creates a monitor that polls the nodes it watches in [network] every [pollInterval]. Nodes are only
+// polled once they're passed to Watch.
+func NewHealthMonitor(network avalancheNetwork.TestAvalancheNetwork, pollInterval time.Duration) *HealthMonitor {
+	return &HealthMonitor{
+		network:      network,
+		pollInterval: pollInterval,
+		watched:      make(map[networks.ServiceID]bool),
+		stop:         make(chan struct{}),
+	}
+}
+
+// Watch starts polling the nodes with the given service IDs until Stop is called. Nodes should be watched once they've
+// finished bootstrapping, since nodes are unhealthy while they bootstrap. Nodes that are already watched are skipped.
+func (monitor *HealthMonitor) Watch(serviceIDs ...networks.ServiceID) error {
+	monitor.lock.Lock()
+	defer monitor.lock.Unlock()
+
+	if monitor.stopped {
+		return stacktrace.NewError("Health monitor has been stopped")
+	}
+	for _, serviceID := range serviceIDs {
+		if monitor.watched[serviceID] {
+			continue
+		}
+		client, err := monitor.network.GetAvalancheClient(serviceID)
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to get client of %s", serviceID)
+		}
+		monitor.watched[serviceID] = true
+		monitor.pollers.Add(1)
+		go monitor.poll(serviceID, client)
+	}
+	return nil
+}
+
+// Stop stops polling every node, waiting for in-flight polls to finish
+func (monitor *HealthMonitor) Stop() {
+	monitor.lock.Lock()
+	if monitor.stopped {
+		monitor.lock.Unlock()
+		return
+	}
+	monitor.stopped = true
+	close(monitor.stop)
+	monitor.lock.Unlock()
+
+	monitor.pollers.Wait()
+}
+
+// Transitions returns every transition recorded so far, in the order they were observed
+func (monitor *HealthMonitor) Transitions() []Transition {
+	monitor.lock.Lock()
+	defer monitor.lock.Unlock()
+
+	transitions := make([]Transition, len(monitor.transitions))
+	copy(transitions, monitor.transitions)
+	return transitions
+}
+
+// AssertNeverUnhealthy returns an error describing every time a node in [serviceIDs] became unhealthy while it was
+// watched, or nil if none did
+func (monitor *HealthMonitor) AssertNeverUnhealthy(serviceIDs map[networks.ServiceID]bool) error {
+	failures := make([]string, 0)
+	for _, transition := range monitor.Transitions() {
+		if !transition.Healthy && serviceIDs[transition.ServiceID] {
+			failures = append(failures, transition.String())
+		}
+	}
+	if len(failures) > 0 {
+		return stacktrace.NewError("%d unhealthy transitions of honest nodes: %s", len(failures), strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+// poll checks the liveness of the node behind [client] every poll interval until the monitor is stopped
+func (monitor *HealthMonitor) poll(serviceID networks.ServiceID, client *services.Client) {
+	defer monitor.pollers.Done()
+
+	ticker := time.NewTicker(monitor.pollInterval)
+	defer ticker.Stop()
+
+	// Nodes are assumed to be healthy when they start being watched
+	healthy := true
+	for {
+		select {
+		case <-monitor.stop:
+			return
+		case <-ticker.C:
+		}
+
+		failingChecks := make(map[string]string)
+		reply, err := client.Liveness()
+		if err != nil {
+			failingChecks[unreachableCheckName] = err.Error()
+		} else {
+			for name, result := range reply.FailingChecks() {
+				failingChecks[name] = result.String()
+			}
+			if !reply.Healthy && len(failingChecks) == 0 {
+				failingChecks[unreachableCheckName] = "node reported unhealthy without failing checks"
+			}
+		}
+
+		nowHealthy := len(failingChecks) == 0
+		if nowHealthy == healthy {
+			continue
+		}
+		healthy = nowHealthy
+		transition := Transition{
+			ServiceID:     serviceID,
+			Time:          time.Now(),
+			Healthy:       healthy,
+			FailingChecks: failingChecks,
+		}
+		if healthy {
+			logging.ForService(string(serviceID)).Infof("%s", transition)
+		} else {
+			logging.ForService(string(serviceID)).Warnf("%s", transition)
+		}
+		monitor.lock.Lock()
+		monitor.transitions = append(monitor.transitions, transition)
+		monitor.lock.Unlock()
+	}
+}
@@ -0,0 +1,252 @@
+package monitoring
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/logging"
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	"github.com/ava-labs/avalanche-testing/utils/files"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Extension of the files avalanchego writes its logs to
+	logFileExtension = ".log"
+
+	// Maximum length of a single log line, which bounds the memory used to scan a log file
+	maxLogLineLength = 1024 * 1024
+
+	// Maximum number of matching lines included in an assertion failure per node
+	maxReportedMatches = 5
+)
+
+// FatalLogPattern matches log lines written at the FATAL level, which avalanchego writes before it crashes
+var FatalLogPattern = regexp.MustCompile(`^FATAL\[`)
+
+// LogLine is a single line of a node's logs
+type LogLine struct {
+	// Path of the file the line is in, relative to the node's log directory
+	File string
+
+	// 1-indexed line number of the line in its file
+	Number int
+
+	Text string
+}
+
+// String implements the fmt.Stringer interface
+func (line LogLine) String() string {
+	return fmt.Sprintf("%s:%d: %s", line.File, line.Number, line.Text)
+}
+
+// LogInspector reads the log files a set of nodes write to the test volume, so that tests can assert what the nodes
+// logged and keep the logs of failed tests
+type LogInspector struct {
+	network avalancheNetwork.TestAvalancheNetwork
+
+	// Service ID -> directory the node writes its logs to, as seen from the test suite container
+	logDirpaths map[networks.ServiceID]string
+}
+
+// NewLogInspector creates an inspector for the logs of nodes in [network], which must have been loaded with node files
+// exposed so that the nodes write their logs to the test volume. Nodes are only inspected once they're passed to Inspect.
+func NewLogInspector(network avalancheNetwork.TestAvalancheNetwork) *LogInspector {
+	return &LogInspector{
+		network:     network,
+		logDirpaths: make(map[networks.ServiceID]string),
+	}
+}
+
+// Inspect adds the nodes with the given service IDs to the nodes whose logs are inspected and saved
+func (inspector *LogInspector) Inspect(serviceIDs ...networks.ServiceID) error {
+	for _, serviceID := range serviceIDs {
+		logDirpath, err := inspector.network.GetNodeLogsDirpath(serviceID)
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to get log directory of node %s", serviceID)
+		}
+		inspector.logDirpaths[serviceID] = logDirpath
+	}
+	return nil
+}
+
+// Lines calls [handler] with every line node [serviceID] has logged so far, file by file, until [handler] returns false
+func (inspector *LogInspector) Lines(serviceID networks.ServiceID, handler func(LogLine) bool) error {
+	logDirpath, ok := inspector.logDirpaths[serviceID]
+	if !ok {
+		return stacktrace.NewError("Logs of node %s are not inspected", serviceID)
+	}
+	return scanLogDir(logDirpath, handler)
+}
+
+// Matches returns every line node [serviceID] has logged so far that matches [pattern]
+func (inspector *LogInspector) Matches(serviceID networks.ServiceID, pattern *regexp.Regexp) ([]LogLine, error) {
+	matches := make([]LogLine, 0)
+	err := inspector.Lines(serviceID, func(line LogLine) bool {
+		if pattern.MatchString(line.Text) {
+			matches = append(matches, line)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to scan logs of node %s", serviceID)
+	}
+	return matches, nil
+}
+
+// AssertAppears returns an error naming every node in [serviceIDs] that hasn't logged a line matching [pattern]
+func (inspector *LogInspector) AssertAppears(serviceIDs map[networks.ServiceID]bool, pattern *regexp.Regexp) error {
+	missing := make([]string, 0)
+	for _, serviceID := range sortedServiceIDs(serviceIDs) {
+		found := false
+		err := inspector.Lines(serviceID, func(line LogLine) bool {
+			found = pattern.MatchString(line.Text)
+			return !found
+		})
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to scan logs of node %s", serviceID)
+		}
+		if !found {
+			missing = append(missing, string(serviceID))
+		}
+	}
+	if len(missing) > 0 {
+		return stacktrace.NewError("Nodes %s never logged a line matching %s", strings.Join(missing, ", "), pattern)
+	}
+	return nil
+}
+
+// AssertNeverAppears returns an error describing the lines matching [pattern] that any node in [serviceIDs] has
+// logged, or nil if none did
+func (inspector *LogInspector) AssertNeverAppears(serviceIDs map[networks.ServiceID]bool, pattern *regexp.Regexp) error {
+	failures := make([]string, 0)
+	for _, serviceID := range sortedServiceIDs(serviceIDs) {
+		matches, err := inspector.Matches(serviceID, pattern)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		reported := matches
+		if len(reported) > maxReportedMatches {
+			reported = reported[:maxReportedMatches]
+		}
+		descriptions := make([]string, 0, len(reported))
+		for _, match := range reported {
+			descriptions = append(descriptions, match.String())
+		}
+		failures = append(failures, fmt.Sprintf("%s logged %d matching lines (%s)", serviceID, len(matches), strings.Join(descriptions, "; ")))
+	}
+	if len(failures) > 0 {
+		return stacktrace.NewError("Nodes logged lines matching %s: %s", pattern, strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+// Save copies the logs of every node to a directory named after its service ID under [resultsDirpath]. Every node is
+// attempted even if an earlier one fails, and the first error is returned.
+func (inspector *LogInspector) Save(resultsDirpath string) error {
+	var firstErr error
+	for serviceID, logDirpath := range inspector.logDirpaths {
+		nodeResultsDirpath := filepath.Join(resultsDirpath, string(serviceID), "logs")
+		if err := files.CopyDir(logDirpath, nodeResultsDirpath); err != nil {
+			logging.ForService(string(serviceID)).Errorf("Failed to save logs of node %s: %v", serviceID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	logrus.Infof("Saved logs of %d nodes in %s.", len(inspector.logDirpaths), resultsDirpath)
+	return nil
+}
+
+// SaveOnFailure saves the logs of every node under [resultsDirpath] if the test is failing, and must be deferred by
+// the test's Run method. Kurtosis fails tests by panicking, so the panic is recovered to save the logs and then resumed.
+func (inspector *LogInspector) SaveOnFailure(resultsDirpath string) {
+	if r := recover(); r != nil {
+		if err := inspector.Save(resultsDirpath); err != nil {
+			logrus.Errorf("Failed to save the logs of the failed test: %v", err)
+		}
+		panic(r)
+	}
+}
+
+// scanLogDir calls [handler] with every line of every log file under [logDirpath] until [handler] returns false.
+// Files are read in lexical order of their paths, so the files of each logger are read together.
+func scanLogDir(logDirpath string, handler func(LogLine) bool) error {
+	logFilepaths := make([]string, 0)
+	err := filepath.Walk(logDirpath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(path) == logFileExtension {
+			logFilepaths = append(logFilepaths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to list log files in %s", logDirpath)
+	}
+	sort.Strings(logFilepaths)
+
+	for _, logFilepath := range logFilepaths {
+		relativeFilepath, err := filepath.Rel(logDirpath, logFilepath)
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to get path of %s relative to %s", logFilepath, logDirpath)
+		}
+		keepScanning, err := scanLogFile(logFilepath, relativeFilepath, handler)
+		if err != nil {
+			return err
+		}
+		if !keepScanning {
+			return nil
+		}
+	}
+	return nil
+}
+
+func scanLogFile(logFilepath string, relativeFilepath string, handler func(LogLine) bool) (bool, error) {
+	logFile, err := os.Open(logFilepath)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to open %s", logFilepath)
+	}
+	defer logFile.Close()
+
+	scanner := bufio.NewScanner(logFile)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineLength)
+	for number := 1; scanner.Scan(); number++ {
+		line := LogLine{
+			File:   relativeFilepath,
+			Number: number,
+			Text:   scanner.Text(),
+		}
+		if !handler(line) {
+			return false, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, stacktrace.Propagate(err, "Failed to read %s", logFilepath)
+	}
+	return true, nil
+}
+
+func sortedServiceIDs(serviceIDs map[networks.ServiceID]bool) []networks.ServiceID {
+	sorted := make([]networks.ServiceID, 0, len(serviceIDs))
+	for serviceID := range serviceIDs {
+		sorted = append(sorted, serviceID)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -0,0 +1,56 @@
+package monitoring
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	honestServiceID    networks.ServiceID = "honest-node"
+	byzantineServiceID networks.ServiceID = "byzantine-node"
+)
+
+func TestLogInspectorAssertions(t *testing.T) {
+	logsDirpath, err := ioutil.TempDir("", "node-logs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(logsDirpath)
+
+	honestLogDirpath := filepath.Join(logsDirpath, string(honestServiceID))
+	writeLogFile(t, filepath.Join(honestLogDirpath, "0.log"), "INFO [10-01|00:00:00] node/node.go#1: starting\n")
+	writeLogFile(t, filepath.Join(honestLogDirpath, "chain", "X", "0.log"), "DEBUG[10-01|00:00:01] router.go#2: dropping unrequested chits\n")
+	byzantineLogDirpath := filepath.Join(logsDirpath, string(byzantineServiceID))
+	writeLogFile(t, filepath.Join(byzantineLogDirpath, "0.log"), "INFO [10-01|00:00:00] node/node.go#1: starting\nFATAL[10-01|00:00:02] node/node.go#3: crashed\n")
+
+	inspector := &LogInspector{logDirpaths: map[networks.ServiceID]string{
+		honestServiceID:    honestLogDirpath,
+		byzantineServiceID: byzantineLogDirpath,
+	}}
+	honest := map[networks.ServiceID]bool{honestServiceID: true}
+	all := map[networks.ServiceID]bool{honestServiceID: true, byzantineServiceID: true}
+
+	assert.NoError(t, inspector.AssertNeverAppears(honest, FatalLogPattern))
+	assert.Error(t, inspector.AssertNeverAppears(all, FatalLogPattern), "Expected an error for the byzantine node's FATAL line")
+	assert.NoError(t, inspector.AssertAppears(honest, regexp.MustCompile("dropping unrequested chits")))
+	assert.Error(t, inspector.AssertAppears(all, regexp.MustCompile("dropping unrequested chits")), "Expected an error for the byzantine node's missing line")
+
+	matches, err := inspector.Matches(byzantineServiceID, FatalLogPattern)
+	assert.NoError(t, err)
+	assert.Equal(t, []LogLine{{File: "0.log", Number: 2, Text: "FATAL[10-01|00:00:02] node/node.go#3: crashed"}}, matches)
+
+	resultsDirpath := filepath.Join(logsDirpath, "results")
+	assert.NoError(t, inspector.Save(resultsDirpath))
+	saved, err := ioutil.ReadFile(filepath.Join(resultsDirpath, string(honestServiceID), "logs", "chain", "X", "0.log"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(saved), "dropping unrequested chits")
+}
+
+func writeLogFile(t *testing.T, logFilepath string, contents string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(logFilepath), 0755))
+	assert.NoError(t, ioutil.WriteFile(logFilepath, []byte(contents), 0644))
+}
@@ -0,0 +1,191 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/palantir/stacktrace"
+	"gopkg.in/yaml.v2"
+)
+
+// Validator is implemented by parameter structs that can check their values
+type Validator interface {
+	Validate() error
+}
+
+// Overrides maps test name -> parameter field name -> value of the parameters that replace a test's defaults. Values
+// are kept as strings and only parsed once applied to a test's typed parameters.
+type Overrides map[string]map[string]string
+
+// LoadFile reads overrides from the JSON or YAML file at [filepath], which maps test names to objects of parameter
+// values, e.g. {"bombardXChainTest": {"NumTxs": 50000, "AcceptanceTimeout": "30s"}}. Files ending in .yaml or .yml are
+// parsed as YAML, and any other file as JSON.
+func LoadFile(filepath string) (Overrides, error) {
+	fileBytes, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to read test parameter file %s", filepath)
+	}
+	var overrides Overrides
+	switch strings.ToLower(path.Ext(filepath)) {
+	case ".yaml", ".yml":
+		overrides, err = parseYAML(fileBytes)
+	default:
+		overrides, err = parseJSON(fileBytes)
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to parse test parameter file %s", filepath)
+	}
+	return overrides, nil
+}
+
+func parseJSON(fileBytes []byte) (Overrides, error) {
+	rawOverrides := make(map[string]map[string]json.RawMessage)
+	if err := json.Unmarshal(fileBytes, &rawOverrides); err != nil {
+		return nil, err
+	}
+	overrides := make(Overrides, len(rawOverrides))
+	for testName, rawFields := range rawOverrides {
+		fields := make(map[string]string, len(rawFields))
+		for fieldName, rawValue := range rawFields {
+			// Strings are unquoted, and every other JSON value is parsed from its text like a flag value would be
+			var value string
+			if err := json.Unmarshal(rawValue, &value); err != nil {
+				value = string(rawValue)
+			}
+			fields[fieldName] = value
+		}
+		overrides[testName] = fields
+	}
+	return overrides, nil
+}
+
+func parseYAML(fileBytes []byte) (Overrides, error) {
+	rawOverrides := make(map[string]map[string]interface{})
+	if err := yaml.Unmarshal(fileBytes, &rawOverrides); err != nil {
+		return nil, err
+	}
+	overrides := make(Overrides, len(rawOverrides))
+	for testName, rawFields := range rawOverrides {
+		fields := make(map[string]string, len(rawFields))
+		for fieldName, rawValue := range rawFields {
+			// Scalars are formatted back to their text, which is parsed like a flag value would be
+			fields[fieldName] = fmt.Sprint(rawValue)
+		}
+		overrides[testName] = fields
+	}
+	return overrides, nil
+}
+
+// ParseFlags parses overrides from flag values of the form <test name>.<field name>=<value>
+func ParseFlags(flagValues []string) (Overrides, error) {
+	overrides := make(Overrides)
+	for _, flagValue := range flagValues {
+		assignment := strings.SplitN(flagValue, "=", 2)
+		if len(assignment) != 2 {
+			return nil, stacktrace.NewError("Test parameter '%s' is not of the form <test name>.<field name>=<value>", flagValue)
+		}
+		dotIndex := strings.LastIndex(assignment[0], ".")
+		if dotIndex <= 0 || dotIndex == len(assignment[0])-1 {
+			return nil, stacktrace.NewError("Test parameter '%s' is not of the form <test name>.<field name>=<value>", flagValue)
+		}
+		testName := strings.TrimSpace(assignment[0][:dotIndex])
+		fieldName := strings.TrimSpace(assignment[0][dotIndex+1:])
+		if _, found := overrides[testName]; !found {
+			overrides[testName] = make(map[string]string)
+		}
+		overrides[testName][fieldName] = strings.TrimSpace(assignment[1])
+	}
+	return overrides, nil
+}
+
+// Merge returns the overrides of [o] replaced by those of [other] where both set the same parameter
+func (o Overrides) Merge(other Overrides) Overrides {
+	merged := make(Overrides, len(o)+len(other))
+	for _, overrides := range []Overrides{o, other} {
+		for testName, fields := range overrides {
+			if _, found := merged[testName]; !found {
+				merged[testName] = make(map[string]string, len(fields))
+			}
+			for fieldName, value := range fields {
+				merged[testName][fieldName] = value
+			}
+		}
+	}
+	return merged
+}
+
+// Apply sets the fields of the struct [target] points to from [fields], which maps field names (matched ignoring
+// case) to values, and then validates the struct if it implements Validator. Fields must be integers, floats, bools,
+// strings, or durations.
+func Apply(target interface{}, fields map[string]string) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.Elem().Kind() != reflect.Struct {
+		return stacktrace.NewError("Test parameters must be applied to a pointer to a struct, not %T", target)
+	}
+	structValue := targetValue.Elem()
+	for fieldName, value := range fields {
+		field := structValue.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, fieldName)
+		})
+		if !field.IsValid() || !field.CanSet() {
+			return stacktrace.NewError("%s has no parameter named %s", structValue.Type().Name(), fieldName)
+		}
+		if err := setField(field, value); err != nil {
+			return stacktrace.Propagate(err, "Failed to set parameter %s to '%s'", fieldName, value)
+		}
+	}
+	if validator, ok := target.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return stacktrace.Propagate(err, "Invalid %s", structValue.Type().Name())
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.String:
+		field.SetString(value)
+	default:
+		return stacktrace.NewError("Parameters of type %s are not supported", field.Type())
+	}
+	return nil
+}
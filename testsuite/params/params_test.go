@@ -0,0 +1,68 @@
+package params
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/palantir/stacktrace"
+	"github.com/stretchr/testify/assert"
+)
+
+type testParams struct {
+	NumTxs            uint64
+	AcceptanceTimeout time.Duration
+	Verbose           bool
+}
+
+func (p testParams) Validate() error {
+	if p.NumTxs == 0 {
+		return stacktrace.NewError("NumTxs must be positive")
+	}
+	return nil
+}
+
+func TestFileAndFlagOverrides(t *testing.T) {
+	paramsFile, err := ioutil.TempFile("", "test-params-*.json")
+	assert.NoError(t, err)
+	defer os.Remove(paramsFile.Name())
+	_, err = paramsFile.WriteString(`{"bombardXChainTest": {"NumTxs": 50000, "AcceptanceTimeout": "30s", "Verbose": true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, paramsFile.Close())
+
+	fileOverrides, err := LoadFile(paramsFile.Name())
+	assert.NoError(t, err)
+	flagOverrides, err := ParseFlags([]string{"bombardXChainTest.numtxs=20000"})
+	assert.NoError(t, err)
+
+	params := testParams{NumTxs: 1000, AcceptanceTimeout: 10 * time.Second}
+	assert.NoError(t, Apply(&params, fileOverrides.Merge(flagOverrides)["bombardXChainTest"]))
+	assert.Equal(t, testParams{NumTxs: 20000, AcceptanceTimeout: 30 * time.Second, Verbose: true}, params)
+}
+
+func TestYAMLFile(t *testing.T) {
+	paramsFile, err := ioutil.TempFile("", "test-params-*.yaml")
+	assert.NoError(t, err)
+	defer os.Remove(paramsFile.Name())
+	_, err = paramsFile.WriteString("bombardXChainTest:\n  NumTxs: 50000\n  AcceptanceTimeout: 30s\n  Verbose: true\n")
+	assert.NoError(t, err)
+	assert.NoError(t, paramsFile.Close())
+
+	fileOverrides, err := LoadFile(paramsFile.Name())
+	assert.NoError(t, err)
+
+	params := testParams{NumTxs: 1000, AcceptanceTimeout: 10 * time.Second}
+	assert.NoError(t, Apply(&params, fileOverrides["bombardXChainTest"]))
+	assert.Equal(t, testParams{NumTxs: 50000, AcceptanceTimeout: 30 * time.Second, Verbose: true}, params)
+}
+
+func TestInvalidOverrides(t *testing.T) {
+	_, err := ParseFlags([]string{"NumTxs=20000"})
+	assert.Error(t, err, "Expected an error for a parameter without a test name")
+
+	params := testParams{NumTxs: 1000}
+	assert.Error(t, Apply(&params, map[string]string{"NumTx": "1"}), "Expected an error for an unknown field")
+	assert.Error(t, Apply(&params, map[string]string{"NumTxs": "-1"}), "Expected an error for a value of the wrong type")
+	assert.Error(t, Apply(&params, map[string]string{"NumTxs": "0"}), "Expected an error for parameters that fail validation")
+}
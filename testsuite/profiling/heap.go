@@ -0,0 +1,197 @@
+package profiling
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// Name of the heap profile's sample type holding the bytes allocated and not yet freed
+	inUseSpaceSampleType = "inuse_space"
+
+	// Field numbers of the pprof profile.proto messages that the in-use heap size is read from
+	profileSampleTypeField  = 1
+	profileSampleField      = 2
+	profileStringTableField = 6
+	valueTypeTypeField      = 1
+	sampleValueField        = 2
+
+	// Protobuf wire types
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// MeasureHeap takes a memory profile of every node and returns the bytes each node had in use on its heap, as of
+// the node's last garbage collection
+func (p *NodeProfiler) MeasureHeap() (map[networks.ServiceID]uint64, error) {
+	heapSizes := make(map[networks.ServiceID]uint64, len(p.nodes))
+	for serviceID, node := range p.nodes {
+		if err := node.client.Admin().MemoryProfile(); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to take memory profile of node %s", serviceID)
+		}
+		heapSize, err := HeapInUseBytes(filepath.Join(node.workingDirpath, services.MemProfileFile))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to read heap size of node %s", serviceID)
+		}
+		heapSizes[serviceID] = heapSize
+	}
+	return heapSizes, nil
+}
+
+// HeapInUseBytes returns the bytes in use on the heap recorded by the gzipped pprof heap profile at [profileFilepath]
+func HeapInUseBytes(profileFilepath string) (uint64, error) {
+	file, err := os.Open(profileFilepath)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to open heap profile %s", profileFilepath)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to decompress heap profile %s", profileFilepath)
+	}
+	profileBytes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to decompress heap profile %s", profileFilepath)
+	}
+	inUse, err := sumSampleValues(profileBytes, inUseSpaceSampleType)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to parse heap profile %s", profileFilepath)
+	}
+	return inUse, nil
+}
+
+// sumSampleValues sums the values of type [sampleType] of every sample of the encoded profile [profileBytes]. Only
+// the fields of the profile needed to do so are decoded.
+func sumSampleValues(profileBytes []byte, sampleType string) (uint64, error) {
+	// The string table and sample types may be encoded after the samples, so the samples' values are collected first
+	sampleTypeStringIndexes := make([]uint64, 0)
+	stringTable := make([]string, 0)
+	sampleValues := make([][]int64, 0)
+	err := forEachField(profileBytes, func(fieldNumber uint64, wireType uint64, varint uint64, bytes []byte) error {
+		switch {
+		case fieldNumber == profileSampleTypeField && wireType == wireBytes:
+			typeIndex := uint64(0)
+			if err := forEachField(bytes, func(fieldNumber uint64, wireType uint64, varint uint64, _ []byte) error {
+				if fieldNumber == valueTypeTypeField && wireType == wireVarint {
+					typeIndex = varint
+				}
+				return nil
+			}); err != nil {
+				return stacktrace.Propagate(err, "Failed to decode sample type")
+			}
+			sampleTypeStringIndexes = append(sampleTypeStringIndexes, typeIndex)
+		case fieldNumber == profileSampleField && wireType == wireBytes:
+			values, err := decodeSampleValues(bytes)
+			if err != nil {
+				return stacktrace.Propagate(err, "Failed to decode sample")
+			}
+			sampleValues = append(sampleValues, values)
+		case fieldNumber == profileStringTableField && wireType == wireBytes:
+			stringTable = append(stringTable, string(bytes))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	valueIndex := -1
+	for i, stringIndex := range sampleTypeStringIndexes {
+		if stringIndex < uint64(len(stringTable)) && stringTable[stringIndex] == sampleType {
+			valueIndex = i
+			break
+		}
+	}
+	if valueIndex < 0 {
+		return 0, stacktrace.NewError("Profile has no sample type %s", sampleType)
+	}
+	total := uint64(0)
+	for _, values := range sampleValues {
+		if valueIndex < len(values) {
+			total += uint64(values[valueIndex])
+		}
+	}
+	return total, nil
+}
+
+// decodeSampleValues returns the values of the encoded Sample [sampleBytes], which may be packed or not
+func decodeSampleValues(sampleBytes []byte) ([]int64, error) {
+	values := make([]int64, 0)
+	err := forEachField(sampleBytes, func(fieldNumber uint64, wireType uint64, varint uint64, bytes []byte) error {
+		if fieldNumber != sampleValueField {
+			return nil
+		}
+		switch wireType {
+		case wireVarint:
+			values = append(values, int64(varint))
+		case wireBytes:
+			for len(bytes) > 0 {
+				value, n := binary.Uvarint(bytes)
+				if n <= 0 {
+					return stacktrace.NewError("Malformed packed sample value")
+				}
+				values = append(values, int64(value))
+				bytes = bytes[n:]
+			}
+		}
+		return nil
+	})
+	return values, err
+}
+
+// forEachField calls [visit] with every field of the encoded protobuf message [message]. [varint] holds the value of
+// varint fields, and [bytes] the contents of length delimited fields.
+func forEachField(message []byte, visit func(fieldNumber uint64, wireType uint64, varint uint64, bytes []byte) error) error {
+	for len(message) > 0 {
+		key, n := binary.Uvarint(message)
+		if n <= 0 {
+			return stacktrace.NewError("Malformed field key")
+		}
+		message = message[n:]
+		fieldNumber, wireType := key>>3, key&7
+
+		var varint uint64
+		var bytes []byte
+		switch wireType {
+		case wireVarint:
+			varint, n = binary.Uvarint(message)
+			if n <= 0 {
+				return stacktrace.NewError("Malformed varint of field %d", fieldNumber)
+			}
+			message = message[n:]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if wireType == wireFixed32 {
+				size = 4
+			}
+			if len(message) < size {
+				return stacktrace.NewError("Truncated fixed size field %d", fieldNumber)
+			}
+			message = message[size:]
+		case wireBytes:
+			length, n := binary.Uvarint(message)
+			if n <= 0 || uint64(len(message)-n) < length {
+				return stacktrace.NewError("Malformed length of field %d", fieldNumber)
+			}
+			bytes = message[n : n+int(length)]
+			message = message[n+int(length):]
+		default:
+			return stacktrace.NewError("Unsupported wire type %d of field %d", wireType, fieldNumber)
+		}
+		if err := visit(fieldNumber, wireType, varint, bytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
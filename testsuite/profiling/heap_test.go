@@ -0,0 +1,47 @@
+package profiling
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var retained [][]byte
+
+func TestHeapInUseBytes(t *testing.T) {
+	dirpath, err := ioutil.TempDir("", "heap-profile")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirpath)
+
+	// Keep enough memory alive that the sampled heap profile is sure to record some of it
+	for i := 0; i < 1024; i++ {
+		retained = append(retained, make([]byte, 16*1024))
+	}
+	runtime.GC()
+
+	profileFilepath := filepath.Join(dirpath, "mem.profile")
+	file, err := os.Create(profileFilepath)
+	assert.NoError(t, err)
+	assert.NoError(t, pprof.WriteHeapProfile(file))
+	assert.NoError(t, file.Close())
+
+	inUse, err := HeapInUseBytes(profileFilepath)
+	assert.NoError(t, err)
+	assert.True(t, inUse >= 8*1024*1024, "Expected at least half of the retained 16MiB to be in use, found %d bytes", inUse)
+}
+
+func TestHeapInUseBytesRejectsUncompressedProfile(t *testing.T) {
+	dirpath, err := ioutil.TempDir("", "heap-profile")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirpath)
+
+	profileFilepath := filepath.Join(dirpath, "mem.profile")
+	assert.NoError(t, ioutil.WriteFile(profileFilepath, []byte("not a profile"), 0644))
+	_, err = HeapInUseBytes(profileFilepath)
+	assert.Error(t, err)
+}
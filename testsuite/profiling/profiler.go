@@ -0,0 +1,99 @@
+package profiling
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/logging"
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/utils/files"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// NodeProfiler captures CPU and memory profiles of a set of nodes through their admin API, and copies the profiles out of
+// the nodes' working directories so they can be attached to performance regressions
+type NodeProfiler struct {
+	nodes map[networks.ServiceID]profiledNode
+}
+
+type profiledNode struct {
+	client *services.Client
+
+	// Where the node writes its profiles, as seen from the test suite container
+	workingDirpath string
+}
+
+// NewNodeProfiler creates a profiler for the nodes in [serviceIDs]. [network] must have been loaded with profiling
+// enabled, so that the nodes write their profiles to the test volume.
+func NewNodeProfiler(network avalancheNetwork.TestAvalancheNetwork, serviceIDs map[networks.ServiceID]bool) (*NodeProfiler, error) {
+	nodes := make(map[networks.ServiceID]profiledNode, len(serviceIDs))
+	for serviceID := range serviceIDs {
+		client, err := network.GetAvalancheClient(serviceID)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to get client of node %s", serviceID)
+		}
+		workingDirpath, err := network.GetNodeWorkingDirpath(serviceID)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to get working directory of node %s", serviceID)
+		}
+		nodes[serviceID] = profiledNode{
+			client:         client,
+			workingDirpath: workingDirpath,
+		}
+	}
+	return &NodeProfiler{nodes: nodes}, nil
+}
+
+// Start starts the CPU profiler of every node
+func (p *NodeProfiler) Start() error {
+	for serviceID, node := range p.nodes {
+		if err := node.client.Admin().StartCPUProfiler(); err != nil {
+			return stacktrace.Propagate(err, "Failed to start CPU profiler of node %s", serviceID)
+		}
+	}
+	logrus.Infof("Started CPU profilers of %d nodes.", len(p.nodes))
+	return nil
+}
+
+// Stop stops the CPU profiler of every node and takes a memory profile of each, then copies both profiles of each node
+// to a directory named after its service ID under [resultsDirpath]. Every node is attempted even if an earlier one
+// fails, and the first error is returned.
+func (p *NodeProfiler) Stop(resultsDirpath string) error {
+	var firstErr error
+	for serviceID, node := range p.nodes {
+		if err := p.collect(serviceID, node, filepath.Join(resultsDirpath, string(serviceID))); err != nil {
+			logging.ForService(string(serviceID)).Errorf("Failed to collect profiles of node %s: %v", serviceID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	logrus.Infof("Collected profiles of %d nodes in %s.", len(p.nodes), resultsDirpath)
+	return nil
+}
+
+func (p *NodeProfiler) collect(serviceID networks.ServiceID, node profiledNode, nodeResultsDirpath string) error {
+	if err := node.client.Admin().StopCPUProfiler(); err != nil {
+		return stacktrace.Propagate(err, "Failed to stop CPU profiler of node %s", serviceID)
+	}
+	if err := node.client.Admin().MemoryProfile(); err != nil {
+		return stacktrace.Propagate(err, "Failed to take memory profile of node %s", serviceID)
+	}
+
+	if err := os.MkdirAll(nodeResultsDirpath, 0755); err != nil {
+		return stacktrace.Propagate(err, "Failed to create results directory %s", nodeResultsDirpath)
+	}
+	for _, filename := range []string{services.CPUProfileFile, services.MemProfileFile} {
+		if err := files.CopyFile(filepath.Join(node.workingDirpath, filename), filepath.Join(nodeResultsDirpath, filename), 0644); err != nil {
+			return stacktrace.Propagate(err, "Failed to copy %s of node %s", filename, serviceID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package testaccounts
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
+	"github.com/ava-labs/avalanche-testing/utils/addressing"
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheConstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/coreth/plugin/evm"
+	"github.com/palantir/stacktrace"
+)
+
+// Account is a test account whose key was derived deterministically from a seed, so that the same seed always
+// produces the same accounts and a failing test can be reproduced
+type Account struct {
+	// Index of the account within the accounts derived from its seed, used to identify the account in logs
+	Index int
+	Key   *crypto.PrivateKeySECP256K1R
+}
+
+// Derive returns [numAccounts] accounts whose keys are derived from [seed]. The key of the account at index i is the
+// SHA256 hash of [seed] followed by i as a big endian uint32.
+func Derive(seed string, numAccounts int) ([]*Account, error) {
+	factory := crypto.FactorySECP256K1R{}
+	accounts := make([]*Account, numAccounts)
+	for i := range accounts {
+		preimage := make([]byte, len(seed)+4)
+		copy(preimage, seed)
+		binary.BigEndian.PutUint32(preimage[len(seed):], uint32(i))
+		skIntf, err := factory.ToPrivateKey(hashing.ComputeHash256(preimage))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to derive key of account %d from seed %s", i, seed)
+		}
+		accounts[i] = &Account{
+			Index: i,
+			Key:   skIntf.(*crypto.PrivateKeySECP256K1R),
+		}
+	}
+	return accounts, nil
+}
+
+// GenesisKey returns the private key that holds the funds allocated by the local network's genesis
+func GenesisKey() (*crypto.PrivateKeySECP256K1R, error) {
+	return helpers.ParsePrivateKey(avalancheNetwork.DefaultLocalNetGenesisConfig.FundedAddresses.PrivateKey)
+}
+
+// ShortID returns the address of [account] shared by the X and P Chains
+func (account *Account) ShortID() ids.ShortID {
+	return account.Key.PublicKey().Address()
+}
+
+// XChainAddress returns the bech32 X Chain address of [account] on the network of [formatter]
+func (account *Account) XChainAddress(formatter addressing.Formatter) (string, error) {
+	return formatter.XChainAddress(account.ShortID())
+}
+
+// PChainAddress returns the bech32 P Chain address of [account] on the network of [formatter]
+func (account *Account) PChainAddress(formatter addressing.Formatter) (string, error) {
+	return formatter.PChainAddress(account.ShortID())
+}
+
+// CChainBech32Address returns the bech32 C Chain address of [account] on the network of [formatter], which is used to
+// reference its atomic UTXOs
+func (account *Account) CChainBech32Address(formatter addressing.Formatter) (string, error) {
+	return formatter.CChainAddress(account.ShortID())
+}
+
+// CChainHexAddress returns the 0x prefixed hex address of [account] on the C Chain
+func (account *Account) CChainHexAddress() string {
+	return evm.GetEthAddress(account.Key).Hex()
+}
+
+// PrivateKeyString returns the private key of [account] in the format accepted by the keystore's importKey
+func (account *Account) PrivateKeyString() (string, error) {
+	encoded, err := formatting.Encode(formatting.CB58, account.Key.Bytes())
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to encode private key of account %d", account.Index)
+	}
+	return avalancheConstants.SecretKeyPrefix + encoded, nil
+}
+
+// String implements the Stringer interface
+func (account *Account) String() string {
+	return fmt.Sprintf("account[%d](%s)", account.Index, account.ShortID())
+}
@@ -0,0 +1,42 @@
+package testaccounts
+
+import (
+	"testing"
+
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	"github.com/ava-labs/avalanche-testing/utils/addressing"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveIsDeterministic(t *testing.T) {
+	first, err := Derive("seed", 3)
+	assert.NoError(t, err)
+	second, err := Derive("seed", 3)
+	assert.NoError(t, err)
+	other, err := Derive("other seed", 3)
+	assert.NoError(t, err)
+
+	for i := range first {
+		assert.Equal(t, i, first[i].Index)
+		assert.Equal(t, first[i].Key.Bytes(), second[i].Key.Bytes())
+		assert.NotEqual(t, first[i].ShortID(), other[i].ShortID())
+	}
+	assert.NotEqual(t, first[0].ShortID(), first[1].ShortID())
+}
+
+func TestGenesisKeyRoundTrip(t *testing.T) {
+	genesisKey, err := GenesisKey()
+	assert.NoError(t, err)
+
+	account := &Account{Key: genesisKey}
+	privateKey, err := account.PrivateKeyString()
+	assert.NoError(t, err)
+	assert.Equal(t, avalancheNetwork.DefaultLocalNetGenesisConfig.FundedAddresses.PrivateKey, privateKey)
+
+	xChainAddress, err := account.XChainAddress(addressing.NewFormatter(constants.LocalID))
+	assert.NoError(t, err)
+	assert.Equal(t, avalancheNetwork.DefaultLocalNetGenesisConfig.FundedAddresses.Address, account.ShortID().String())
+	assert.Equal(t, "X-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u", xChainAddress)
+	assert.Equal(t, "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", account.CChainHexAddress())
+}
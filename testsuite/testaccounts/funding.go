@@ -0,0 +1,150 @@
+package testaccounts
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// FundXChain sends [amount] AVAX to each of [accounts] from the genesis key in a single X Chain transaction, and
+// waits up to [acceptanceTimeout] for it to be accepted. It returns the ID of the funding transaction.
+func FundXChain(client *services.Client, accounts []*Account, amount uint64, acceptanceTimeout time.Duration) (ids.ID, error) {
+	if len(accounts) == 0 {
+		return ids.ID{}, stacktrace.NewError("No accounts to fund")
+	}
+	genesisKey, err := GenesisKey()
+	if err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to parse genesis key.")
+	}
+	txFeeResponse, err := client.InfoAPI().GetTxFee()
+	if err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to get the network's transaction fee.")
+	}
+	txFee := uint64(txFeeResponse.TxFee)
+	codec, err := helpers.CreateXChainCodec()
+	if err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to initialize codec.")
+	}
+
+	required := amount*uint64(len(accounts)) + txFee
+	runner := helpers.NewRPCWorkFlowRunner(client, api.UserPass{}, acceptanceTimeout)
+	registry, err := runner.ChainRegistry()
+	if err != nil {
+		return ids.ID{}, err
+	}
+	genesisAddress, err := registry.AddressFormatter().XChainAddress(genesisKey.PublicKey().Address())
+	if err != nil {
+		return ids.ID{}, err
+	}
+	utxosBytes, _, err := client.XChainAPI().GetUTXOs([]string{genesisAddress}, 0, "", "")
+	if err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to get UTXOs of genesis address %s.", genesisAddress)
+	}
+	var fundingUTXO *avax.UTXO
+	var fundingOut *secp256k1fx.TransferOutput
+	for _, utxoBytes := range utxosBytes {
+		utxo := &avax.UTXO{}
+		if _, err := codec.Unmarshal(utxoBytes, utxo); err != nil {
+			return ids.ID{}, stacktrace.Propagate(err, "Failed to unmarshal utxo bytes.")
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || utxo.AssetID() != registry.AvaxAssetID() || out.Locktime != 0 || out.Threshold != 1 || len(out.Addrs) != 1 {
+			continue
+		}
+		if out.Amt >= required && (fundingOut == nil || out.Amt > fundingOut.Amt) {
+			fundingUTXO = utxo
+			fundingOut = out
+		}
+	}
+	if fundingUTXO == nil {
+		return ids.ID{}, stacktrace.NewError("No unlocked genesis UTXO holds the %d required to fund %d accounts", required, len(accounts))
+	}
+
+	outs := make([]*avax.TransferableOutput, 0, len(accounts)+1)
+	for _, account := range accounts {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: registry.AvaxAssetID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{account.ShortID()},
+				},
+			},
+		})
+	}
+	if change := fundingOut.Amt - required; change > 0 {
+		outs = append(outs, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: registry.AvaxAssetID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: change,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{genesisKey.PublicKey().Address()},
+				},
+			},
+		})
+	}
+	avax.SortTransferableOutputs(outs, codec)
+	ins := []*avax.TransferableInput{{
+		UTXOID: fundingUTXO.UTXOID,
+		Asset:  avax.Asset{ID: registry.AvaxAssetID()},
+		In: &secp256k1fx.TransferInput{
+			Amt:   fundingOut.Amt,
+			Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		},
+	}}
+	tx := &avm.Tx{UnsignedTx: &avm.BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    registry.NetworkID(),
+		BlockchainID: registry.XChainID(),
+		Outs:         outs,
+		Ins:          ins,
+	}}}
+	if err := tx.SignSECP256K1Fx(codec, [][]*crypto.PrivateKeySECP256K1R{{genesisKey}}); err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to sign funding transaction.")
+	}
+
+	txID, err := client.XChainAPI().IssueTx(tx.Bytes())
+	if err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to issue funding transaction.")
+	}
+	if err := runner.AwaitXChainTransactionAcceptance(txID); err != nil {
+		return ids.ID{}, stacktrace.Propagate(err, "Failed to accept funding transaction %s.", txID)
+	}
+	logrus.Infof("Funded %d test accounts with %d each in transaction %s.", len(accounts), amount, txID)
+	return txID, nil
+}
+
+// ImportToKeystore creates [user] on the node behind [client] and imports the keys of [accounts] into its X, P, and
+// C Chain keystores, so that the accounts can be used with APIs that sign on the node's side
+func ImportToKeystore(client *services.Client, user api.UserPass, accounts []*Account) error {
+	if _, err := client.KeystoreAPI().CreateUser(user); err != nil {
+		return stacktrace.Propagate(err, "Failed to create user %s.", user.Username)
+	}
+	for _, account := range accounts {
+		privateKey, err := account.PrivateKeyString()
+		if err != nil {
+			return err
+		}
+		if _, err := client.XChainAPI().ImportKey(user, privateKey); err != nil {
+			return stacktrace.Propagate(err, "Failed to import %s to the X Chain.", account)
+		}
+		if _, err := client.PChainAPI().ImportKey(user, privateKey); err != nil {
+			return stacktrace.Propagate(err, "Failed to import %s to the P Chain.", account)
+		}
+		if _, err := client.CChainAPI().ImportKey(user, privateKey); err != nil {
+			return stacktrace.Propagate(err, "Failed to import %s to the C Chain.", account)
+		}
+	}
+	logrus.Debugf("Imported %d test accounts to the keystore of user %s.", len(accounts), user.Username)
+	return nil
+}
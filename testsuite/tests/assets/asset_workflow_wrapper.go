@@ -0,0 +1,85 @@
+package assets
+
+import (
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/testsuite"
+
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	networkAcceptanceTimeoutRatio = 0.3
+)
+
+// StakingNetworkAssetWorkflowTest walks through the lifecycle of every X Chain asset type: fixed cap, variable cap,
+// NFT and property fx assets, followed by exporting a fixed cap asset to the C Chain
+type StakingNetworkAssetWorkflowTest struct {
+	ImageName string
+	Params
+}
+
+// Params are the parameters of the asset workflow test
+type Params struct {
+	// Fee of X Chain transactions on the test network
+	TxFee uint64
+}
+
+// DefaultParams returns the parameters the asset workflow test runs with unless they're overridden
+func DefaultParams() Params {
+	return Params{TxFee: 1000000}
+}
+
+// Run implements the Kurtosis Test interface
+func (test StakingNetworkAssetWorkflowTest) Run(network networks.Network, context testsuite.TestContext) {
+	castedNetwork := network.(avalancheNetwork.TestAvalancheNetwork)
+	networkAcceptanceTimeout := time.Duration(networkAcceptanceTimeoutRatio * float64(test.GetExecutionTimeout().Nanoseconds()))
+
+	var bootServiceID networks.ServiceID
+	for serviceID := range castedNetwork.GetAllBootServiceIDs() {
+		bootServiceID = serviceID
+		break
+	}
+	client, err := castedNetwork.GetAvalancheClient(bootServiceID)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to get Avalanche Client for boot node with serviceID: %s.", bootServiceID))
+	}
+	defer client.Close()
+
+	executor := NewAssetWorkflowTestExecutor(client, networkAcceptanceTimeout)
+	logrus.Infof("Executing asset workflow test...")
+	if err := executor.ExecuteTest(); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Asset Workflow Test failed."))
+	}
+}
+
+// GetNetworkLoader implements the Kurtosis Test interface
+func (test StakingNetworkAssetWorkflowTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
+		true,
+		test.ImageName,
+		avalancheService.DEBUG,
+		2,
+		2,
+		test.TxFee,
+		2*time.Second,
+		false,
+		make(map[networks.ConfigurationID]avalancheNetwork.TestAvalancheNetworkServiceConfig),
+		make(map[networks.ServiceID]networks.ConfigurationID),
+	)
+}
+
+// GetExecutionTimeout implements the Kurtosis Test interface
+func (test StakingNetworkAssetWorkflowTest) GetExecutionTimeout() time.Duration {
+	return 5 * time.Minute
+}
+
+// GetSetupBuffer implements the Kurtosis Test interface
+func (test StakingNetworkAssetWorkflowTest) GetSetupBuffer() time.Duration {
+	return 2 * time.Minute
+}
@@ -0,0 +1,144 @@
+package assets
+
+import (
+	"fmt"
+	"time"
+
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
+	"github.com/ava-labs/avalanche-testing/testsuite/tester"
+	"github.com/ava-labs/avalanchego/api"
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	assetUsername = "asset_creator"
+	assetPassword = "MyNameIs!Jeff"
+
+	fixedCapSupply         = uint64(1000000)
+	fixedCapRecipientShare = uint64(250000)
+	variableCapMintAmount  = uint64(777777)
+	cChainExportAmount     = uint64(100000)
+	denomination           = byte(9)
+)
+
+type executor struct {
+	client            *services.Client
+	acceptanceTimeout time.Duration
+}
+
+// NewAssetWorkflowTestExecutor returns a test executor that creates, mints, and transfers every type of X Chain asset
+// (fixed cap, variable cap, NFT, and property fx) and exports a fixed cap asset to the C Chain
+func NewAssetWorkflowTestExecutor(client *services.Client, acceptanceTimeout time.Duration) tester.AvalancheTester {
+	return &executor{
+		client:            client,
+		acceptanceTimeout: acceptanceTimeout,
+	}
+}
+
+// ExecuteTest implements the AvalancheTester interface
+func (e *executor) ExecuteTest() error {
+	user := api.UserPass{Username: assetUsername, Password: assetPassword}
+	runner := helpers.NewRPCWorkFlowRunner(e.client, user, e.acceptanceTimeout)
+
+	ownerAddress, err := runner.ImportGenesisFunds()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to fund asset creator.")
+	}
+	recipientAddress, err := e.client.XChainAPI().CreateAddress(user)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create recipient address.")
+	}
+	minters := []avm.Owners{{
+		Threshold: 1,
+		Minters:   []string{ownerAddress},
+	}}
+
+	// ====================================== FIXED CAP ASSET ======================================
+	fixedCapAssetID, err := runner.CreateFixedCapAsset("FixedCapToken", "FIX", denomination, []*avm.Holder{
+		{
+			Amount:  cjson.Uint64(fixedCapSupply - fixedCapRecipientShare),
+			Address: ownerAddress,
+		},
+		{
+			Amount:  cjson.Uint64(fixedCapRecipientShare),
+			Address: recipientAddress,
+		},
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create fixed cap asset.")
+	}
+	logrus.Infof("Created fixed cap asset %s and verified holder balances.", fixedCapAssetID)
+
+	// ====================================== VARIABLE CAP ASSET ===================================
+	variableCapAssetID, err := runner.CreateVariableCapAsset("VariableCapToken", "VAR", denomination, minters)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create variable cap asset.")
+	}
+	if err := runner.VerifyXChainAssetBalance(recipientAddress, variableCapAssetID, 0); err != nil {
+		return stacktrace.Propagate(err, "Variable cap asset should have no supply before minting.")
+	}
+	if err := runner.MintAsset(variableCapAssetID, variableCapMintAmount, recipientAddress); err != nil {
+		return stacktrace.Propagate(err, "Failed to mint variable cap asset.")
+	}
+	if err := runner.MintAsset(variableCapAssetID, variableCapMintAmount, recipientAddress); err != nil {
+		return stacktrace.Propagate(err, "Failed to mint variable cap asset a second time.")
+	}
+	logrus.Infof("Created variable cap asset %s and minted it twice.", variableCapAssetID)
+
+	// ====================================== NFT FAMILY ===========================================
+	nftAssetID, err := runner.CreateNFTFamily("NFTFamily", "NFT", minters)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create NFT family.")
+	}
+	for i := 0; i < 2; i++ {
+		if err := runner.MintNFT(nftAssetID, []byte(fmt.Sprintf("nft payload %d", i)), ownerAddress); err != nil {
+			return stacktrace.Propagate(err, "Failed to mint NFT %d.", i)
+		}
+	}
+	if err := runner.SendNFT(nftAssetID, 0, recipientAddress); err != nil {
+		return stacktrace.Propagate(err, "Failed to send NFT.")
+	}
+	logrus.Infof("Created NFT family %s, minted two NFTs and transferred one.", nftAssetID)
+
+	// ====================================== PROPERTY FX ==========================================
+	propertyAssetID, err := runner.CreatePropertyAsset(ownerAddress, "PropertyToken", "PROP")
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create property fx asset.")
+	}
+	if err := runner.MintProperty(ownerAddress, propertyAssetID, recipientAddress); err != nil {
+		return stacktrace.Propagate(err, "Failed to mint property.")
+	}
+	if err := runner.BurnProperty(recipientAddress, ownerAddress, propertyAssetID); err != nil {
+		return stacktrace.Propagate(err, "Failed to burn property.")
+	}
+	logrus.Infof("Created property fx asset %s, minted a property and burned it.", propertyAssetID)
+
+	// ====================================== EXPORT TO C CHAIN ====================================
+	cChainHexAddress, err := e.client.CChainAPI().ImportKey(user, avalancheNetwork.DefaultLocalNetGenesisConfig.FundedAddresses.PrivateKey)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to import genesis key to the C Chain.")
+	}
+	formatter, err := runner.AddressFormatter()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to get address formatter.")
+	}
+	cChainBech32Address, err := formatter.ConvertChain(ownerAddress, services.XChain, services.CChain)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to convert owner address to the C Chain.")
+	}
+	if err := runner.TransferAssetXChainToCChain(fixedCapAssetID, cChainExportAmount, cChainBech32Address, cChainHexAddress); err != nil {
+		return stacktrace.Propagate(err, "Failed to transfer fixed cap asset to the C Chain.")
+	}
+	expectedOwnerBalance := fixedCapSupply - fixedCapRecipientShare - cChainExportAmount
+	if err := runner.VerifyXChainAssetBalance(ownerAddress, fixedCapAssetID, expectedOwnerBalance); err != nil {
+		return stacktrace.Propagate(err, "Unexpected X Chain balance of fixed cap asset after export to the C Chain.")
+	}
+	logrus.Infof("Exported fixed cap asset %s to the C Chain and verified balances on both chains.", fixedCapAssetID)
+
+	return nil
+}
@@ -1,29 +1,34 @@
 package bombard
 
 import (
-	"fmt"
-	"math/rand"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/avalanche/services/ipc"
 	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
+	"github.com/ava-labs/avalanche-testing/testsuite/testaccounts"
 	"github.com/ava-labs/avalanche-testing/testsuite/tester"
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/utils/constants"
-	"github.com/ava-labs/avalanchego/utils/crypto"
-	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
 )
 
-// NewBombardExecutor returns a new bombard test bombardExecutor
-func NewBombardExecutor(clients []*services.Client, numTxs, txFee uint64, acceptanceTimeout time.Duration) tester.AvalancheTester {
+const (
+	// Seed from which the accounts that issue transactions are derived
+	bombardAccountSeed = "bombard"
+)
+
+// NewBombardExecutor returns a new bombard test bombardExecutor. [acceptances] must read the X Chain of the node behind
+// the first client, which is used to confirm the transactions.
+func NewBombardExecutor(clients []*services.Client, registry *services.ChainRegistry, acceptances *ipc.Consumer, numTxs, txFee uint64, acceptanceTimeout time.Duration) tester.AvalancheTester {
 	return &bombardExecutor{
 		normalClients:     clients,
+		registry:          registry,
+		acceptances:       acceptances,
 		numTxs:            numTxs,
 		acceptanceTimeout: acceptanceTimeout,
 		txFee:             txFee,
@@ -32,61 +37,40 @@ func NewBombardExecutor(clients []*services.Client, numTxs, txFee uint64, accept
 
 type bombardExecutor struct {
 	normalClients     []*services.Client
+	registry          *services.ChainRegistry
+	acceptances       *ipc.Consumer
 	acceptanceTimeout time.Duration
 	numTxs            uint64
 	txFee             uint64
 }
 
-func createRandomString() string {
-	return fmt.Sprintf("rand:%d", rand.Int())
-}
-
 // ExecuteTest implements the AvalancheTester interface
 func (e *bombardExecutor) ExecuteTest() error {
 	genesisClient := e.normalClients[0]
+	accounts, err := testaccounts.Derive(bombardAccountSeed, len(e.normalClients)-1)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to derive test accounts.")
+	}
+	formatter := e.registry.AddressFormatter()
 	secondaryClients := make([]*helpers.RPCWorkFlowRunner, len(e.normalClients)-1)
 	xChainAddrs := make([]string, len(e.normalClients)-1)
 	for i, client := range e.normalClients[1:] {
-		secondaryClients[i] = helpers.NewRPCWorkFlowRunner(
-			client,
-			api.UserPass{Username: createRandomString(), Password: createRandomString()},
-			e.acceptanceTimeout,
-		)
-		xChainAddress, _, err := secondaryClients[i].CreateDefaultAddresses()
+		secondaryClients[i] = helpers.NewRPCWorkFlowRunner(client, api.UserPass{}, e.acceptanceTimeout)
+		xChainAddress, err := accounts[i].XChainAddress(formatter)
 		if err != nil {
-			return stacktrace.Propagate(err, "Failed to create default addresses for client: %d", i)
+			return stacktrace.Propagate(err, "Failed to get X Chain address of %s", accounts[i])
 		}
 		xChainAddrs[i] = xChainAddress
 	}
 
-	genesisUser := api.UserPass{Username: createRandomString(), Password: createRandomString()}
-	highLevelGenesisClient := helpers.NewRPCWorkFlowRunner(
-		genesisClient,
-		genesisUser,
-		e.acceptanceTimeout,
-	)
-
-	if _, err := highLevelGenesisClient.ImportGenesisFunds(); err != nil {
-		return stacktrace.Propagate(err, "Failed to fund genesis client.")
-	}
-	addrs, err := genesisClient.XChainAPI().ListAddresses(genesisUser)
-	if err != nil {
-		return stacktrace.Propagate(err, "Failed to get genesis client's addresses")
-	}
-	if len(addrs) != 1 {
-		return stacktrace.NewError("Found unexecpted number of addresses for genesis client: %d", len(addrs))
-	}
-	genesisAddress := addrs[0]
-	logrus.Infof("Imported genesis funds at address: %s", genesisAddress)
-
 	// Fund X Chain Addresses enough to issue [numTxs]
 	seedAmount := (e.numTxs + 1) * e.txFee
-	if err := highLevelGenesisClient.FundXChainAddresses(xChainAddrs, seedAmount); err != nil {
+	if _, err := testaccounts.FundXChain(genesisClient, accounts, seedAmount, e.acceptanceTimeout); err != nil {
 		return stacktrace.Propagate(err, "Failed to fund X Chain Addresses for Clients")
 	}
 	logrus.Infof("Funded X Chain Addresses with seedAmount %v.", seedAmount)
 
-	codec, err := createXChainCodec()
+	codec, err := helpers.CreateXChainCodec()
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to initialize codec.")
 	}
@@ -94,7 +78,7 @@ func (e *bombardExecutor) ExecuteTest() error {
 	for i, client := range secondaryClients {
 		// Each address should have [e.txFee] remaining after sending [numTxs] and paying the fixed fee each time
 		if err := client.VerifyXChainAVABalance(xChainAddrs[i], seedAmount); err != nil {
-			return stacktrace.Propagate(err, "Failed to verify X Chain Balane for Client: %d", i)
+			return stacktrace.Propagate(err, "Failed to verify X Chain Balane for %s", accounts[i])
 		}
 		utxosBytes, _, err := genesisClient.XChainAPI().GetUTXOs([]string{xChainAddrs[i]}, 10, "", "")
 		if err != nil {
@@ -115,32 +99,12 @@ func (e *bombardExecutor) ExecuteTest() error {
 	logrus.Infof("Verified X Chain Balances and retrieved UTXOs.")
 
 	// Create a string of consecutive transactions for each secondary client to send
-	privateKeys := make([]*crypto.PrivateKeySECP256K1R, len(secondaryClients))
 	txLists := make([][][]byte, len(secondaryClients))
 	txIDLists := make([][]ids.ID, len(secondaryClients))
-	for i, client := range e.normalClients[1:] {
+	for i, account := range accounts {
 		utxo := utxoLists[i][0]
-		pkStr, err := client.XChainAPI().ExportKey(secondaryClients[i].User(), xChainAddrs[i])
-		if err != nil {
-			return stacktrace.Propagate(err, "Failed to export key.")
-		}
-
-		if !strings.HasPrefix(pkStr, constants.SecretKeyPrefix) {
-			return fmt.Errorf("private key missing %s prefix", constants.SecretKeyPrefix)
-		}
-		trimmedPrivateKey := strings.TrimPrefix(pkStr, constants.SecretKeyPrefix)
-		pkBytes, err := formatting.Decode(formatting.CB58, trimmedPrivateKey)
-		if err != nil {
-			return fmt.Errorf("problem parsing private key: %w", err)
-		}
-
-		factory := crypto.FactorySECP256K1R{}
-		skIntf, err := factory.ToPrivateKey(pkBytes)
-		sk := skIntf.(*crypto.PrivateKeySECP256K1R)
-		privateKeys[i] = sk
-
-		logrus.Infof("Creating string of %d transactions", e.numTxs)
-		txs, txIDs, err := CreateConsecutiveTransactions(utxo, e.numTxs, seedAmount, e.txFee, sk)
+		logrus.Infof("Creating string of %d transactions for %s", e.numTxs, account)
+		txs, txIDs, err := CreateConsecutiveTransactions(utxo, e.numTxs, seedAmount, e.txFee, account.Key, e.registry)
 		if err != nil {
 			return stacktrace.Propagate(err, "Failed to create transaction list.")
 		}
@@ -166,13 +130,25 @@ func (e *bombardExecutor) ExecuteTest() error {
 
 	duration := time.Since(startTime)
 	logrus.Infof("Finished issuing transaction lists in %v seconds.", duration.Seconds())
+	acceptanceTimes := make([]time.Duration, 0, len(txIDLists)*int(e.numTxs))
 	for _, txIDs := range txIDLists {
-		if err := highLevelGenesisClient.AwaitXChainTxs(txIDs...); err != nil {
-			stacktrace.Propagate(err, "Failed to confirm transactions.")
+		for _, txID := range txIDs {
+			accepted, err := e.acceptances.AwaitAcceptance(txID, e.acceptanceTimeout)
+			if err != nil {
+				return stacktrace.Propagate(err, "Failed to confirm transactions.")
+			}
+			acceptanceTimes = append(acceptanceTimes, accepted.AcceptedAt.Sub(startTime))
 		}
 	}
 
-	logrus.Infof("Confirmed all issued transactions.")
+	sort.Slice(acceptanceTimes, func(i, j int) bool { return acceptanceTimes[i] < acceptanceTimes[j] })
+	logrus.Infof(
+		"Confirmed all %d issued transactions. Time from the start of issuance to acceptance: median %v, 99th percentile %v, max %v.",
+		len(acceptanceTimes),
+		acceptanceTimes[len(acceptanceTimes)/2],
+		acceptanceTimes[len(acceptanceTimes)*99/100],
+		acceptanceTimes[len(acceptanceTimes)-1],
+	)
 
 	return nil
 }
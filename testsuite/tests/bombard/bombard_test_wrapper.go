@@ -8,6 +8,8 @@ import (
 
 	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
 	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/avalanche/services/ipc"
+	"github.com/ava-labs/avalanche-testing/testsuite/profiling"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
 )
@@ -18,36 +20,104 @@ const (
 	additionalNode2ServiceID                          = "additional-node-2"
 	seedAmount                                        = int64(50000000000000)
 	stakeAmount                                       = int64(30000000000000)
+
+	// Name of the directory in the suite's results directory that node profiles are copied to
+	profileResultsName = "bombardXChainTest"
+
+	// The execution timeout is the base timeout plus the per-transaction timeout for every transaction issued to a node
+	baseExecutionTimeout  = 5 * time.Minute
+	executionTimeoutPerTx = 300 * time.Millisecond
 )
 
+// Params are the parameters of the bombard test
+type Params struct {
+	// Number of transactions issued to each boot node
+	NumTxs uint64
+
+	// Fee of X Chain transactions on the test network
+	TxFee uint64
+
+	// How long to wait for each transaction to be accepted
+	AcceptanceTimeout time.Duration
+}
+
+// DefaultParams returns the parameters the bombard test runs with unless they're overridden
+func DefaultParams() Params {
+	return Params{
+		NumTxs:            1000,
+		TxFee:             1000000,
+		AcceptanceTimeout: 10 * time.Second,
+	}
+}
+
+// Validate implements the params.Validator interface
+func (p Params) Validate() error {
+	if p.NumTxs == 0 {
+		return stacktrace.NewError("NumTxs must be positive")
+	}
+	if p.AcceptanceTimeout <= 0 {
+		return stacktrace.NewError("AcceptanceTimeout must be positive")
+	}
+	return nil
+}
+
 // StakingNetworkBombardTest funds individual clients with a starting UTXO for each
 // and then creates a string of transactions to send to each one based off of the original UTXO.
 // Then it adds two nodes to ensure that they can bootstrap the new data on the X chain.
 type StakingNetworkBombardTest struct {
-	ImageName         string
-	NumTxs            uint64
-	TxFee             uint64
-	AcceptanceTimeout time.Duration
+	ImageName string
+	Params
 }
 
 // Run implements the Kurtosis Test interface
 func (test StakingNetworkBombardTest) Run(network networks.Network, context testsuite.TestContext) {
 	castedNetwork := network.(avalancheNetwork.TestAvalancheNetwork)
 	bootServiceIDs := castedNetwork.GetAllBootServiceIDs()
+	serviceIDs := make([]networks.ServiceID, 0, len(bootServiceIDs))
 	clients := make([]*avalancheService.Client, 0, len(bootServiceIDs))
 	for serviceID := range bootServiceIDs {
 		avalancheClient, err := castedNetwork.GetAvalancheClient(serviceID)
 		if err != nil {
 			context.Fatal(stacktrace.Propagate(err, "Failed to get Avalanche Client for boot node with serviceID: %s.", serviceID))
 		}
+		serviceIDs = append(serviceIDs, serviceID)
 		clients = append(clients, avalancheClient)
 	}
 
+	registry, err := castedNetwork.GetChainRegistry()
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to look up the network's chain IDs."))
+	}
+
+	// Transactions are confirmed on the first node, by reading the transactions it accepts over IPC
+	workingDirpath, err := castedNetwork.GetNodeWorkingDirpath(serviceIDs[0])
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to get working directory of %s.", serviceIDs[0]))
+	}
+	acceptances := ipc.NewConsumer(clients[0], registry, workingDirpath)
+	if err := acceptances.Publish(avalancheService.XChain); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to read accepted X Chain transactions of %s.", serviceIDs[0]))
+	}
+	defer acceptances.Close()
+
+	profiler, err := profiling.NewNodeProfiler(castedNetwork, bootServiceIDs)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to create node profiler."))
+	}
+	if err := profiler.Start(); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to start profiling nodes."))
+	}
+
 	// Execute the bombard test to issue [NumTxs] to each node
-	executor := NewBombardExecutor(clients, test.NumTxs, test.TxFee, test.AcceptanceTimeout)
+	executor := NewBombardExecutor(clients, registry, acceptances, test.NumTxs, test.TxFee, test.AcceptanceTimeout)
 	logrus.Infof("Executing bombard test...")
-	if err := executor.ExecuteTest(); err != nil {
-		context.Fatal(stacktrace.Propagate(err, "Bombard Test Failed."))
+	executionErr := executor.ExecuteTest()
+	// Collect the profiles even if the test failed, since they're most useful when the network falls behind
+	if err := profiler.Stop(avalancheService.ResultsDirpath(profileResultsName)); err != nil && executionErr == nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to collect node profiles."))
+	}
+	if executionErr != nil {
+		context.Fatal(stacktrace.Propagate(executionErr, "Bombard Test Failed."))
 	}
 
 	logrus.Infof("Bombard test completed successfully.")
@@ -89,6 +159,7 @@ func (test StakingNetworkBombardTest) GetNetworkLoader() (networks.NetworkLoader
 	)
 
 	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
 		true,
 		test.ImageName,
 		avalancheService.DEBUG,
@@ -96,6 +167,7 @@ func (test StakingNetworkBombardTest) GetNetworkLoader() (networks.NetworkLoader
 		2,
 		test.TxFee,
 		2*time.Second,
+		true,
 		serviceConfigs,
 		desiredServices,
 	)
@@ -103,7 +175,7 @@ func (test StakingNetworkBombardTest) GetNetworkLoader() (networks.NetworkLoader
 
 // GetExecutionTimeout implements the Kurtosis Test interface
 func (test StakingNetworkBombardTest) GetExecutionTimeout() time.Duration {
-	return 10 * time.Minute
+	return baseExecutionTimeout + time.Duration(test.NumTxs)*executionTimeoutPerTx
 }
 
 // GetSetupBuffer implements the Kurtosis Test interface
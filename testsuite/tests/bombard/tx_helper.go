@@ -3,51 +3,21 @@ package bombard
 import (
 	"fmt"
 
-	"github.com/ava-labs/avalanche-testing/utils/constants"
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/codec"
 	"github.com/ava-labs/avalanchego/utils/crypto"
-	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
-	"github.com/ava-labs/avalanchego/vms/propertyfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
-func createXChainCodec() (codec.Manager, error) {
-	c := codec.NewDefault()
-	errs := wrappers.Errs{}
-	errs.Add(
-		c.RegisterType(&avm.BaseTx{}),
-		c.RegisterType(&avm.CreateAssetTx{}),
-		c.RegisterType(&avm.OperationTx{}),
-		c.RegisterType(&avm.ImportTx{}),
-		c.RegisterType(&avm.ExportTx{}),
-
-		c.RegisterType(&secp256k1fx.TransferInput{}),
-		c.RegisterType(&secp256k1fx.MintOutput{}),
-		c.RegisterType(&secp256k1fx.TransferOutput{}),
-		c.RegisterType(&secp256k1fx.MintOperation{}),
-		c.RegisterType(&secp256k1fx.Credential{}),
-
-		c.RegisterType(&propertyfx.MintOutput{}),
-		c.RegisterType(&propertyfx.OwnedOutput{}),
-		c.RegisterType(&propertyfx.MintOperation{}),
-		c.RegisterType(&propertyfx.BurnOperation{}),
-		c.RegisterType(&propertyfx.Credential{}),
-	)
-
-	codecManager := codec.NewDefaultManager()
-	codecManager.RegisterCodec(uint16(0), c)
-
-	return codecManager, errs.Err
-}
-
 // CreateSingleUTXOTx returns a transaction spending an individual utxo owned by [privateKey]
-func CreateSingleUTXOTx(utxo *avax.UTXO, inputAmount, outputAmount uint64, address ids.ShortID, privateKey *crypto.PrivateKeySECP256K1R, codec codec.Manager) (*avm.Tx, error) {
+func CreateSingleUTXOTx(utxo *avax.UTXO, inputAmount, outputAmount uint64, address ids.ShortID, privateKey *crypto.PrivateKeySECP256K1R, registry *services.ChainRegistry, codec codec.Manager) (*avm.Tx, error) {
 	keys := [][]*crypto.PrivateKeySECP256K1R{{privateKey}}
 	outs := []*avax.TransferableOutput{&avax.TransferableOutput{
-		Asset: avax.Asset{ID: constants.AvaxAssetID},
+		Asset: avax.Asset{ID: registry.AvaxAssetID()},
 		Out: &secp256k1fx.TransferOutput{
 			Amt: outputAmount,
 			OutputOwners: secp256k1fx.OutputOwners{
@@ -67,13 +37,13 @@ func CreateSingleUTXOTx(utxo *avax.UTXO, inputAmount, outputAmount uint64, addre
 
 	ins := []*avax.TransferableInput{&avax.TransferableInput{
 		UTXOID: utxo.UTXOID,
-		Asset:  avax.Asset{ID: constants.AvaxAssetID},
+		Asset:  avax.Asset{ID: registry.AvaxAssetID()},
 		In:     transferableIn.(avax.TransferableIn),
 	}}
 
 	tx := &avm.Tx{UnsignedTx: &avm.BaseTx{BaseTx: avax.BaseTx{
-		NetworkID:    constants.NetworkID,
-		BlockchainID: constants.XChainID,
+		NetworkID:    registry.NetworkID(),
+		BlockchainID: registry.XChainID(),
 		Outs:         outs,
 		Ins:          ins,
 	}}}
@@ -86,11 +56,11 @@ func CreateSingleUTXOTx(utxo *avax.UTXO, inputAmount, outputAmount uint64, addre
 
 // CreateConsecutiveTransactions returns a string of [numTxs] sending [utxo] back and forth
 // assumes that [privateKey] is the sole owner of [utxo]
-func CreateConsecutiveTransactions(utxo *avax.UTXO, numTxs, amount, txFee uint64, privateKey *crypto.PrivateKeySECP256K1R) ([][]byte, []ids.ID, error) {
+func CreateConsecutiveTransactions(utxo *avax.UTXO, numTxs, amount, txFee uint64, privateKey *crypto.PrivateKeySECP256K1R, registry *services.ChainRegistry) ([][]byte, []ids.ID, error) {
 	if numTxs*txFee > amount {
 		return nil, nil, fmt.Errorf("Insufficient starting funds to send %v transactions with a txFee of %v", numTxs, txFee)
 	}
-	codec, err := createXChainCodec()
+	codec, err := helpers.CreateXChainCodec()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -102,7 +72,7 @@ func CreateConsecutiveTransactions(utxo *avax.UTXO, numTxs, amount, txFee uint64
 	inputAmount := amount
 	outputAmount := amount - txFee
 	for i := uint64(0); i < numTxs; i++ {
-		tx, err := CreateSingleUTXOTx(utxo, inputAmount, outputAmount, address, privateKey, codec)
+		tx, err := CreateSingleUTXOTx(utxo, inputAmount, outputAmount, address, privateKey, registry, codec)
 		if err != nil {
 			return nil, nil, err
 		}
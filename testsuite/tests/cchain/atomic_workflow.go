@@ -42,7 +42,10 @@ func (aw *atomicWorkflowTest) ExecuteTest() error {
 	_, _ = aw.client.KeystoreAPI().CreateUser(user)
 	xClient := aw.client.XChainAPI()
 	cClient := aw.client.CChainAPI()
-	cEthClient := aw.client.CChainEthAPI()
+	cEthClient, err := aw.client.CChainEthAPI()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the C Chain websocket: %w", err)
+	}
 
 	xAddr, err := xClient.ImportKey(user, prefixedPrivateKey)
 	if err != nil {
@@ -82,7 +85,14 @@ func (aw *atomicWorkflowTest) ExecuteTest() error {
 	expectedAVAXBalance = expectedAVAXBalance - aw.txFee
 
 	logrus.Infof("Exporting AVAX")
-	bech32CAddr := fmt.Sprintf("C%s", xAddr[1:])
+	formatter, err := workflowRunner.AddressFormatter()
+	if err != nil {
+		return fmt.Errorf("failed to get address formatter: %w", err)
+	}
+	bech32CAddr, err := formatter.ConvertChain(xAddr, services.XChain, services.CChain)
+	if err != nil {
+		return fmt.Errorf("failed to convert X Chain address to C Chain: %w", err)
+	}
 	txID, err := xClient.ExportAVAX(user, nil, "", exportAVAXAmount, bech32CAddr)
 	if err != nil {
 		return fmt.Errorf("failed to export AVAX: %w", err)
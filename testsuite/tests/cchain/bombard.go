@@ -3,12 +3,16 @@ package cchain
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
+	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/avalanche-testing/avalanche/services"
 	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
 	"github.com/ava-labs/avalanche-testing/testsuite/tester"
+	"github.com/ava-labs/coreth"
 	"github.com/ava-labs/coreth/core/types"
 	"github.com/ava-labs/coreth/ethclient"
 	"github.com/ethereum/go-ethereum/common"
@@ -18,29 +22,42 @@ import (
 )
 
 type parallelBasicTxXputTest struct {
-	client   *services.Client
+	clients  []*services.Client
 	numLists int
 	numTxs   int
+
+	// Timeout of each request made to the nodes
+	requestTimeout time.Duration
+
+	// Where the throughput report is written, or empty to only log it
+	reportFilepath string
 }
 
-// NewBasicTransactionThroughputTest returns a test executor that will run a small xput test of [numTxs] from each of [numLists] accounts
-// Note: all issued to the same node.
-func NewBasicTransactionThroughputTest(client *services.Client, numLists int, numTxs int) tester.AvalancheTester {
+// NewBasicTransactionThroughputTest returns a test executor that will run a small xput test of [numTxs] from each of
+// [numLists] accounts, timing out each request to the nodes after [requestTimeout]. The lists are spread across the nodes of [clients], and every node observes when each
+// transaction is included, from which the throughput and inclusion latency are reported. Coreth doesn't gossip
+// transactions, so each is only in the pool of the node it was issued to until it's included.
+func NewBasicTransactionThroughputTest(clients []*services.Client, numLists int, numTxs int, requestTimeout time.Duration, reportFilepath string) tester.AvalancheTester {
 	return &parallelBasicTxXputTest{
-		client:   client,
-		numLists: numLists,
-		numTxs:   numTxs,
+		clients:        clients,
+		numLists:       numLists,
+		numTxs:         numTxs,
+		requestTimeout: requestTimeout,
+		reportFilepath: reportFilepath,
 	}
 }
 
 // ExecuteTest ...
 func (p *parallelBasicTxXputTest) ExecuteTest() error {
 	workflowRunner := helpers.NewRPCWorkFlowRunner(
-		p.client,
+		p.clients[0],
 		user,
-		3*time.Second,
+		p.requestTimeout,
 	)
-	cEthClient := p.client.CChainEthAPI()
+	nodes, err := newEthNodes(p.clients)
+	if err != nil {
+		return err
+	}
 
 	pks := make([]*ecdsa.PrivateKey, p.numLists)
 	addrs := make([]common.Address, p.numLists)
@@ -60,43 +77,187 @@ func (p *parallelBasicTxXputTest) ExecuteTest() error {
 	}
 
 	txLists := make([][]*types.Transaction, p.numLists)
+	issuers := make([]int, p.numLists)
 	for i := 0; i < p.numLists; i++ {
 		txs, err := createConsecutiveBasicEthTransactions(pks[i], addrs[i], 0, p.numTxs)
 		if err != nil {
 			return err
 		}
 		txLists[i] = txs
+		issuers[i] = i % len(nodes.clients)
 	}
 
-	errs := make(chan error, p.numLists)
-	launchIssueTxList := func(ctx context.Context, ethclient *ethclient.Client, txList []*types.Transaction) {
-		err := issueTxList(ctx, cEthClient, txList)
-		errs <- err
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recorder := newLoadRecorder(txLists, issuers, len(nodes.clients))
+	observeErrs := make(chan error, len(nodes.clients))
+	for i, ethClient := range nodes.clients {
+		stopObserving, err := observeNode(ctx, i, ethClient, recorder, observeErrs)
+		if err != nil {
+			return fmt.Errorf("failed to observe node %d: %w", i, err)
+		}
+		defer stopObserving()
 	}
+	headerCtx, cancelHeader := context.WithTimeout(ctx, p.requestTimeout)
+	startHeader, err := nodes.clients[0].HeaderByNumber(headerCtx, nil)
+	cancelHeader()
+	if err != nil {
+		return fmt.Errorf("failed to get latest header: %w", err)
+	}
+
 	launchedIssuers := time.Now()
-	for _, txList := range txLists {
-		go launchIssueTxList(context.Background(), cEthClient, txList)
+	wg := sync.WaitGroup{}
+	issueErrs := make(chan error, p.numLists)
+	for i, txList := range txLists {
+		wg.Add(1)
+		go func(issuer int, txList []*types.Transaction) {
+			defer wg.Done()
+			for _, tx := range txList {
+				recorder.issued(tx.Hash(), time.Now())
+				sendCtx, cancelSend := context.WithTimeout(ctx, p.requestTimeout)
+				err := nodes.clients[issuer].SendTransaction(sendCtx, tx)
+				cancelSend()
+				if err != nil {
+					issueErrs <- fmt.Errorf("failed to issue transaction with nonce %d to node %d: %w", tx.Nonce(), issuer, err)
+					return
+				}
+			}
+		}(issuers[i], txList)
+	}
+	wg.Wait()
+	close(issueErrs)
+	if err := <-issueErrs; err != nil {
+		return err
+	}
+	finishedIssuing := time.Now()
+	logrus.Infof("Took %v to issue %d lists of %d transactions across %d nodes", finishedIssuing.Sub(launchedIssuers).Seconds(), p.numLists, p.numTxs, len(nodes.clients))
+
+	lastBlock, err := awaitLastTxs(ctx, nodes, txLists, issuers, p.requestTimeout)
+	if err != nil {
+		return err
 	}
-	startedGoRoutines := time.Now()
-	logrus.Infof("Took %v to launch issuers", startedGoRoutines.Sub(launchedIssuers).Seconds())
+	if err := awaitLoad(recorder, lastBlock, observeErrs); err != nil {
+		return err
+	}
+	finishedConfirming := time.Now()
+	logrus.Infof("Every node saw every transaction included after %v seconds. Total time start to finish: %v", finishedConfirming.Sub(finishedIssuing).Seconds(), finishedConfirming.Sub(launchedIssuers).Seconds())
 
-	for i := 0; i < p.numLists; i++ {
-		err := <-errs
+	// The blocks are only fetched now that the load is over, so that fetching them doesn't slow the nodes down
+	blocks := make([]*types.Block, 0, lastBlock-startHeader.Number.Uint64())
+	for number := startHeader.Number.Uint64() + 1; number <= lastBlock; number++ {
+		blockCtx, cancelBlock := context.WithTimeout(ctx, p.requestTimeout)
+		block, err := nodes.clients[0].BlockByNumber(blockCtx, new(big.Int).SetUint64(number))
+		cancelBlock()
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("failed to get block %d: %w", number, err)
 		}
+		blocks = append(blocks, block)
 	}
-	finishedIssuing := time.Now()
-	logrus.Infof("Took %v to finish issuing after launching issuers", finishedIssuing.Sub(launchedIssuers).Seconds())
-
-	time.Sleep(3 * time.Second)
-	for _, txList := range txLists {
-		if err := confirmTxList(context.Background(), cEthClient, txList); err != nil {
+	report, err := recorder.report(blocks)
+	if err != nil {
+		return err
+	}
+	logrus.Infof(
+		"Included %d transactions in %d blocks over %v: %.2f transactions/s, %.2f blocks/s, %.0f gas/s.",
+		report.Txs, report.Blocks, report.Duration, report.TxsPerSecond, report.BlocksPerSecond, report.GasPerSecond,
+	)
+	logrus.Infof("Inclusion latency seen by other nodes: %s.", report.InclusionLatency)
+	if p.reportFilepath != "" {
+		if err := report.write(p.reportFilepath); err != nil {
 			return err
 		}
 	}
-	finishedConfirming := time.Now()
-	logrus.Infof("Finished confirming after %v seconds. Total time start to finish: %v", finishedConfirming.Sub(finishedIssuing).Seconds(), finishedConfirming.Sub(launchedIssuers).Seconds())
+	return nil
+}
+
+// observeNode subscribes to the heads of node [node], and records them in [recorder] until [ctx] is done or the
+// returned function is called. Subscription failures are sent to [errs].
+func observeNode(ctx context.Context, node int, ethClient *ethclient.Client, recorder *loadRecorder, errs chan<- error) (func(), error) {
+	heads := make(chan *types.Header, 64)
+	headSubscription, err := ethClient.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to new heads: %w", err)
+	}
 
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case head := <-heads:
+				recorder.head(node, head, time.Now())
+			case err := <-headSubscription.Err():
+				errs <- fmt.Errorf("head subscription of node %d failed: %w", node, err)
+				return
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		headSubscription.Unsubscribe()
+	}, nil
+}
+
+// awaitLastTxs waits until the last transaction of each of [txLists] is included, polling the node it was issued to
+// as [issuers] gives, and returns the highest block including one. Since the transactions of a list have consecutive
+// nonces, every transaction of the load is included by then. It fails if no more are included for receiptTimeout, or
+// if a poll takes longer than [requestTimeout].
+func awaitLastTxs(ctx context.Context, nodes ethNodes, txLists [][]*types.Transaction, issuers []int, requestTimeout time.Duration) (uint64, error) {
+	pending := make(map[int]common.Hash)
+	for i, txList := range txLists {
+		if len(txList) > 0 {
+			pending[i] = txList[len(txList)-1].Hash()
+		}
+	}
+	lastBlock := uint64(0)
+	lastProgress := time.Now()
+	for len(pending) > 0 {
+		for i, txHash := range pending {
+			receiptCtx, cancelReceipt := context.WithTimeout(ctx, requestTimeout)
+			receipt, err := nodes.clients[issuers[i]].TransactionReceipt(receiptCtx, txHash)
+			cancelReceipt()
+			if errors.Is(err, coreth.NotFound) {
+				continue
+			} else if err != nil {
+				return 0, fmt.Errorf("failed to get receipt of %s: %w", txHash.Hex(), err)
+			}
+			if number := receipt.BlockNumber.Uint64(); number > lastBlock {
+				lastBlock = number
+			}
+			delete(pending, i)
+			lastProgress = time.Now()
+		}
+		if len(pending) == 0 {
+			break
+		}
+		if time.Since(lastProgress) > receiptTimeout {
+			return 0, fmt.Errorf("the last transactions of %d lists weren't included within %v of the last", len(pending), receiptTimeout)
+		}
+		time.Sleep(receiptPollInterval)
+	}
+	return lastBlock, nil
+}
+
+// awaitLoad waits until every node has been notified of a head at or above [lastBlock], failing if no node makes
+// progress for receiptTimeout or an observer fails
+func awaitLoad(recorder *loadRecorder, lastBlock uint64, observeErrs <-chan error) error {
+	lowest := recorder.lowestHead()
+	lastProgress := time.Now()
+	for lowest < lastBlock {
+		select {
+		case err := <-observeErrs:
+			return err
+		case <-time.After(receiptPollInterval):
+		}
+		if head := recorder.lowestHead(); head > lowest {
+			lowest = head
+			lastProgress = time.Now()
+		} else if time.Since(lastProgress) > receiptTimeout {
+			return fmt.Errorf("a node's head is still %d, not %d, %v after the last progress", lowest, lastBlock, receiptTimeout)
+		}
+	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package cchain
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
@@ -8,6 +9,7 @@ import (
 
 	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
 	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/profiling"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
 )
@@ -16,26 +18,67 @@ const (
 	normalNodeConfigID       networks.ConfigurationID = "normal-config"
 	additionalNode1ServiceID                          = "additional-node-1"
 	additionalNode2ServiceID                          = "additional-node-2"
+
+	// Name of the directory in the suite's results directory that node profiles are copied to, and the throughput
+	// report is written to
+	profileResultsName = "virtuousCorethTest"
+
+	// Name of the throughput report in the results directory
+	throughputReportFilename = "throughput.json"
+
+	// The execution timeout is the base timeout plus the per-transaction timeout for every transaction issued
+	baseExecutionTimeout  = 3 * time.Minute
+	executionTimeoutPerTx = 200 * time.Millisecond
 )
 
+// Params are the parameters of the virtuous C-Chain test
+type Params struct {
+	// Number of transactions in each list of transactions
+	NumTxs int
+
+	// Number of lists of transactions, each issued from its own account
+	NumTxLists int
+
+	// Fee of X Chain transactions on the test network
+	TxFee uint64
+
+	// Timeout of each request the throughput test makes to the nodes
+	RequestTimeout time.Duration
+}
+
+// DefaultParams returns the parameters the virtuous C-Chain test runs with unless they're overridden
+func DefaultParams() Params {
+	return Params{
+		NumTxs:         100,
+		NumTxLists:     3,
+		TxFee:          1000000,
+		RequestTimeout: 3 * time.Second,
+	}
+}
+
+// Validate implements the params.Validator interface
+func (p Params) Validate() error {
+	if p.NumTxs <= 0 || p.NumTxLists <= 0 {
+		return stacktrace.NewError("NumTxs and NumTxLists must be positive")
+	}
+	if p.RequestTimeout <= 0 {
+		return stacktrace.NewError("RequestTimeout must be positive")
+	}
+	return nil
+}
+
 // Test runs a series of basic C-Chain tests on a network of
 // virtuous nodes
 type Test struct {
-	ImageName      string
-	NumTxs         int
-	NumTxLists     int
-	TxFee          uint64
-	RequestTimeout time.Duration
+	ImageName string
+	Params
 }
 
 // NewVirtuousCChainTest ...
-func NewVirtuousCChainTest(imageName string, numTxs int, numTxLists int, txFee uint64, requestTimeout time.Duration) testsuite.Test {
+func NewVirtuousCChainTest(imageName string, params Params) testsuite.Test {
 	return &Test{
-		ImageName:      imageName,
-		NumTxs:         numTxs,
-		NumTxLists:     numTxLists,
-		TxFee:          txFee,
-		RequestTimeout: requestTimeout,
+		ImageName: imageName,
+		Params:    params,
 	}
 }
 
@@ -51,6 +94,31 @@ func (test Test) Run(network networks.Network, context testsuite.TestContext) {
 		}
 		clients = append(clients, avalancheClient)
 	}
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+
+	profiler, err := profiling.NewNodeProfiler(castedNetwork, bootServiceIDs)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to create node profiler."))
+	}
+	if err := profiler.Start(); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to start profiling nodes."))
+	}
+
+	reportFilepath := filepath.Join(avalancheService.ResultsDirpath(profileResultsName), throughputReportFilename)
+	executor := NewBasicTransactionThroughputTest(clients, test.NumTxLists, test.NumTxs, test.RequestTimeout, reportFilepath)
+	logrus.Infof("Executing C-Chain throughput test...")
+	executionErr := executor.ExecuteTest()
+	// Collect the profiles even if the test failed, since they're most useful when the network falls behind
+	if err := profiler.Stop(avalancheService.ResultsDirpath(profileResultsName)); err != nil && executionErr == nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to collect node profiles."))
+	}
+	if executionErr != nil {
+		context.Fatal(stacktrace.Propagate(executionErr, "C-Chain throughput test failed."))
+	}
 
 	logrus.Infof("C-Chain Tests completed successfully.")
 	logrus.Infof("Adding two additional nodes and waiting for them to bootstrap...")
@@ -91,6 +159,7 @@ func (test Test) GetNetworkLoader() (networks.NetworkLoader, error) {
 	)
 
 	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
 		true,
 		test.ImageName,
 		avalancheService.DEBUG,
@@ -98,6 +167,7 @@ func (test Test) GetNetworkLoader() (networks.NetworkLoader, error) {
 		2,
 		test.TxFee,
 		2*time.Second,
+		true,
 		serviceConfigs,
 		desiredServices,
 	)
@@ -105,7 +175,7 @@ func (test Test) GetNetworkLoader() (networks.NetworkLoader, error) {
 
 // GetExecutionTimeout implements the Kurtosis Test interface
 func (test Test) GetExecutionTimeout() time.Duration {
-	return 4 * time.Minute
+	return baseExecutionTimeout + time.Duration(test.NumTxs*test.NumTxLists)*executionTimeoutPerTx
 }
 
 // GetSetupBuffer implements the Kurtosis Test interface
@@ -0,0 +1,396 @@
+package cchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/tester"
+	"github.com/ava-labs/coreth"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Supply of the token, minted to the deployer
+	tokenSupply = 1000000
+
+	// The slots the store's fill call writes
+	fillStart = 1000
+	fillCount = 100
+	fillSeed  = 7
+)
+
+// Values the test writes to the store with set
+var storeValues = map[int64]int64{1: 11, 2: 22, 42: 4242}
+
+// tokenEvent is a decoded Transfer or Approval event of the token. For an Approval, From is the owner and To the
+// spender.
+type tokenEvent struct {
+	Name  string
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+}
+
+// String implements the fmt.Stringer interface
+func (event tokenEvent) String() string {
+	return fmt.Sprintf("%s(%s, %s, %s)", event.Name, event.From.Hex(), event.To.Hex(), event.Value)
+}
+
+// decodeTokenEvent decodes a token event from the topics and data of its log
+func decodeTokenEvent(topics []common.Hash, data []byte) (tokenEvent, error) {
+	if len(topics) != 3 {
+		return tokenEvent{}, fmt.Errorf("expected 3 topics, found %d", len(topics))
+	}
+	event, err := tokenContract.abi.EventByID(topics[0])
+	if err != nil {
+		return tokenEvent{}, err
+	}
+	outputs, err := tokenContract.unpack(event.Name, data)
+	if err != nil {
+		return tokenEvent{}, fmt.Errorf("failed to unpack %s event: %w", event.Name, err)
+	}
+	return tokenEvent{
+		Name:  event.Name,
+		From:  common.BytesToAddress(topics[1].Bytes()),
+		To:    common.BytesToAddress(topics[2].Bytes()),
+		Value: outputs[0].(*big.Int),
+	}, nil
+}
+
+// decodeTokenLogs decodes the token events of [logs]
+func decodeTokenLogs(logs []types.Log) ([]tokenEvent, error) {
+	events := make([]tokenEvent, 0, len(logs))
+	for _, log := range logs {
+		event, err := decodeTokenEvent(log.Topics, log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode log %d of tx %s: %w", log.Index, log.TxHash.Hex(), err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// tokenBalanceSlot returns the storage slot of the token balance of [owner]
+func tokenBalanceSlot(owner common.Address) common.Hash {
+	return ethcrypto.Keccak256Hash(common.LeftPadBytes(owner.Bytes(), 32), common.LeftPadBytes([]byte{1}, 32))
+}
+
+// tokenAllowanceSlot returns the storage slot of the allowance of [spender] over the tokens of [owner]
+func tokenAllowanceSlot(owner common.Address, spender common.Address) common.Hash {
+	ownerSlot := ethcrypto.Keccak256(common.LeftPadBytes(owner.Bytes(), 32), common.LeftPadBytes([]byte{2}, 32))
+	return ethcrypto.Keccak256Hash(common.LeftPadBytes(spender.Bytes(), 32), ownerSlot)
+}
+
+// contractWorkflowTest deploys an ERC-20 token and a contract that writes many storage slots, calls their methods,
+// and checks that every node returns the same results, events and storage
+type contractWorkflowTest struct {
+	// The clients of the nodes, of which the first is issued transactions
+	clients []*services.Client
+	nodes   ethNodes
+}
+
+// NewContractWorkflowTest returns a test of deploying and calling contracts, issued to the first of [clients] and
+// checked on all of them
+func NewContractWorkflowTest(clients []*services.Client) tester.AvalancheTester {
+	return &contractWorkflowTest{clients: clients}
+}
+
+// ExecuteTest implements the AvalancheTester interface
+func (c *contractWorkflowTest) ExecuteTest() error {
+	ctx := context.Background()
+	nodes, err := newEthNodes(c.clients)
+	if err != nil {
+		return err
+	}
+	c.nodes = nodes
+
+	deployer, err := newEthAccount()
+	if err != nil {
+		return err
+	}
+	spender, err := newEthAccount()
+	if err != nil {
+		return err
+	}
+	recipient, err := newEthAccount()
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Funding deployer %s and spender %s.", deployer.address.Hex(), spender.address.Hex())
+	if err := fundEthAccounts(c.clients[0], deployer, spender); err != nil {
+		return err
+	}
+
+	if err := c.testToken(ctx, deployer, spender, recipient); err != nil {
+		return fmt.Errorf("token workflow failed: %w", err)
+	}
+	logrus.Infof("Token workflow succeeded.")
+	if err := c.testStore(ctx, deployer); err != nil {
+		return fmt.Errorf("store workflow failed: %w", err)
+	}
+	logrus.Infof("Store workflow succeeded.")
+	return nil
+}
+
+// testToken deploys the token from [deployer], transfers to [recipient] directly and through [spender], and checks
+// the balances, events and storage on every node
+func (c *contractWorkflowTest) testToken(ctx context.Context, deployer *ethAccount, spender *ethAccount, recipient *ethAccount) error {
+	deployData, err := tokenContract.deployData(big.NewInt(tokenSupply))
+	if err != nil {
+		return fmt.Errorf("failed to encode deployment: %w", err)
+	}
+	deployReceipt, err := c.nodes.issue(ctx, deployer, nil, deployData)
+	if err != nil {
+		return fmt.Errorf("failed to deploy token: %w", err)
+	}
+	token := deployReceipt.ContractAddress
+	if expected := ethcrypto.CreateAddress(deployer.address, deployer.nonce-1); token != expected {
+		return fmt.Errorf("token was deployed at %s, but expected %s", token.Hex(), expected.Hex())
+	}
+	logrus.Infof("Deployed token at %s in block %d.", token.Hex(), deployReceipt.BlockNumber)
+	if err := c.nodes.checkCode(ctx, token); err != nil {
+		return err
+	}
+
+	// Subscribed through the last node, so that it receives events of transactions issued to another node
+	subscribedLogs := make(chan types.Log, 16)
+	subscription, err := c.nodes.clients[len(c.nodes.clients)-1].SubscribeFilterLogs(ctx, coreth.FilterQuery{Addresses: []common.Address{token}}, subscribedLogs)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to token logs: %w", err)
+	}
+	defer subscription.Unsubscribe()
+
+	calls := []struct {
+		from   *ethAccount
+		method string
+		args   []interface{}
+	}{
+		{deployer, "transfer", []interface{}{recipient.address, big.NewInt(300)}},
+		{deployer, "approve", []interface{}{spender.address, big.NewInt(200)}},
+		{spender, "transferFrom", []interface{}{deployer.address, recipient.address, big.NewInt(150)}},
+	}
+	var lastReceipt *types.Receipt
+	for _, call := range calls {
+		data, err := tokenContract.abi.Pack(call.method, call.args...)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", call.method, err)
+		}
+		if lastReceipt, err = c.nodes.issue(ctx, call.from, &token, data); err != nil {
+			return fmt.Errorf("%s failed: %w", call.method, err)
+		}
+	}
+
+	// A transfer exceeding the remaining allowance is included, but fails without emitting events
+	data, err := tokenContract.abi.Pack("transferFrom", deployer.address, recipient.address, big.NewInt(51))
+	if err != nil {
+		return fmt.Errorf("failed to encode transferFrom: %w", err)
+	}
+	tx, err := c.nodes.send(ctx, spender, &token, data, 100000)
+	if err != nil {
+		return err
+	}
+	if failedReceipt, err := c.nodes.awaitReceipt(ctx, c.nodes.clients[0], tx.Hash()); err != nil {
+		return err
+	} else if failedReceipt.Status != types.ReceiptStatusFailed || len(failedReceipt.Logs) != 0 {
+		return fmt.Errorf("expected transferFrom exceeding the allowance to fail without logs, but it had status %d and %d logs", failedReceipt.Status, len(failedReceipt.Logs))
+	}
+	if err := c.nodes.awaitOnAllNodes(ctx, tx.Hash()); err != nil {
+		return err
+	}
+
+	// Every node must return the same balances, both from calls to the token's view methods and from its storage
+	views := []struct {
+		method   string
+		args     []interface{}
+		slot     common.Hash
+		expected int64
+	}{
+		{"totalSupply", nil, common.Hash{}, tokenSupply},
+		{"balanceOf", []interface{}{deployer.address}, tokenBalanceSlot(deployer.address), tokenSupply - 450},
+		{"balanceOf", []interface{}{recipient.address}, tokenBalanceSlot(recipient.address), 450},
+		{"balanceOf", []interface{}{spender.address}, tokenBalanceSlot(spender.address), 0},
+		{"allowance", []interface{}{deployer.address, spender.address}, tokenAllowanceSlot(deployer.address, spender.address), 50},
+	}
+	for _, view := range views {
+		if err := c.checkView(ctx, tokenContract, token, view.method, view.args, big.NewInt(view.expected)); err != nil {
+			return err
+		}
+		if err := c.checkStorage(ctx, token, view.slot, common.BigToHash(big.NewInt(view.expected))); err != nil {
+			return err
+		}
+	}
+
+	expectedEvents := []tokenEvent{
+		{Name: "Transfer", From: common.Address{}, To: deployer.address, Value: big.NewInt(tokenSupply)},
+		{Name: "Transfer", From: deployer.address, To: recipient.address, Value: big.NewInt(300)},
+		{Name: "Approval", From: deployer.address, To: spender.address, Value: big.NewInt(200)},
+		{Name: "Transfer", From: deployer.address, To: recipient.address, Value: big.NewInt(150)},
+	}
+	allLogs := coreth.FilterQuery{
+		FromBlock: deployReceipt.BlockNumber,
+		ToBlock:   lastReceipt.BlockNumber,
+		Addresses: []common.Address{token},
+	}
+	if err := c.checkFilteredEvents(ctx, allLogs, expectedEvents); err != nil {
+		return err
+	}
+	transfersToRecipient := coreth.FilterQuery{
+		FromBlock: deployReceipt.BlockNumber,
+		Addresses: []common.Address{token},
+		Topics:    [][]common.Hash{{tokenContract.abi.Events["Transfer"].ID}, nil, {common.BytesToHash(recipient.address.Bytes())}},
+	}
+	if err := c.checkFilteredEvents(ctx, transfersToRecipient, []tokenEvent{expectedEvents[1], expectedEvents[3]}); err != nil {
+		return err
+	}
+
+	// The subscription was made after the deployment, so it only receives the events of the calls
+	received := make([]types.Log, 0, len(calls))
+	for len(received) < len(calls) {
+		select {
+		case log := <-subscribedLogs:
+			received = append(received, log)
+		case err := <-subscription.Err():
+			return fmt.Errorf("log subscription failed: %w", err)
+		case <-time.After(receiptTimeout):
+			return fmt.Errorf("received %d of %d events through the log subscription", len(received), len(calls))
+		}
+	}
+	receivedEvents, err := decodeTokenLogs(received)
+	if err != nil {
+		return err
+	}
+	if err := compareEvents(receivedEvents, expectedEvents[1:]); err != nil {
+		return fmt.Errorf("unexpected events from the log subscription: %w", err)
+	}
+	return nil
+}
+
+// testStore deploys the store from [deployer], sets some slots and fills many others, and checks the values and
+// storage on every node
+func (c *contractWorkflowTest) testStore(ctx context.Context, deployer *ethAccount) error {
+	deployReceipt, err := c.nodes.issue(ctx, deployer, nil, storeContract.bytecode)
+	if err != nil {
+		return fmt.Errorf("failed to deploy store: %w", err)
+	}
+	store := deployReceipt.ContractAddress
+	logrus.Infof("Deployed store at %s in block %d.", store.Hex(), deployReceipt.BlockNumber)
+	if err := c.nodes.checkCode(ctx, store); err != nil {
+		return err
+	}
+
+	expected := make(map[int64]int64, len(storeValues)+fillCount)
+	for key, value := range storeValues {
+		data, err := storeContract.abi.Pack("set", big.NewInt(key), big.NewInt(value))
+		if err != nil {
+			return fmt.Errorf("failed to encode set: %w", err)
+		}
+		receipt, err := c.nodes.issue(ctx, deployer, &store, data)
+		if err != nil {
+			return fmt.Errorf("set failed: %w", err)
+		}
+		if len(receipt.Logs) != 1 || receipt.Logs[0].Topics[1] != common.BigToHash(big.NewInt(key)) {
+			return fmt.Errorf("expected set of slot %d to emit a ValueSet event for it", key)
+		}
+		expected[key] = value
+	}
+	data, err := storeContract.abi.Pack("fill", big.NewInt(fillStart), big.NewInt(fillCount), big.NewInt(fillSeed))
+	if err != nil {
+		return fmt.Errorf("failed to encode fill: %w", err)
+	}
+	fillReceipt, err := c.nodes.issue(ctx, deployer, &store, data)
+	if err != nil {
+		return fmt.Errorf("fill failed: %w", err)
+	}
+	logrus.Infof("Filled %d slots of the store using %d gas.", fillCount, fillReceipt.GasUsed)
+	for i := int64(0); i < fillCount; i++ {
+		expected[fillStart+i] = fillSeed + i
+	}
+	if err := c.nodes.awaitOnAllNodes(ctx, fillReceipt.TxHash); err != nil {
+		return err
+	}
+
+	for key, value := range expected {
+		if err := c.checkStorage(ctx, store, common.BigToHash(big.NewInt(key)), common.BigToHash(big.NewInt(value))); err != nil {
+			return err
+		}
+	}
+	for key, value := range storeValues {
+		if err := c.checkView(ctx, storeContract, store, "get", []interface{}{big.NewInt(key)}, big.NewInt(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkView checks that calling view [method] of [callee] at [address] returns [expected] on every node
+func (c *contractWorkflowTest) checkView(ctx context.Context, callee contract, address common.Address, method string, args []interface{}, expected *big.Int) error {
+	input, err := callee.abi.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", method, err)
+	}
+	for i, client := range c.nodes.clients {
+		output, err := client.CallContract(ctx, coreth.CallMsg{To: &address, Data: input}, nil)
+		if err != nil {
+			return fmt.Errorf("failed to call %s on node %d: %w", method, i, err)
+		}
+		outputs, err := callee.unpack(method, output)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s from node %d: %w", method, i, err)
+		}
+		if actual := outputs[0].(*big.Int); actual.Cmp(expected) != 0 {
+			return fmt.Errorf("%s%v returned %s on node %d, expected %s", method, args, actual, i, expected)
+		}
+	}
+	return nil
+}
+
+// checkStorage checks that slot [slot] of [address] holds [expected] on every node
+func (c *contractWorkflowTest) checkStorage(ctx context.Context, address common.Address, slot common.Hash, expected common.Hash) error {
+	for i, client := range c.nodes.clients {
+		value, err := client.StorageAt(ctx, address, slot, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get slot %s of %s from node %d: %w", slot.Hex(), address.Hex(), i, err)
+		}
+		if actual := common.BytesToHash(value); actual != expected {
+			return fmt.Errorf("slot %s of %s is %s on node %d, expected %s", slot.Hex(), address.Hex(), actual.Hex(), i, expected.Hex())
+		}
+	}
+	return nil
+}
+
+// checkFilteredEvents checks that every node returns [expected] for [query]
+func (c *contractWorkflowTest) checkFilteredEvents(ctx context.Context, query coreth.FilterQuery, expected []tokenEvent) error {
+	for i, client := range c.nodes.clients {
+		logs, err := client.FilterLogs(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to filter logs on node %d: %w", i, err)
+		}
+		events, err := decodeTokenLogs(logs)
+		if err != nil {
+			return err
+		}
+		if err := compareEvents(events, expected); err != nil {
+			return fmt.Errorf("unexpected filtered events on node %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// compareEvents returns an error if [actual] isn't [expected], in order
+func compareEvents(actual []tokenEvent, expected []tokenEvent) error {
+	if len(actual) != len(expected) {
+		return fmt.Errorf("found %d events %v, expected %d events %v", len(actual), actual, len(expected), expected)
+	}
+	for i := range actual {
+		if actual[i].Name != expected[i].Name || actual[i].From != expected[i].From || actual[i].To != expected[i].To || actual[i].Value.Cmp(expected[i].Value) != 0 {
+			return fmt.Errorf("event %d is %s, expected %s", i, actual[i], expected[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package cchain
+
+import (
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/testsuite"
+
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// ContractTest deploys and calls an ERC-20 token and a storage heavy contract through one boot node, and checks that
+// every boot node returns the same results, events and storage
+type ContractTest struct {
+	ImageName string
+}
+
+// Run implements the Kurtosis Test interface
+func (test ContractTest) Run(network networks.Network, context testsuite.TestContext) {
+	clients, err := bootNodeClients(network)
+	if err != nil {
+		context.Fatal(err)
+	}
+	defer closeClients(clients)
+
+	logrus.Infof("Executing C-Chain contract test...")
+	if err := NewContractWorkflowTest(clients).ExecuteTest(); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "C-Chain contract test failed."))
+	}
+	logrus.Infof("C-Chain contract test completed successfully.")
+}
+
+// GetNetworkLoader implements the Kurtosis Test interface
+func (test ContractTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	return newBootNodeNetworkLoader(test.ImageName)
+}
+
+// GetExecutionTimeout implements the Kurtosis Test interface
+func (test ContractTest) GetExecutionTimeout() time.Duration {
+	return 5 * time.Minute
+}
+
+// GetSetupBuffer implements the Kurtosis Test interface
+func (test ContractTest) GetSetupBuffer() time.Duration {
+	return 2 * time.Minute
+}
+
+// bootNodeClients returns the clients of the boot nodes of [network]
+func bootNodeClients(network networks.Network) ([]*avalancheService.Client, error) {
+	castedNetwork := network.(avalancheNetwork.TestAvalancheNetwork)
+	clientsByID, err := castedNetwork.GetAvalancheClients(castedNetwork.GetAllBootServiceIDs())
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get clients of the boot nodes.")
+	}
+	clients := make([]*avalancheService.Client, 0, len(clientsByID))
+	for _, client := range clientsByID {
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+func closeClients(clients []*avalancheService.Client) {
+	for _, client := range clients {
+		client.Close()
+	}
+}
+
+// newBootNodeNetworkLoader returns a loader of a network of only boot nodes, whose C Chain tests issue transactions to
+// one of them and check the results on all of them
+func newBootNodeNetworkLoader(imageName string) (networks.NetworkLoader, error) {
+	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
+		true,
+		imageName,
+		avalancheService.DEBUG,
+		2,
+		2,
+		DefaultParams().TxFee,
+		2*time.Second,
+		false,
+		make(map[networks.ConfigurationID]avalancheNetwork.TestAvalancheNetworkServiceConfig),
+		make(map[networks.ServiceID]networks.ConfigurationID),
+	)
+}
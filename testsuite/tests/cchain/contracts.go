@@ -0,0 +1,100 @@
+package cchain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+//go:generate ./contracts/compile.sh
+
+// The contracts deployed by the contract test, whose Solidity sources are in contracts/. Their bytecode is in
+// contracts_bytecode.go, which contracts/compile.sh regenerates, and contracts_test.go runs it in an in-process EVM.
+
+// tokenABI is the ABI of an ERC-20 token, whose constructor mints the initial supply to the deployer:
+//
+//	constructor(uint256 initialSupply)
+//	function totalSupply() external view returns (uint256)
+//	function balanceOf(address owner) external view returns (uint256)
+//	function allowance(address owner, address spender) external view returns (uint256)
+//	function transfer(address to, uint256 value) external returns (bool)
+//	function approve(address spender, uint256 value) external returns (bool)
+//	function transferFrom(address from, address to, uint256 value) external returns (bool)
+//	event Transfer(address indexed from, address indexed to, uint256 value)
+//	event Approval(address indexed owner, address indexed spender, uint256 value)
+//
+// The total supply is in slot 0, and the balances and allowances are mappings at slots 1 and 2. Transfers exceeding the
+// sender's balance or allowance revert.
+const tokenABI = `[
+	{"type":"constructor","inputs":[{"name":"initialSupply","type":"uint256"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"totalSupply","inputs":[],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"allowance","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"transferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Approval","anonymous":false,"inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+// storeABI is the ABI of a contract that writes many storage slots at once:
+//
+//	function set(uint256 key, uint256 value) external
+//	function get(uint256 key) external view returns (uint256)
+//	function fill(uint256 start, uint256 count, uint256 seed) external
+//	event ValueSet(uint256 indexed key, uint256 value)
+//	event Filled(uint256 start, uint256 count)
+//
+// set and get write and read slot [key], and fill sets slot [start]+i to [seed]+i for every i < [count].
+const storeABI = `[
+	{"type":"function","name":"set","inputs":[{"name":"key","type":"uint256"},{"name":"value","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"get","inputs":[{"name":"key","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"fill","inputs":[{"name":"start","type":"uint256"},{"name":"count","type":"uint256"},{"name":"seed","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"event","name":"ValueSet","anonymous":false,"inputs":[{"name":"key","type":"uint256","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Filled","anonymous":false,"inputs":[{"name":"start","type":"uint256","indexed":false},{"name":"count","type":"uint256","indexed":false}]}
+]`
+
+// contract is the ABI and creation code of a contract
+type contract struct {
+	abi      abi.ABI
+	bytecode []byte
+}
+
+var (
+	tokenContract = mustParseContract(tokenABI, tokenBytecode)
+	storeContract = mustParseContract(storeABI, storeBytecode)
+)
+
+func mustParseContract(abiJSON string, bytecodeHex string) contract {
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	bytecode, err := hex.DecodeString(bytecodeHex)
+	if err != nil {
+		panic(err)
+	}
+	return contract{abi: parsedABI, bytecode: bytecode}
+}
+
+// deployData returns the data of a transaction deploying [c] with constructor arguments [args]
+func (c contract) deployData(args ...interface{}) ([]byte, error) {
+	encodedArgs, err := c.abi.Pack("", args...)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, c.bytecode...), encodedArgs...), nil
+}
+
+// unpack decodes the outputs of method [name] of [c], or the non-indexed inputs of its event [name], from [data]
+func (c contract) unpack(name string, data []byte) ([]interface{}, error) {
+	if method, found := c.abi.Methods[name]; found {
+		return method.Outputs.UnpackValues(data)
+	}
+	if event, found := c.abi.Events[name]; found {
+		return event.Inputs.NonIndexed().UnpackValues(data)
+	}
+	return nil, fmt.Errorf("contract has no method or event %s", name)
+}
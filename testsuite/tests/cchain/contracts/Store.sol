@@ -0,0 +1,33 @@
+pragma solidity 0.6.12;
+
+// Store writes many storage slots at once, addressing them directly so that the contract test knows which slots to
+// read with eth_getStorageAt.
+contract Store {
+    event ValueSet(uint256 indexed key, uint256 value);
+    event Filled(uint256 start, uint256 count);
+
+    // set writes [value] to slot [key]
+    function set(uint256 key, uint256 value) external {
+        assembly {
+            sstore(key, value)
+        }
+        emit ValueSet(key, value);
+    }
+
+    // get reads slot [key]
+    function get(uint256 key) external view returns (uint256 value) {
+        assembly {
+            value := sload(key)
+        }
+    }
+
+    // fill sets slot [start]+i to [seed]+i for every i < [count]
+    function fill(uint256 start, uint256 count, uint256 seed) external {
+        for (uint256 i = 0; i < count; i++) {
+            assembly {
+                sstore(add(start, i), add(seed, i))
+            }
+        }
+        emit Filled(start, count);
+    }
+}
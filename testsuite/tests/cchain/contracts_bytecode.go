@@ -0,0 +1,32 @@
+// The creation code of the contracts in contracts/. contracts/compile.sh overwrites this file with the output of solc
+// 0.6.12. The code below was assembled by hand to behave like the sources, with the same storage layout, since solc
+// wasn't available when they were added; rerun contracts/compile.sh to replace it with the compiler's output.
+
+package cchain
+
+// tokenBytecode is the creation code of contracts/Token.sol, which must be followed by the ABI encoded initial supply
+const tokenBytecode = "602061036e60003960005180600055803360005260016020526040600020556000523360007fddf252ad1be2c89b69c2b068" +
+	"fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a3610316806100586000396000f3346103115760003560e01c80" +
+	"6318160ddd1461005157806370a082311461005e578063a9059cbb1461008f578063dd62ed3e14610129578063095ea7b314" +
+	"61017e57806323b872dd1461020757610311565b5060005460005260206000f35b5060043573ffffffffffffffffffffffff" +
+	"ffffffffffffffff16600052600160205260406000205460005260206000f35b506024353360005260016020526040600020" +
+	"805482811061031157829003905560043573ffffffffffffffffffffffffffffffffffffffff166000526001602052604060" +
+	"002080548201905560005260043573ffffffffffffffffffffffffffffffffffffffff16337fddf252ad1be2c89b69c2b068" +
+	"fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a3600160005260206000f35b5060243573ffffffffffffffffff" +
+	"ffffffffffffffffffffff1660043573ffffffffffffffffffffffffffffffffffffffff1660005260026020526040600020" +
+	"60205260005260406000205460005260206000f35b5060043573ffffffffffffffffffffffffffffffffffffffff16336000" +
+	"52600260205260406000206020526000526040600020602435905560243560005260043573ffffffffffffffffffffffffff" +
+	"ffffffffffffff16337f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b92560206000a3600160" +
+	"005260206000f35b506044353360043573ffffffffffffffffffffffffffffffffffffffff16600052600260205260406000" +
+	"206020526000526040600020805482811061031157829003905560043573ffffffffffffffffffffffffffffffffffffffff" +
+	"1660005260016020526040600020805482811061031157829003905560243573ffffffffffffffffffffffffffffffffffff" +
+	"ffff166000526001602052604060002080548201905560005260243573ffffffffffffffffffffffffffffffffffffffff16" +
+	"60043573ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a116" +
+	"28f55a4df523b3ef60206000a3600160005260206000f35b600080fd"
+
+// storeBytecode is the creation code of contracts/Store.sol
+const storeBytecode = "6100d28061000d6000396000f3346100cd5760003560e01c80631ab06ee5146100305780639507d39a146100695780636fe4" +
+	"a44814610077576100cd565b50602435600435556024356000526004357f69be06033bef8d755e18606a27d6d07393aabbd1" +
+	"800776e503af2c8a03b7c68160206000a2005b506004355460005260206000f35b5060005b60243581101561009857806044" +
+	"350181600435015560010161007b565b506004356000526024356020527fc795ee7b5cc1dcc829f113a444452a1f8381a301" +
+	"767486bce0a4f61627bf7d8660406000a1005b600080fd"
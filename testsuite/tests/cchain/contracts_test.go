@@ -0,0 +1,128 @@
+package cchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// localEVM runs the contracts in an in-process EVM, so that their bytecode can be checked against the sources without a network
+type localEVM struct {
+	t     *testing.T
+	state *state.StateDB
+}
+
+func newLocalEVM(t *testing.T) *localEVM {
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	assert.NoError(t, err)
+	return &localEVM{t: t, state: stateDB}
+}
+
+func (e *localEVM) deploy(from common.Address, c contract, args ...interface{}) common.Address {
+	data, err := c.deployData(args...)
+	assert.NoError(e.t, err)
+	_, address, _, err := runtime.Create(data, &runtime.Config{Origin: from, State: e.state})
+	assert.NoError(e.t, err)
+	return address
+}
+
+// call calls [method] of [c] at [address], returning its outputs, or an error if it reverted
+func (e *localEVM) call(from common.Address, c contract, address common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := c.abi.Pack(method, args...)
+	assert.NoError(e.t, err)
+	output, _, err := runtime.Call(address, input, &runtime.Config{Origin: from, State: e.state})
+	if err != nil {
+		return nil, err
+	}
+	return c.unpack(method, output)
+}
+
+func (e *localEVM) callUint(c contract, address common.Address, method string, args ...interface{}) *big.Int {
+	outputs, err := e.call(common.Address{}, c, address, method, args...)
+	assert.NoError(e.t, err)
+	return outputs[0].(*big.Int)
+}
+
+func TestTokenContract(t *testing.T) {
+	e := newLocalEVM(t)
+	owner := common.HexToAddress("0x1000")
+	spender := common.HexToAddress("0x2000")
+	recipient := common.HexToAddress("0x3000")
+	token := e.deploy(owner, tokenContract, big.NewInt(1000))
+
+	assert.Equal(t, big.NewInt(1000), e.callUint(tokenContract, token, "totalSupply"))
+	assert.Equal(t, big.NewInt(1000), e.callUint(tokenContract, token, "balanceOf", owner))
+	// Solidity's layout, so that the balances can be read with eth_getStorageAt
+	assert.Equal(t, common.BigToHash(big.NewInt(1000)), e.state.GetState(token, tokenBalanceSlot(owner)))
+
+	outputs, err := e.call(owner, tokenContract, token, "transfer", recipient, big.NewInt(300))
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{true}, outputs)
+	_, err = e.call(recipient, tokenContract, token, "transfer", owner, big.NewInt(301))
+	assert.Error(t, err, "Expected a transfer exceeding the balance to revert")
+
+	_, err = e.call(owner, tokenContract, token, "approve", spender, big.NewInt(200))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(200), e.callUint(tokenContract, token, "allowance", owner, spender))
+	_, err = e.call(spender, tokenContract, token, "transferFrom", owner, recipient, big.NewInt(201))
+	assert.Error(t, err, "Expected a transfer exceeding the allowance to revert")
+	_, err = e.call(spender, tokenContract, token, "transferFrom", owner, recipient, big.NewInt(150))
+	assert.NoError(t, err)
+
+	assert.Equal(t, big.NewInt(550), e.callUint(tokenContract, token, "balanceOf", owner))
+	assert.Equal(t, big.NewInt(450), e.callUint(tokenContract, token, "balanceOf", recipient))
+	assert.Equal(t, big.NewInt(50), e.callUint(tokenContract, token, "allowance", owner, spender))
+
+	events := make([]tokenEvent, 0, 4)
+	for _, log := range e.state.Logs() {
+		event, err := decodeTokenEvent(log.Topics, log.Data)
+		assert.NoError(t, err)
+		events = append(events, event)
+	}
+	assert.ElementsMatch(t, []tokenEvent{
+		{Name: "Transfer", From: common.Address{}, To: owner, Value: big.NewInt(1000)},
+		{Name: "Transfer", From: owner, To: recipient, Value: big.NewInt(300)},
+		{Name: "Approval", From: owner, To: spender, Value: big.NewInt(200)},
+		{Name: "Transfer", From: owner, To: recipient, Value: big.NewInt(150)},
+	}, events)
+}
+
+func TestStoreContract(t *testing.T) {
+	e := newLocalEVM(t)
+	store := e.deploy(common.HexToAddress("0x1000"), storeContract)
+
+	_, err := e.call(common.Address{}, storeContract, store, "set", big.NewInt(5), big.NewInt(42))
+	assert.NoError(t, err)
+	_, err = e.call(common.Address{}, storeContract, store, "fill", big.NewInt(100), big.NewInt(3), big.NewInt(7))
+	assert.NoError(t, err)
+
+	assert.Equal(t, big.NewInt(42), e.callUint(storeContract, store, "get", big.NewInt(5)))
+	for i := int64(0); i < 3; i++ {
+		assert.Equal(t, common.BigToHash(big.NewInt(7+i)), e.state.GetState(store, common.BigToHash(big.NewInt(100+i))))
+	}
+	assert.Equal(t, common.Hash{}, e.state.GetState(store, common.BigToHash(big.NewInt(103))))
+
+	logs := e.state.Logs()
+	assert.Len(t, logs, 2)
+	for _, log := range logs {
+		switch log.Topics[0] {
+		case storeContract.abi.Events["ValueSet"].ID:
+			assert.Equal(t, common.BigToHash(big.NewInt(5)), log.Topics[1])
+		case storeContract.abi.Events["Filled"].ID:
+			outputs, err := storeContract.unpack("Filled", log.Data)
+			assert.NoError(t, err)
+			assert.Equal(t, []interface{}{big.NewInt(100), big.NewInt(3)}, outputs)
+		default:
+			t.Errorf("Unexpected log with topic %s", log.Topics[0].Hex())
+		}
+	}
+
+	_, _, err = runtime.Call(store, crypto.Keccak256([]byte("unknown()"))[:4], &runtime.Config{State: e.state})
+	assert.Error(t, err, "Expected an unknown method to revert")
+}
@@ -0,0 +1,169 @@
+package cchain
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
+	"github.com/ava-labs/coreth"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ava-labs/coreth/ethclient"
+	"github.com/ava-labs/coreth/params"
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// How long to wait for a transaction's receipt, on the node it was issued to or on any other node
+	receiptTimeout = 30 * time.Second
+
+	// Time between polls for a transaction's receipt
+	receiptPollInterval = 200 * time.Millisecond
+)
+
+// Gas price of the transactions the tests issue, which is the minimum the C Chain accepts
+var gasPrice = big.NewInt(470 * params.GWei)
+
+// ethAccount is a C Chain account issuing transactions, whose nonce is tracked locally since it's the only issuer
+type ethAccount struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+	nonce   uint64
+}
+
+func newEthAccount() (*ethAccount, error) {
+	key, err := ethcrypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return &ethAccount{key: key, address: ethcrypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+// fundEthAccounts sends avaxAmount to each of [accounts] on the C Chain, from the genesis funds on the X Chain of the
+// node of [client]
+func fundEthAccounts(client *services.Client, accounts ...*ethAccount) error {
+	addresses := make([]common.Address, 0, len(accounts))
+	for _, account := range accounts {
+		addresses = append(addresses, account.address)
+	}
+	workflowRunner := helpers.NewRPCWorkFlowRunner(client, user, requestTimeout)
+	if err := workflowRunner.FundCChainAddresses(addresses, avaxAmount); err != nil {
+		return fmt.Errorf("failed to fund C Chain accounts: %w", err)
+	}
+	return nil
+}
+
+// ethNodes is the C Chain websocket clients of a set of nodes, of which the first is issued transactions
+type ethNodes struct {
+	clients []*ethclient.Client
+}
+
+// newEthNodes connects to the C Chain websocket of every node in [clients]
+func newEthNodes(clients []*services.Client) (ethNodes, error) {
+	ethClients := make([]*ethclient.Client, 0, len(clients))
+	for _, client := range clients {
+		ethClient, err := client.CChainEthAPI()
+		if err != nil {
+			return ethNodes{}, fmt.Errorf("failed to connect to the C Chain websocket: %w", err)
+		}
+		ethClients = append(ethClients, ethClient)
+	}
+	return ethNodes{clients: ethClients}, nil
+}
+
+// send signs and sends a transaction from [from] to [to], or deploying a contract if [to] is nil, with [gasLimit]
+func (nodes ethNodes) send(ctx context.Context, from *ethAccount, to *common.Address, data []byte, gasLimit uint64) (*types.Transaction, error) {
+	var tx *types.Transaction
+	if to == nil {
+		tx = types.NewContractCreation(from.nonce, big.NewInt(0), gasLimit, gasPrice, data)
+	} else {
+		tx = types.NewTransaction(from.nonce, *to, big.NewInt(0), gasLimit, gasPrice, data)
+	}
+	signedTx, err := types.SignTx(tx, signer, from.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := nodes.clients[0].SendTransaction(ctx, signedTx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	from.nonce++
+	return signedTx, nil
+}
+
+// issue estimates the gas of a transaction from [from] to [to], sends it, and waits for it to succeed
+func (nodes ethNodes) issue(ctx context.Context, from *ethAccount, to *common.Address, data []byte) (*types.Receipt, error) {
+	gas, err := nodes.clients[0].EstimateGas(ctx, coreth.CallMsg{From: from.address, To: to, GasPrice: gasPrice, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	tx, err := nodes.send(ctx, from, to, data, gas)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := nodes.awaitReceipt(ctx, nodes.clients[0], tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction %s failed using %d of its %d gas", tx.Hash().Hex(), receipt.GasUsed, gas)
+	}
+	return receipt, nil
+}
+
+// awaitReceipt polls [client] for the receipt of the transaction with hash [txHash] until it's found
+func (nodes ethNodes) awaitReceipt(ctx context.Context, client *ethclient.Client, txHash common.Hash) (*types.Receipt, error) {
+	deadline := time.Now().Add(receiptTimeout)
+	for {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, coreth.NotFound) {
+			return nil, fmt.Errorf("failed to get receipt of %s: %w", txHash.Hex(), err)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("no receipt of %s within %v", txHash.Hex(), receiptTimeout)
+		}
+		time.Sleep(receiptPollInterval)
+	}
+}
+
+// awaitOnAllNodes waits until every node has the receipt of the transaction with hash [txHash], so that they all
+// have the state it was included in
+func (nodes ethNodes) awaitOnAllNodes(ctx context.Context, txHash common.Hash) error {
+	for i, client := range nodes.clients {
+		if _, err := nodes.awaitReceipt(ctx, client, txHash); err != nil {
+			return fmt.Errorf("node %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// checkCode checks that every node has the same, non-empty code at [address]
+func (nodes ethNodes) checkCode(ctx context.Context, address common.Address) error {
+	var expected []byte
+	for i, client := range nodes.clients {
+		var code []byte
+		for deadline := time.Now().Add(receiptTimeout); len(code) == 0; time.Sleep(receiptPollInterval) {
+			var err error
+			if code, err = client.CodeAt(ctx, address, nil); err != nil {
+				return fmt.Errorf("failed to get code of %s from node %d: %w", address.Hex(), i, err)
+			}
+			if len(code) == 0 && time.Now().After(deadline) {
+				return fmt.Errorf("node %d has no code at %s", i, address.Hex())
+			}
+		}
+		if i == 0 {
+			expected = code
+		} else if !bytes.Equal(code, expected) {
+			return fmt.Errorf("node %d has different code at %s than node 0", i, address.Hex())
+		}
+	}
+	return nil
+}
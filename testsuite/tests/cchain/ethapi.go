@@ -6,8 +6,10 @@ import (
 
 	"math/big"
 
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
 	"github.com/ava-labs/avalanche-testing/testsuite/tester"
 	"github.com/ava-labs/coreth/ethclient"
+	ethrpc "github.com/ava-labs/coreth/rpc"
 	"github.com/sirupsen/logrus"
 
 	"github.com/ava-labs/coreth"
@@ -15,27 +17,49 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-// NewEthAPIExecutor returns a new bombard test bombardExecutor
-func NewEthAPIExecutor(client *ethclient.Client) tester.AvalancheTester {
+// NewEthAPIExecutor returns a test of the C Chain's Ethereum JSON-RPC API, which issues transactions to the first of
+// [clients] and checks that every node returns the same result to each request
+func NewEthAPIExecutor(clients []*services.Client) tester.AvalancheTester {
 	return &ethAPIExecutor{
-		client: client,
+		clients: clients,
 	}
 }
 
 type ethAPIExecutor struct {
-	client *ethclient.Client
+	clients []*services.Client
+	nodes   ethNodes
+
+	// The JSON-RPC clients of the nodes, for the methods the ethclient lacks and for batches
+	rpcClients []*ethrpc.Client
 }
 
 // ExecuteTest implements the AvalancheTester interface
 func (e *ethAPIExecutor) ExecuteTest() error {
 	ctx := context.Background()
+	nodes, err := newEthNodes(e.clients)
+	if err != nil {
+		return err
+	}
+	e.nodes = nodes
+	for _, client := range e.clients {
+		rpcClient, err := client.CChainEthRPC()
+		if err != nil {
+			return err
+		}
+		e.rpcClients = append(e.rpcClients, rpcClient)
+	}
 
 	logrus.Info("Conducting test on basic ethclient API calls")
-	if err := testBasicAPICalls(ctx, e.client, ethAddr); err != nil {
+	if err := testBasicAPICalls(ctx, e.nodes.clients[0], ethAddr); err != nil {
 		return fmt.Errorf("Basic API Calls failed: %w", err)
 	}
 	logrus.Info("Basic API Call test was successful.")
 
+	logrus.Info("Conducting JSON-RPC conformance tests")
+	if err := e.testConformance(ctx); err != nil {
+		return fmt.Errorf("JSON-RPC conformance tests failed: %w", err)
+	}
+	logrus.Info("JSON-RPC conformance tests were successful.")
 	return nil
 }
 
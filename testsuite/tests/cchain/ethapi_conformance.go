@@ -0,0 +1,578 @@
+package cchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/coreth/core/types"
+	ethrpc "github.com/ava-labs/coreth/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/sirupsen/logrus"
+)
+
+// conformanceState is the chain state the conformance cases query, set up by issuing transactions through the first
+// node
+type conformanceState struct {
+	sender    *ethAccount
+	recipient common.Address
+	token     common.Address
+
+	// Receipts of the token's deployment, a transfer and an approval to the recipient, in the order they were issued
+	receipts []*types.Receipt
+
+	// The events of the receipts, in order
+	events []tokenEvent
+}
+
+// block returns the number of the block receipt [i] was included in, as a JSON-RPC block number
+func (state *conformanceState) block(i int) string {
+	return hexutil.EncodeBig(state.receipts[i].BlockNumber)
+}
+
+// lastBlock returns the number of the block the last receipt was included in, which every node has
+func (state *conformanceState) lastBlock() string {
+	return state.block(len(state.receipts) - 1)
+}
+
+// testConformance sets up the chain state and runs every conformance case against it
+func (e *ethAPIExecutor) testConformance(ctx context.Context) error {
+	state, err := e.setUpConformance(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to set up conformance state: %w", err)
+	}
+	cases := []struct {
+		name string
+		run  func(context.Context, *conformanceState) error
+	}{
+		{"chain ID", e.testChainID},
+		{"code and storage", e.testCodeAndStorage},
+		{"eth_call", e.testCall},
+		{"eth_estimateGas", e.testEstimateGas},
+		{"eth_getLogs", e.testGetLogs},
+		{"eth_getTransactionReceipt", e.testReceipts},
+		{"pending nonce", e.testPendingNonce},
+		{"filters", e.testFilters},
+		{"batch", e.testBatch},
+	}
+	for _, c := range cases {
+		if err := c.run(ctx, state); err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+		logrus.Infof("JSON-RPC conformance case %s passed.", c.name)
+	}
+	return nil
+}
+
+// setUpConformance funds a sender, which deploys the token, transfers to a recipient and approves it as a spender
+func (e *ethAPIExecutor) setUpConformance(ctx context.Context) (*conformanceState, error) {
+	sender, err := newEthAccount()
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := newEthAccount()
+	if err != nil {
+		return nil, err
+	}
+	if err := fundEthAccounts(e.clients[0], sender); err != nil {
+		return nil, err
+	}
+	state := &conformanceState{sender: sender, recipient: recipient.address}
+
+	deployData, err := tokenContract.deployData(big.NewInt(tokenSupply))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode deployment: %w", err)
+	}
+	deployReceipt, err := e.nodes.issue(ctx, sender, nil, deployData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deploy token: %w", err)
+	}
+	state.token = deployReceipt.ContractAddress
+	state.receipts = append(state.receipts, deployReceipt)
+	for _, method := range []string{"transfer", "approve"} {
+		value := big.NewInt(300)
+		if method == "approve" {
+			value = big.NewInt(200)
+		}
+		data, err := tokenContract.abi.Pack(method, state.recipient, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", method, err)
+		}
+		receipt, err := e.nodes.issue(ctx, sender, &state.token, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s failed: %w", method, err)
+		}
+		state.receipts = append(state.receipts, receipt)
+	}
+	if err := e.nodes.awaitOnAllNodes(ctx, state.receipts[len(state.receipts)-1].TxHash); err != nil {
+		return nil, err
+	}
+	state.events = []tokenEvent{
+		{Name: "Transfer", From: common.Address{}, To: sender.address, Value: big.NewInt(tokenSupply)},
+		{Name: "Transfer", From: sender.address, To: state.recipient, Value: big.NewInt(300)},
+		{Name: "Approval", From: sender.address, To: state.recipient, Value: big.NewInt(200)},
+	}
+	logrus.Infof("Deployed token at %s for the conformance cases.", state.token.Hex())
+	return state, nil
+}
+
+// callAll calls [method] with [args] on every node, and returns the result, which must be identical on all of them
+func (e *ethAPIExecutor) callAll(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error) {
+	var expected json.RawMessage
+	for i, client := range e.rpcClients {
+		var result json.RawMessage
+		if err := client.CallContext(ctx, &result, method, args...); err != nil {
+			return nil, fmt.Errorf("%s failed on node %d: %w", method, i, err)
+		}
+		if i == 0 {
+			expected = result
+		} else if !bytes.Equal(result, expected) {
+			return nil, fmt.Errorf("%s returned %s on node %d, but %s on node 0", method, result, i, expected)
+		}
+	}
+	return expected, nil
+}
+
+// callAllInto calls [method] on every node like callAll, and decodes the result into [result]
+func (e *ethAPIExecutor) callAllInto(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	raw, err := e.callAll(ctx, method, args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return fmt.Errorf("failed to decode result %s of %s: %w", raw, method, err)
+	}
+	return nil
+}
+
+// failAll calls [method] with [args] on every node, and returns an error unless all of them fail with the same error
+func (e *ethAPIExecutor) failAll(ctx context.Context, method string, args ...interface{}) error {
+	var expected string
+	for i, client := range e.rpcClients {
+		var result json.RawMessage
+		err := client.CallContext(ctx, &result, method, args...)
+		if err == nil {
+			return fmt.Errorf("expected %s to fail on node %d, but it returned %s", method, i, result)
+		}
+		if i == 0 {
+			expected = err.Error()
+		} else if err.Error() != expected {
+			return fmt.Errorf("%s failed with %q on node %d, but with %q on node 0", method, err, i, expected)
+		}
+	}
+	return nil
+}
+
+// callArgs returns the arguments of eth_call and eth_estimateGas for a call from [from] to [to]
+func callArgs(from common.Address, to common.Address, data []byte) map[string]interface{} {
+	return map[string]interface{}{
+		"from": from,
+		"to":   to,
+		"data": hexutil.Bytes(data),
+	}
+}
+
+// testChainID checks that eth_chainId returns the C Chain's ID and net_version a network ID
+func (e *ethAPIExecutor) testChainID(ctx context.Context, state *conformanceState) error {
+	var chainID hexutil.Big
+	if err := e.callAllInto(ctx, &chainID, "eth_chainId"); err != nil {
+		return err
+	}
+	if chainID.ToInt().Cmp(cChainID) != 0 {
+		return fmt.Errorf("eth_chainId returned %s, expected %s", chainID.ToInt(), cChainID)
+	}
+	var networkID string
+	if err := e.callAllInto(ctx, &networkID, "net_version"); err != nil {
+		return err
+	}
+	if _, err := strconv.ParseUint(networkID, 10, 64); err != nil {
+		return fmt.Errorf("net_version returned %q, which isn't a decimal network ID", networkID)
+	}
+	return nil
+}
+
+// testCodeAndStorage checks eth_getCode and eth_getStorageAt against the token's bytecode and storage layout
+func (e *ethAPIExecutor) testCodeAndStorage(ctx context.Context, state *conformanceState) error {
+	var code hexutil.Bytes
+	if err := e.callAllInto(ctx, &code, "eth_getCode", state.token, state.lastBlock()); err != nil {
+		return err
+	}
+	// The runtime code is returned by the creation code, which contains it
+	if len(code) == 0 || !bytes.Contains(tokenContract.bytecode, code) {
+		return fmt.Errorf("eth_getCode returned %d bytes that aren't the token's runtime code", len(code))
+	}
+	if err := e.callAllInto(ctx, &code, "eth_getCode", state.recipient, state.lastBlock()); err != nil {
+		return err
+	}
+	if len(code) != 0 {
+		return fmt.Errorf("eth_getCode returned %d bytes for an account without code", len(code))
+	}
+
+	slots := []struct {
+		slot     common.Hash
+		expected int64
+	}{
+		{common.Hash{}, tokenSupply},
+		{tokenBalanceSlot(state.recipient), 300},
+		{tokenAllowanceSlot(state.sender.address, state.recipient), 200},
+	}
+	for _, slot := range slots {
+		var value hexutil.Bytes
+		if err := e.callAllInto(ctx, &value, "eth_getStorageAt", state.token, slot.slot.Hex(), state.lastBlock()); err != nil {
+			return err
+		}
+		if actual, expected := common.BytesToHash(value), common.BigToHash(big.NewInt(slot.expected)); actual != expected {
+			return fmt.Errorf("eth_getStorageAt returned %s for slot %s, expected %s", actual.Hex(), slot.slot.Hex(), expected.Hex())
+		}
+	}
+	return nil
+}
+
+// testCall checks that eth_call returns the token's balances, and fails for a call that reverts
+func (e *ethAPIExecutor) testCall(ctx context.Context, state *conformanceState) error {
+	data, err := tokenContract.abi.Pack("balanceOf", state.recipient)
+	if err != nil {
+		return fmt.Errorf("failed to encode balanceOf: %w", err)
+	}
+	var output hexutil.Bytes
+	if err := e.callAllInto(ctx, &output, "eth_call", callArgs(state.recipient, state.token, data), state.lastBlock()); err != nil {
+		return err
+	}
+	outputs, err := tokenContract.unpack("balanceOf", output)
+	if err != nil {
+		return fmt.Errorf("failed to decode balanceOf: %w", err)
+	}
+	if balance := outputs[0].(*big.Int); balance.Cmp(big.NewInt(300)) != 0 {
+		return fmt.Errorf("eth_call of balanceOf returned %s, expected 300", balance)
+	}
+
+	data, err = tokenContract.abi.Pack("transfer", state.sender.address, big.NewInt(301))
+	if err != nil {
+		return fmt.Errorf("failed to encode transfer: %w", err)
+	}
+	return e.failAll(ctx, "eth_call", callArgs(state.recipient, state.token, data), state.lastBlock())
+}
+
+// testEstimateGas checks eth_estimateGas of a plain transfer, a token transfer, and a token transfer that reverts
+func (e *ethAPIExecutor) testEstimateGas(ctx context.Context, state *conformanceState) error {
+	var gas hexutil.Uint64
+	plainTransfer := map[string]interface{}{
+		"from":  state.sender.address,
+		"to":    state.recipient,
+		"value": (*hexutil.Big)(big.NewInt(1)),
+	}
+	if err := e.callAllInto(ctx, &gas, "eth_estimateGas", plainTransfer); err != nil {
+		return err
+	}
+	if gas != 21000 {
+		return fmt.Errorf("eth_estimateGas of a plain transfer returned %d, expected 21000", gas)
+	}
+
+	data, err := tokenContract.abi.Pack("transfer", state.recipient, big.NewInt(1))
+	if err != nil {
+		return fmt.Errorf("failed to encode transfer: %w", err)
+	}
+	if err := e.callAllInto(ctx, &gas, "eth_estimateGas", callArgs(state.sender.address, state.token, data)); err != nil {
+		return err
+	}
+	if gas <= 21000 {
+		return fmt.Errorf("eth_estimateGas of a token transfer returned %d, which doesn't cover its execution", gas)
+	}
+
+	data, err = tokenContract.abi.Pack("transfer", state.sender.address, big.NewInt(301))
+	if err != nil {
+		return fmt.Errorf("failed to encode transfer: %w", err)
+	}
+	return e.failAll(ctx, "eth_estimateGas", callArgs(state.recipient, state.token, data))
+}
+
+// testGetLogs checks eth_getLogs with block ranges, a block hash and topic filters
+func (e *ethAPIExecutor) testGetLogs(ctx context.Context, state *conformanceState) error {
+	transferTopic := tokenContract.abi.Events["Transfer"].ID
+	approvalTopic := tokenContract.abi.Events["Approval"].ID
+	senderTopic := common.BytesToHash(state.sender.address.Bytes())
+	recipientTopic := common.BytesToHash(state.recipient.Bytes())
+	queries := []struct {
+		name     string
+		filter   map[string]interface{}
+		expected []tokenEvent
+	}{
+		{
+			"all blocks",
+			map[string]interface{}{"fromBlock": state.block(0), "toBlock": state.lastBlock(), "address": state.token},
+			state.events,
+		},
+		{
+			"approval block",
+			map[string]interface{}{"fromBlock": state.block(2), "toBlock": state.block(2), "address": state.token},
+			state.events[2:],
+		},
+		{
+			"approval block hash",
+			map[string]interface{}{"blockHash": state.receipts[2].BlockHash, "address": state.token},
+			state.events[2:],
+		},
+		{
+			"transfers from sender",
+			map[string]interface{}{"fromBlock": state.block(0), "toBlock": state.lastBlock(), "topics": []interface{}{transferTopic, senderTopic}},
+			state.events[1:2],
+		},
+		{
+			"transfers or approvals",
+			map[string]interface{}{"fromBlock": state.block(0), "toBlock": state.lastBlock(), "topics": []interface{}{[]common.Hash{transferTopic, approvalTopic}}},
+			state.events,
+		},
+		{
+			"events to recipient",
+			map[string]interface{}{"fromBlock": state.block(0), "toBlock": state.lastBlock(), "topics": []interface{}{nil, nil, recipientTopic}},
+			state.events[1:],
+		},
+	}
+	for _, query := range queries {
+		var logs []types.Log
+		if err := e.callAllInto(ctx, &logs, "eth_getLogs", query.filter); err != nil {
+			return fmt.Errorf("%s: %w", query.name, err)
+		}
+		events, err := decodeTokenLogs(logs)
+		if err != nil {
+			return fmt.Errorf("%s: %w", query.name, err)
+		}
+		if err := compareEvents(events, query.expected); err != nil {
+			return fmt.Errorf("%s: %w", query.name, err)
+		}
+	}
+	return nil
+}
+
+// testReceipts checks that every node returns the same receipts, in the blocks eth_getBlockByNumber returns, and no
+// receipt of an unknown transaction
+func (e *ethAPIExecutor) testReceipts(ctx context.Context, state *conformanceState) error {
+	for i, expected := range state.receipts {
+		var receipt types.Receipt
+		if err := e.callAllInto(ctx, &receipt, "eth_getTransactionReceipt", expected.TxHash); err != nil {
+			return err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful || receipt.BlockHash != expected.BlockHash || receipt.ContractAddress != expected.ContractAddress {
+			return fmt.Errorf("receipt of %s differs from the one returned when it was issued", expected.TxHash.Hex())
+		}
+		var block struct {
+			Hash common.Hash `json:"hash"`
+		}
+		if err := e.callAllInto(ctx, &block, "eth_getBlockByNumber", state.block(i), false); err != nil {
+			return err
+		}
+		if block.Hash != receipt.BlockHash {
+			return fmt.Errorf("receipt of %s is in block %s, but block %s is %s", expected.TxHash.Hex(), receipt.BlockHash.Hex(), receipt.BlockNumber, block.Hash.Hex())
+		}
+	}
+	unknown, err := e.callAll(ctx, "eth_getTransactionReceipt", common.Hash{})
+	if err != nil {
+		return err
+	}
+	if string(unknown) != "null" {
+		return fmt.Errorf("eth_getTransactionReceipt of an unknown transaction returned %s", unknown)
+	}
+	return nil
+}
+
+// testPendingNonce checks that the pending nonce counts a transaction as soon as it's issued, and that every node
+// agrees once it's accepted
+func (e *ethAPIExecutor) testPendingNonce(ctx context.Context, state *conformanceState) error {
+	for _, block := range []string{"latest", "pending"} {
+		var nonce hexutil.Uint64
+		if err := e.callAllInto(ctx, &nonce, "eth_getTransactionCount", state.sender.address, block); err != nil {
+			return err
+		}
+		if uint64(nonce) != state.sender.nonce {
+			return fmt.Errorf("%s nonce is %d, expected %d", block, nonce, state.sender.nonce)
+		}
+	}
+
+	tx, err := e.nodes.send(ctx, state.sender, &state.recipient, nil, 21000)
+	if err != nil {
+		return err
+	}
+	var nonce hexutil.Uint64
+	if err := e.rpcClients[0].CallContext(ctx, &nonce, "eth_getTransactionCount", state.sender.address, "pending"); err != nil {
+		return fmt.Errorf("eth_getTransactionCount failed on node 0: %w", err)
+	}
+	if uint64(nonce) != state.sender.nonce {
+		return fmt.Errorf("pending nonce is %d after issuing a transaction, expected %d", nonce, state.sender.nonce)
+	}
+
+	if err := e.nodes.awaitOnAllNodes(ctx, tx.Hash()); err != nil {
+		return err
+	}
+	for _, block := range []string{"latest", "pending"} {
+		if err := e.callAllInto(ctx, &nonce, "eth_getTransactionCount", state.sender.address, block); err != nil {
+			return err
+		}
+		if uint64(nonce) != state.sender.nonce {
+			return fmt.Errorf("%s nonce is %d once the transaction is accepted, expected %d", block, nonce, state.sender.nonce)
+		}
+	}
+	return nil
+}
+
+// testFilters installs a log filter and a block filter on every node, issues a transfer, and checks that every
+// filter reports it
+func (e *ethAPIExecutor) testFilters(ctx context.Context, state *conformanceState) error {
+	logFilterIDs := make([]string, len(e.rpcClients))
+	blockFilterIDs := make([]string, len(e.rpcClients))
+	for i, client := range e.rpcClients {
+		filter := map[string]interface{}{"fromBlock": state.block(0), "address": state.token}
+		if err := client.CallContext(ctx, &logFilterIDs[i], "eth_newFilter", filter); err != nil {
+			return fmt.Errorf("eth_newFilter failed on node %d: %w", i, err)
+		}
+		if err := client.CallContext(ctx, &blockFilterIDs[i], "eth_newBlockFilter"); err != nil {
+			return fmt.Errorf("eth_newBlockFilter failed on node %d: %w", i, err)
+		}
+	}
+
+	data, err := tokenContract.abi.Pack("transfer", state.recipient, big.NewInt(100))
+	if err != nil {
+		return fmt.Errorf("failed to encode transfer: %w", err)
+	}
+	receipt, err := e.nodes.issue(ctx, state.sender, &state.token, data)
+	if err != nil {
+		return fmt.Errorf("transfer failed: %w", err)
+	}
+	if err := e.nodes.awaitOnAllNodes(ctx, receipt.TxHash); err != nil {
+		return err
+	}
+
+	var expectedLogs json.RawMessage
+	for i, client := range e.rpcClients {
+		// A log filter only reports the logs emitted after it was installed, regardless of its fromBlock
+		var changes []types.Log
+		err := pollFilterChanges(ctx, client, logFilterIDs[i], func(raw json.RawMessage) (bool, error) {
+			var logs []types.Log
+			if err := json.Unmarshal(raw, &logs); err != nil {
+				return false, fmt.Errorf("failed to decode logs: %w", err)
+			}
+			changes = append(changes, logs...)
+			return len(changes) > 0, nil
+		})
+		if err != nil {
+			return fmt.Errorf("log filter on node %d: %w", i, err)
+		}
+		if len(changes) != 1 || changes[0].TxHash != receipt.TxHash {
+			return fmt.Errorf("log filter on node %d reported %d logs, expected only the transfer's", i, len(changes))
+		}
+
+		err = pollFilterChanges(ctx, client, blockFilterIDs[i], func(raw json.RawMessage) (bool, error) {
+			var blockHashes []common.Hash
+			if err := json.Unmarshal(raw, &blockHashes); err != nil {
+				return false, fmt.Errorf("failed to decode block hashes: %w", err)
+			}
+			for _, hash := range blockHashes {
+				if hash == receipt.BlockHash {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			return fmt.Errorf("block filter on node %d: %w", i, err)
+		}
+
+		var logs json.RawMessage
+		if err := client.CallContext(ctx, &logs, "eth_getFilterLogs", logFilterIDs[i]); err != nil {
+			return fmt.Errorf("eth_getFilterLogs failed on node %d: %w", i, err)
+		}
+		if i == 0 {
+			var decoded []types.Log
+			if err := json.Unmarshal(logs, &decoded); err != nil {
+				return fmt.Errorf("failed to decode filter logs: %w", err)
+			}
+			if len(decoded) != len(state.events)+1 {
+				return fmt.Errorf("eth_getFilterLogs returned %d logs, expected %d", len(decoded), len(state.events)+1)
+			}
+			expectedLogs = logs
+		} else if !bytes.Equal(logs, expectedLogs) {
+			return fmt.Errorf("eth_getFilterLogs returned %s on node %d, but %s on node 0", logs, i, expectedLogs)
+		}
+
+		for _, filterID := range []string{logFilterIDs[i], blockFilterIDs[i]} {
+			for _, expected := range []bool{true, false} {
+				var uninstalled bool
+				if err := client.CallContext(ctx, &uninstalled, "eth_uninstallFilter", filterID); err != nil {
+					return fmt.Errorf("eth_uninstallFilter failed on node %d: %w", i, err)
+				}
+				if uninstalled != expected {
+					return fmt.Errorf("eth_uninstallFilter of filter %s on node %d returned %t, expected %t", filterID, i, uninstalled, expected)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pollFilterChanges polls [client] for the changes of filter [filterID], passing each batch of them to [handle], until
+// it returns true
+func pollFilterChanges(ctx context.Context, client *ethrpc.Client, filterID string, handle func(json.RawMessage) (bool, error)) error {
+	deadline := time.Now().Add(receiptTimeout)
+	for {
+		var changes json.RawMessage
+		if err := client.CallContext(ctx, &changes, "eth_getFilterChanges", filterID); err != nil {
+			return fmt.Errorf("eth_getFilterChanges failed: %w", err)
+		}
+		if done, err := handle(changes); err != nil || done {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("changes weren't reported within %v", receiptTimeout)
+		}
+		time.Sleep(receiptPollInterval)
+	}
+}
+
+// testBatch sends a batch of requests to every node over HTTP, and checks that each returns what the request returns
+// on its own, and that a request for an unknown method fails without failing the others
+func (e *ethAPIExecutor) testBatch(ctx context.Context, state *conformanceState) error {
+	requests := []struct {
+		method string
+		args   []interface{}
+	}{
+		{"eth_chainId", nil},
+		{"eth_getBalance", []interface{}{state.sender.address, state.lastBlock()}},
+		{"eth_getCode", []interface{}{state.token, state.lastBlock()}},
+		{"eth_getTransactionReceipt", []interface{}{state.receipts[1].TxHash}},
+	}
+	expected := make([]json.RawMessage, len(requests))
+	for j, request := range requests {
+		result, err := e.callAll(ctx, request.method, request.args...)
+		if err != nil {
+			return err
+		}
+		expected[j] = result
+	}
+
+	for i, client := range e.rpcClients {
+		batch := make([]ethrpc.BatchElem, 0, len(requests)+1)
+		for _, request := range requests {
+			batch = append(batch, ethrpc.BatchElem{Method: request.method, Args: request.args, Result: new(json.RawMessage)})
+		}
+		batch = append(batch, ethrpc.BatchElem{Method: "eth_unknownMethod", Result: new(json.RawMessage)})
+		if err := client.BatchCallContext(ctx, batch); err != nil {
+			return fmt.Errorf("batch failed on node %d: %w", i, err)
+		}
+		for j, request := range requests {
+			if batch[j].Error != nil {
+				return fmt.Errorf("%s failed in a batch on node %d: %w", request.method, i, batch[j].Error)
+			}
+			if result := *batch[j].Result.(*json.RawMessage); !bytes.Equal(result, expected[j]) {
+				return fmt.Errorf("%s returned %s in a batch on node %d, but %s on its own", request.method, result, i, expected[j])
+			}
+		}
+		if batch[len(requests)].Error == nil {
+			return fmt.Errorf("expected the unknown method to fail in a batch on node %d", i)
+		}
+	}
+	return nil
+}
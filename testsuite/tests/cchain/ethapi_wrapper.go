@@ -0,0 +1,47 @@
+package cchain
+
+import (
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/testsuite"
+
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// RPCConformanceTest checks the C Chain's Ethereum JSON-RPC API, issuing transactions through one boot node and
+// checking that every boot node returns the same result to each request
+type RPCConformanceTest struct {
+	ImageName string
+}
+
+// Run implements the Kurtosis Test interface
+func (test RPCConformanceTest) Run(network networks.Network, context testsuite.TestContext) {
+	clients, err := bootNodeClients(network)
+	if err != nil {
+		context.Fatal(err)
+	}
+	defer closeClients(clients)
+
+	logrus.Infof("Executing C-Chain JSON-RPC conformance test...")
+	if err := NewEthAPIExecutor(clients).ExecuteTest(); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "C-Chain JSON-RPC conformance test failed."))
+	}
+	logrus.Infof("C-Chain JSON-RPC conformance test completed successfully.")
+}
+
+// GetNetworkLoader implements the Kurtosis Test interface
+func (test RPCConformanceTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	return newBootNodeNetworkLoader(test.ImageName)
+}
+
+// GetExecutionTimeout implements the Kurtosis Test interface
+func (test RPCConformanceTest) GetExecutionTimeout() time.Duration {
+	return 5 * time.Minute
+}
+
+// GetSetupBuffer implements the Kurtosis Test interface
+func (test RPCConformanceTest) GetSetupBuffer() time.Duration {
+	return 2 * time.Minute
+}
@@ -1,7 +1,6 @@
 package cchain
 
 import (
-	"context"
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
@@ -14,8 +13,6 @@ import (
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/coreth/core/types"
-	"github.com/ava-labs/coreth/ethclient"
-	"github.com/ava-labs/coreth/params"
 	"github.com/ava-labs/coreth/plugin/evm"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/sirupsen/logrus"
@@ -69,7 +66,7 @@ func createConsecutiveBasicEthTransactions(pk *ecdsa.PrivateKey, addr common.Add
 	txs := make([]*types.Transaction, numTxs)
 	for i := 0; i < numTxs; i++ {
 		nonce := uint64(i) + startingNonce
-		tx := types.NewTransaction(nonce, addr, big.NewInt(1), 21000, big.NewInt(470*params.GWei), nil)
+		tx := types.NewTransaction(nonce, addr, big.NewInt(1), 21000, gasPrice, nil)
 		signedTx, err := types.SignTx(tx, signer, pk)
 		if err != nil {
 			return nil, fmt.Errorf("failed to sign transaction: %w", err)
@@ -79,25 +76,3 @@ func createConsecutiveBasicEthTransactions(pk *ecdsa.PrivateKey, addr common.Add
 
 	return txs, nil
 }
-
-func issueTxList(ctx context.Context, client *ethclient.Client, txs []*types.Transaction) error {
-	for _, tx := range txs {
-		if err := client.SendTransaction(ctx, tx); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func confirmTxList(ctx context.Context, client *ethclient.Client, txs []*types.Transaction) error {
-	for _, tx := range txs {
-		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
-		if err != nil {
-			return err
-		}
-		logrus.Infof("Transaction was in block: (%s, %d)", receipt.BlockHash.Hex(), receipt.BlockNumber)
-	}
-
-	return nil
-}
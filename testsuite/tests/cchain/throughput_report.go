@@ -0,0 +1,224 @@
+package cchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// txRecord is when a transaction of the load was issued
+type txRecord struct {
+	// Index of the node the transaction was issued to
+	issuer   int
+	issuedAt time.Time
+}
+
+// headRecord is a head a node was notified of
+type headRecord struct {
+	number uint64
+	seenAt time.Time
+}
+
+// loadRecorder records when the transactions of a load are issued and when each node is notified of its new heads.
+// Only headers are recorded while the load runs, so that observing the nodes doesn't load them; which blocks include
+// the load is only looked up once it's over. It's safe for concurrent use.
+type loadRecorder struct {
+	lock sync.Mutex
+	txs  map[common.Hash]*txRecord
+
+	// Node index -> the heads the node was notified of, in the order it was notified of them
+	heads [][]headRecord
+}
+
+// newLoadRecorder returns a recorder of [txLists], each of which is issued to node [issuers[i]], of [numNodes]
+func newLoadRecorder(txLists [][]*types.Transaction, issuers []int, numNodes int) *loadRecorder {
+	r := &loadRecorder{
+		txs:   make(map[common.Hash]*txRecord),
+		heads: make([][]headRecord, numNodes),
+	}
+	for i, txList := range txLists {
+		for _, tx := range txList {
+			r.txs[tx.Hash()] = &txRecord{issuer: issuers[i]}
+		}
+	}
+	return r
+}
+
+// issued records that the transaction with hash [txHash] was issued at [at]
+func (r *loadRecorder) issued(txHash common.Hash, at time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.txs[txHash].issuedAt = at
+}
+
+// head records that node [node] was notified at [at] of [header] as its head
+func (r *loadRecorder) head(node int, header *types.Header, at time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.heads[node] = append(r.heads[node], headRecord{number: header.Number.Uint64(), seenAt: at})
+}
+
+// lowestHead returns the lowest number of the latest heads the nodes were notified of, which every node has reached
+func (r *loadRecorder) lowestHead() uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	lowest := uint64(0)
+	for node, heads := range r.heads {
+		highest := uint64(0)
+		for _, head := range heads {
+			if head.number > highest {
+				highest = head.number
+			}
+		}
+		if node == 0 || highest < lowest {
+			lowest = highest
+		}
+	}
+	return lowest
+}
+
+// reachedAt returns when node [node] was first notified of a head at or above [number], and whether it was. A node is
+// only notified of its new head, which may be several blocks past its last one, so it's considered to have seen every
+// block up to its head at once.
+func (r *loadRecorder) reachedAt(node int, number uint64) (time.Time, bool) {
+	for _, head := range r.heads[node] {
+		if head.number >= number {
+			return head.seenAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// latencySummary summarizes a set of latencies
+type latencySummary struct {
+	Count  int           `json:"count"`
+	Median time.Duration `json:"median"`
+	P99    time.Duration `json:"p99"`
+	Max    time.Duration `json:"max"`
+}
+
+func summarizeLatencies(latencies []time.Duration) latencySummary {
+	if len(latencies) == 0 {
+		return latencySummary{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return latencySummary{
+		Count:  len(latencies),
+		Median: latencies[len(latencies)/2],
+		P99:    latencies[len(latencies)*99/100],
+		Max:    latencies[len(latencies)-1],
+	}
+}
+
+// String implements the fmt.Stringer interface
+func (s latencySummary) String() string {
+	if s.Count == 0 {
+		return "none observed"
+	}
+	return fmt.Sprintf("median %v, 99th percentile %v, max %v over %d observations", s.Median, s.P99, s.Max, s.Count)
+}
+
+// throughputReport is the throughput of a load, from issuing its first transaction until every node saw its last
+// one included
+type throughputReport struct {
+	Txs      int           `json:"txs"`
+	Blocks   int           `json:"blocks"`
+	Duration time.Duration `json:"duration"`
+
+	TxsPerSecond    float64 `json:"txsPerSecond"`
+	BlocksPerSecond float64 `json:"blocksPerSecond"`
+	GasPerSecond    float64 `json:"gasPerSecond"`
+
+	// Time from issuing a transaction until a node other than the issuer saw it included. With one node, the issuer
+	// itself.
+	InclusionLatency latencySummary `json:"inclusionLatency"`
+}
+
+// report summarizes the recorded load, which [blocks] include. It fails if a transaction of the load isn't in
+// [blocks], or a node wasn't notified of a head including it.
+func (r *loadRecorder) report(blocks []*types.Block) (throughputReport, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	txBlocks := make(map[common.Hash]uint64)
+	gasUsed := uint64(0)
+	numBlocks := 0
+	for _, block := range blocks {
+		ofLoad := false
+		for _, tx := range block.Transactions() {
+			if _, ok := r.txs[tx.Hash()]; ok {
+				txBlocks[tx.Hash()] = block.NumberU64()
+				ofLoad = true
+			}
+		}
+		if ofLoad {
+			gasUsed += block.GasUsed()
+			numBlocks++
+		}
+	}
+
+	var start, end time.Time
+	var inclusionLatencies []time.Duration
+	for txHash, tx := range r.txs {
+		if start.IsZero() || tx.issuedAt.Before(start) {
+			start = tx.issuedAt
+		}
+		number, found := txBlocks[txHash]
+		if !found {
+			return throughputReport{}, fmt.Errorf("transaction %s isn't in the blocks of the load", txHash.Hex())
+		}
+		for node := range r.heads {
+			at, reached := r.reachedAt(node, number)
+			if !reached {
+				return throughputReport{}, fmt.Errorf("node %d wasn't notified of block %d, which includes transaction %s", node, number, txHash.Hex())
+			}
+			if at.After(end) {
+				end = at
+			}
+			if node != tx.issuer || len(r.heads) == 1 {
+				inclusionLatencies = append(inclusionLatencies, at.Sub(tx.issuedAt))
+			}
+		}
+	}
+
+	report := throughputReport{
+		Txs:              len(r.txs),
+		Blocks:           numBlocks,
+		InclusionLatency: summarizeLatencies(inclusionLatencies),
+	}
+	if !end.After(start) {
+		return report, nil
+	}
+	report.Duration = end.Sub(start)
+	seconds := report.Duration.Seconds()
+	report.TxsPerSecond = float64(report.Txs) / seconds
+	report.BlocksPerSecond = float64(report.Blocks) / seconds
+	report.GasPerSecond = float64(gasUsed) / seconds
+	return report, nil
+}
+
+// write writes [report] as JSON to [reportFilepath], creating its directory if needed
+func (report throughputReport) write(reportFilepath string) error {
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize throughput report: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(reportFilepath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory of %s: %w", reportFilepath, err)
+	}
+	if err := ioutil.WriteFile(reportFilepath, bytes, 0644); err != nil {
+		return fmt.Errorf("failed to write throughput report to %s: %w", reportFilepath, err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package cchain
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/coreth/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBlock(number int64, txs ...*types.Transaction) *types.Block {
+	header := &types.Header{Number: big.NewInt(number), GasUsed: uint64(21000 * len(txs))}
+	return types.NewBlockWithHeader(header).WithBody(txs, nil, 0, nil)
+}
+
+func TestLoadRecorderReport(t *testing.T) {
+	key, err := ethcrypto.GenerateKey()
+	assert.NoError(t, err)
+	txs, err := createConsecutiveBasicEthTransactions(key, ethcrypto.PubkeyToAddress(key.PublicKey), 0, 4)
+	assert.NoError(t, err)
+	// The first two transactions are issued to node 0 and the others to node 1
+	recorder := newLoadRecorder([][]*types.Transaction{txs[:2], txs[2:]}, []int{0, 1}, 2)
+
+	start := time.Now()
+	for _, tx := range txs {
+		recorder.issued(tx.Hash(), start)
+	}
+
+	// Node 1 is only notified of a head past the second block, so it sees that block then
+	first, second, empty := testBlock(1, txs[:3]...), testBlock(2, txs[3]), testBlock(3)
+	recorder.head(0, first.Header(), start.Add(time.Second))
+	recorder.head(1, first.Header(), start.Add(1500*time.Millisecond))
+	recorder.head(0, second.Header(), start.Add(2*time.Second))
+	assert.Equal(t, uint64(1), recorder.lowestHead())
+	recorder.head(1, empty.Header(), start.Add(4*time.Second))
+	assert.Equal(t, uint64(2), recorder.lowestHead())
+
+	_, err = recorder.report([]*types.Block{first})
+	assert.Error(t, err, "Expected an error for a transaction that isn't in the blocks")
+
+	// A block without transactions of the load isn't counted
+	report, err := recorder.report([]*types.Block{first, second, empty})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.Txs)
+	assert.Equal(t, 2, report.Blocks)
+	assert.Equal(t, 4*time.Second, report.Duration)
+	assert.Equal(t, 0.5, report.BlocksPerSecond)
+	assert.Equal(t, float64(4*21000)/4, report.GasPerSecond)
+	// Only the inclusions seen by the node each transaction wasn't issued to count
+	assert.Equal(t, latencySummary{Count: 4, Median: 1500 * time.Millisecond, P99: 2 * time.Second, Max: 2 * time.Second}, report.InclusionLatency)
+}
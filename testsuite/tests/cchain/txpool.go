@@ -0,0 +1,342 @@
+package cchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/tester"
+	"github.com/ava-labs/coreth"
+	"github.com/ava-labs/coreth/core"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// How long transactions behind a nonce gap are checked to stay out of blocks
+	gapWait = 3 * time.Second
+
+	// How many transactions are queued behind a nonce gap beyond the number the pool keeps per account
+	excessQueuedTxs = 16
+)
+
+// The number of transactions behind a nonce gap the pool keeps per account, which the C Chain leaves at its default.
+// The pool drops the ones with the highest nonces beyond it.
+var accountQueueLimit = int(core.DefaultTxPoolConfig.AccountQueue)
+
+// txPoolTest submits invalid transactions and transactions the pool must hold back, and checks that each is rejected
+// with the expected error or eventually included
+type txPoolTest struct {
+	// The clients of the nodes, of which the first is issued transactions
+	clients []*services.Client
+	nodes   ethNodes
+
+	sender    *ethAccount
+	recipient common.Address
+}
+
+// NewTxPoolExecutor returns a test of the C Chain's transaction pool, issued to the first of [clients]
+func NewTxPoolExecutor(clients []*services.Client) tester.AvalancheTester {
+	return &txPoolTest{clients: clients}
+}
+
+// ExecuteTest implements the AvalancheTester interface
+func (t *txPoolTest) ExecuteTest() error {
+	ctx := context.Background()
+	nodes, err := newEthNodes(t.clients)
+	if err != nil {
+		return err
+	}
+	t.nodes = nodes
+
+	if t.sender, err = newEthAccount(); err != nil {
+		return err
+	}
+	recipient, err := newEthAccount()
+	if err != nil {
+		return err
+	}
+	t.recipient = recipient.address
+	logrus.Infof("Funding sender %s.", t.sender.address.Hex())
+	if err := fundEthAccounts(t.clients[0], t.sender); err != nil {
+		return err
+	}
+
+	cases := []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{"invalid transactions", t.testInvalidTxs},
+		{"nonce gap and replacement", t.testGapAndReplacement},
+		{"reused nonce", t.testReusedNonce},
+		{"queue limit", t.testQueueLimit},
+	}
+	for _, c := range cases {
+		if err := c.run(ctx); err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+		logrus.Infof("Transaction pool case %s passed.", c.name)
+	}
+	return nil
+}
+
+// transfer returns a transfer of nothing from [from] to the recipient with [nonce], [price] and [gasLimit], signed
+// with [txSigner]
+func (t *txPoolTest) transfer(from *ethAccount, nonce uint64, price *big.Int, gasLimit uint64, txSigner types.Signer) (*types.Transaction, error) {
+	tx := types.NewTransaction(nonce, t.recipient, big.NewInt(0), gasLimit, price, nil)
+	signedTx, err := types.SignTx(tx, txSigner, from.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// submit signs a transfer from the sender with [nonce] and [price], and sends it to the first node
+func (t *txPoolTest) submit(ctx context.Context, nonce uint64, price *big.Int) (*types.Transaction, error) {
+	tx, err := t.transfer(t.sender, nonce, price, 21000, signer)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.nodes.clients[0].SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("transaction with nonce %d was rejected: %w", nonce, err)
+	}
+	return tx, nil
+}
+
+// expectRejection sends [tx] to the first node, and returns an error unless it's rejected with [expected]
+func (t *txPoolTest) expectRejection(ctx context.Context, tx *types.Transaction, expected error) error {
+	err := t.nodes.clients[0].SendTransaction(ctx, tx)
+	if err == nil {
+		return fmt.Errorf("expected transaction %s to be rejected with %q, but it was accepted", tx.Hash().Hex(), expected)
+	}
+	// The error is returned over JSON-RPC, so only its message is kept
+	if err.Error() != expected.Error() {
+		return fmt.Errorf("expected transaction %s to be rejected with %q, but it was rejected with %q", tx.Hash().Hex(), expected, err)
+	}
+	return nil
+}
+
+// expectNonces checks that the pending and latest nonces of the sender on the first node are [pending] and [latest]
+func (t *txPoolTest) expectNonces(ctx context.Context, pending uint64, latest uint64) error {
+	// The ethclient lacks PendingNonceAt, so the pending nonce is requested directly
+	rpcClient, err := t.clients[0].CChainEthRPC()
+	if err != nil {
+		return err
+	}
+	defer rpcClient.Close()
+	var pendingNonce hexutil.Uint64
+	if err := rpcClient.CallContext(ctx, &pendingNonce, "eth_getTransactionCount", t.sender.address, "pending"); err != nil {
+		return fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+	latestNonce, err := t.nodes.clients[0].NonceAt(ctx, t.sender.address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest nonce: %w", err)
+	}
+	if uint64(pendingNonce) != pending || latestNonce != latest {
+		return fmt.Errorf("pending and latest nonces are %d and %d, expected %d and %d", pendingNonce, latestNonce, pending, latest)
+	}
+	return nil
+}
+
+// expectNoReceipt checks that the first node has no receipt of [tx]
+func (t *txPoolTest) expectNoReceipt(ctx context.Context, tx *types.Transaction) error {
+	_, err := t.nodes.clients[0].TransactionReceipt(ctx, tx.Hash())
+	if err == nil {
+		return fmt.Errorf("transaction %s with nonce %d was included", tx.Hash().Hex(), tx.Nonce())
+	}
+	if !errors.Is(err, coreth.NotFound) {
+		return fmt.Errorf("failed to get receipt of %s: %w", tx.Hash().Hex(), err)
+	}
+	return nil
+}
+
+// awaitIncluded waits until every node has the receipts of [txs], which must have succeeded, and advances the
+// sender's nonce past them
+func (t *txPoolTest) awaitIncluded(ctx context.Context, txs ...*types.Transaction) error {
+	for _, tx := range txs {
+		receipt, err := t.nodes.awaitReceipt(ctx, t.nodes.clients[0], tx.Hash())
+		if err != nil {
+			return err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return fmt.Errorf("transaction %s with nonce %d failed", tx.Hash().Hex(), tx.Nonce())
+		}
+		if tx.Nonce() >= t.sender.nonce {
+			t.sender.nonce = tx.Nonce() + 1
+		}
+	}
+	return t.nodes.awaitOnAllNodes(ctx, txs[len(txs)-1].Hash())
+}
+
+// testInvalidTxs checks that transactions the pool can never accept are rejected with the expected errors
+func (t *txPoolTest) testInvalidTxs(ctx context.Context) error {
+	head, err := t.nodes.clients[0].HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest header: %w", err)
+	}
+	unfunded, err := newEthAccount()
+	if err != nil {
+		return err
+	}
+	nonce := t.sender.nonce
+	invalidTxs := []struct {
+		name     string
+		from     *ethAccount
+		price    *big.Int
+		gasLimit uint64
+		signer   types.Signer
+		expected error
+	}{
+		// Signed for Ethereum's main net, so the sender recovered with the C Chain's ID isn't the signer
+		{"wrong chain ID", t.sender, gasPrice, 21000, types.NewEIP155Signer(big.NewInt(1)), core.ErrInvalidSender},
+		{"gas price below the minimum", t.sender, new(big.Int).Sub(gasPrice, big.NewInt(1)), 21000, signer, core.ErrUnderpriced},
+		// Its fee is still below the node's cap on the fee of submitted transactions, which is checked first
+		{"gas limit above the block's", t.sender, gasPrice, head.GasLimit + 1, signer, core.ErrGasLimit},
+		{"insufficient balance", unfunded, gasPrice, 21000, signer, core.ErrInsufficientFunds},
+		{"gas limit below the intrinsic gas", t.sender, gasPrice, 20999, signer, core.ErrIntrinsicGas},
+	}
+	for _, invalid := range invalidTxs {
+		tx, err := t.transfer(invalid.from, invalid.from.nonce, invalid.price, invalid.gasLimit, invalid.signer)
+		if err != nil {
+			return err
+		}
+		if err := t.expectRejection(ctx, tx, invalid.expected); err != nil {
+			return fmt.Errorf("%s: %w", invalid.name, err)
+		}
+	}
+	// None of them may have been included, or even kept in the pool
+	return t.expectNonces(ctx, nonce, nonce)
+}
+
+// testGapAndReplacement queues a transaction behind a nonce gap, replaces it with one with a higher gas price, and
+// checks that only the replacement is included once the gap is filled
+func (t *txPoolTest) testGapAndReplacement(ctx context.Context) error {
+	nonce := t.sender.nonce
+	original, err := t.submit(ctx, nonce+1, gasPrice)
+	if err != nil {
+		return err
+	}
+	// The pool requires a replacement to raise the gas price by 10%
+	underpriced, err := t.transfer(t.sender, nonce+1, new(big.Int).Div(new(big.Int).Mul(gasPrice, big.NewInt(105)), big.NewInt(100)), 21000, signer)
+	if err != nil {
+		return err
+	}
+	if err := t.expectRejection(ctx, underpriced, core.ErrReplaceUnderpriced); err != nil {
+		return err
+	}
+	replacement, err := t.submit(ctx, nonce+1, new(big.Int).Mul(gasPrice, big.NewInt(2)))
+	if err != nil {
+		return err
+	}
+	if err := t.expectRejection(ctx, replacement, core.ErrAlreadyKnown); err != nil {
+		return err
+	}
+
+	time.Sleep(gapWait)
+	if err := t.expectNoReceipt(ctx, replacement); err != nil {
+		return fmt.Errorf("expected the transaction behind the gap to wait for it: %w", err)
+	}
+	if err := t.expectNonces(ctx, nonce, nonce); err != nil {
+		return err
+	}
+
+	filler, err := t.submit(ctx, nonce, gasPrice)
+	if err != nil {
+		return err
+	}
+	if err := t.awaitIncluded(ctx, filler, replacement); err != nil {
+		return err
+	}
+	if err := t.expectNoReceipt(ctx, original); err != nil {
+		return fmt.Errorf("expected the replaced transaction to be dropped: %w", err)
+	}
+	return t.expectNonces(ctx, t.sender.nonce, t.sender.nonce)
+}
+
+// testReusedNonce checks that transactions reusing the nonce of an included transaction are rejected
+func (t *txPoolTest) testReusedNonce(ctx context.Context) error {
+	for _, price := range []*big.Int{gasPrice, new(big.Int).Mul(gasPrice, big.NewInt(10))} {
+		tx, err := t.transfer(t.sender, t.sender.nonce-1, price, 21000, signer)
+		if err != nil {
+			return err
+		}
+		if err := t.expectRejection(ctx, tx, core.ErrNonceTooLow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testQueueLimit queues more transactions behind a nonce gap than the pool keeps, checks that it drops the excess,
+// and that it drains the rest once the gap is filled
+func (t *txPoolTest) testQueueLimit(ctx context.Context) error {
+	nonce := t.sender.nonce
+	queued := make([]*types.Transaction, 0, accountQueueLimit+excessQueuedTxs)
+	for i := 1; i <= accountQueueLimit+excessQueuedTxs; i++ {
+		// Every transaction is accepted, and the pool only drops the excess when it next reorganizes
+		tx, err := t.submit(ctx, nonce+uint64(i), gasPrice)
+		if err != nil {
+			return err
+		}
+		queued = append(queued, tx)
+	}
+	kept, dropped := queued[:accountQueueLimit], queued[accountQueueLimit:]
+
+	deadline := time.Now().Add(receiptTimeout)
+	for _, tx := range dropped {
+		for {
+			_, _, err := t.nodes.clients[0].TransactionByHash(ctx, tx.Hash())
+			if errors.Is(err, coreth.NotFound) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get transaction %s: %w", tx.Hash().Hex(), err)
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("pool kept transaction with nonce %d beyond its queue limit of %d", tx.Nonce(), accountQueueLimit)
+			}
+			time.Sleep(receiptPollInterval)
+		}
+	}
+	for _, tx := range kept {
+		if _, _, err := t.nodes.clients[0].TransactionByHash(ctx, tx.Hash()); err != nil {
+			return fmt.Errorf("pool dropped transaction with nonce %d within its queue limit: %w", tx.Nonce(), err)
+		}
+	}
+	if err := t.expectNonces(ctx, nonce, nonce); err != nil {
+		return err
+	}
+
+	filler, err := t.submit(ctx, nonce, gasPrice)
+	if err != nil {
+		return err
+	}
+	if err := t.awaitIncluded(ctx, append([]*types.Transaction{filler}, kept...)...); err != nil {
+		return err
+	}
+	logrus.Infof("Pool drained %d queued transactions once their gap was filled.", len(kept))
+	for _, tx := range dropped {
+		if err := t.expectNoReceipt(ctx, tx); err != nil {
+			return fmt.Errorf("expected the transactions beyond the queue limit to be dropped: %w", err)
+		}
+	}
+	if err := t.expectNonces(ctx, t.sender.nonce, t.sender.nonce); err != nil {
+		return err
+	}
+
+	// The dropped transactions are now executable, so sending them again includes them
+	for _, tx := range dropped {
+		if err := t.nodes.clients[0].SendTransaction(ctx, tx); err != nil {
+			return fmt.Errorf("dropped transaction with nonce %d was rejected when sent again: %w", tx.Nonce(), err)
+		}
+	}
+	if err := t.awaitIncluded(ctx, dropped...); err != nil {
+		return err
+	}
+	return t.expectNonces(ctx, t.sender.nonce, t.sender.nonce)
+}
@@ -0,0 +1,47 @@
+package cchain
+
+import (
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/testsuite"
+
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// TxPoolTest submits invalid transactions and transactions behind nonce gaps to one boot node, and checks that
+// each is rejected with the expected error or eventually included on every boot node
+type TxPoolTest struct {
+	ImageName string
+}
+
+// Run implements the Kurtosis Test interface
+func (test TxPoolTest) Run(network networks.Network, context testsuite.TestContext) {
+	clients, err := bootNodeClients(network)
+	if err != nil {
+		context.Fatal(err)
+	}
+	defer closeClients(clients)
+
+	logrus.Infof("Executing C-Chain transaction pool test...")
+	if err := NewTxPoolExecutor(clients).ExecuteTest(); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "C-Chain transaction pool test failed."))
+	}
+	logrus.Infof("C-Chain transaction pool test completed successfully.")
+}
+
+// GetNetworkLoader implements the Kurtosis Test interface
+func (test TxPoolTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	return newBootNodeNetworkLoader(test.ImageName)
+}
+
+// GetExecutionTimeout implements the Kurtosis Test interface
+func (test TxPoolTest) GetExecutionTimeout() time.Duration {
+	return 5 * time.Minute
+}
+
+// GetSetupBuffer implements the Kurtosis Test interface
+func (test TxPoolTest) GetSetupBuffer() time.Duration {
+	return 2 * time.Minute
+}
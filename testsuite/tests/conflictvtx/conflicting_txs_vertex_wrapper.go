@@ -104,6 +104,7 @@ func getByzantineNetworkLoader(desiredServices map[networks.ServiceID]networks.C
 	logrus.Debugf("Normal Image Name: %s", normalImageName)
 
 	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
 		true,
 		normalImageName,
 		avalancheService.DEBUG,
@@ -111,6 +112,7 @@ func getByzantineNetworkLoader(desiredServices map[networks.ServiceID]networks.C
 		2,
 		1000000,
 		2*time.Second,
+		false,
 		serviceConfigs,
 		desiredServices,
 	)
@@ -102,6 +102,7 @@ func (test StakingNetworkFullyConnectedTest) GetNetworkLoader() (networks.Networ
 		nonBootNonValidatorServiceID: normalNodeConfigID,
 	}
 	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
 		true,
 		test.ImageName,
 		avalancheService.DEBUG,
@@ -109,6 +110,7 @@ func (test StakingNetworkFullyConnectedTest) GetNetworkLoader() (networks.Networ
 		2,
 		0,
 		2*time.Second,
+		false,
 		serviceConfigs,
 		desiredServices,
 	)
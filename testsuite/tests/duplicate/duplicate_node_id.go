@@ -187,6 +187,7 @@ func (test DuplicateNodeIDTest) GetNetworkLoader() (networks.NetworkLoader, erro
 		vanillaNodeServiceID: normalNodeConfigID,
 	}
 	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
 		true,
 		test.ImageName,
 		avalancheService.DEBUG,
@@ -194,6 +195,7 @@ func (test DuplicateNodeIDTest) GetNetworkLoader() (networks.NetworkLoader, erro
 		2,
 		0,
 		2*time.Second,
+		false,
 		serviceConfigs,
 		desiredServices,
 	)
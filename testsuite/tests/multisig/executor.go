@@ -0,0 +1,249 @@
+package multisig
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
+	"github.com/ava-labs/avalanche-testing/testsuite/tester"
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/utils/codec"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	multisigUsername = "multisig_funder"
+	multisigPassword = "MyNameIs!Jeff"
+
+	threshold = uint32(2)
+	numOwners = 3
+
+	// Distinct amounts are used so that each output of the funding transaction can be identified after sorting
+	thresholdSpendAmount  = uint64(3000000)
+	underSignedAmount     = uint64(4000000)
+	timelockedSpendAmount = uint64(5000000)
+
+	// How far in the future the timelocked output becomes spendable. This must leave enough time to fund the outputs
+	// and run the signer count checks before the output unlocks.
+	locktimeDelay = time.Minute
+)
+
+type executor struct {
+	client            *services.Client
+	registry          *services.ChainRegistry
+	txFee             uint64
+	acceptanceTimeout time.Duration
+}
+
+// NewMultisigTestExecutor returns a test executor that creates X Chain outputs owned by M-of-N address sets and
+// verifies that they can only be spent by exactly M signatures once their locktime has passed
+func NewMultisigTestExecutor(
+	client *services.Client,
+	registry *services.ChainRegistry,
+	txFee uint64,
+	acceptanceTimeout time.Duration) tester.AvalancheTester {
+	return &executor{
+		client:            client,
+		registry:          registry,
+		txFee:             txFee,
+		acceptanceTimeout: acceptanceTimeout,
+	}
+}
+
+// ExecuteTest implements the AvalancheTester interface
+func (e *executor) ExecuteTest() error {
+	user := api.UserPass{Username: multisigUsername, Password: multisigPassword}
+	runner := helpers.NewRPCWorkFlowRunner(e.client, user, e.acceptanceTimeout)
+
+	genesisAddress, err := runner.ImportGenesisFunds()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to import genesis funds.")
+	}
+	codec, err := helpers.CreateXChainCodec()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to initialize codec.")
+	}
+	genesisKey, err := e.exportKey(user, genesisAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to export genesis key.")
+	}
+	fundingUTXO, err := e.findFundingUTXO(codec, genesisAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to find a UTXO to fund the multisig outputs.")
+	}
+
+	// ====================================== CREATE THRESHOLD OUTPUTS =============================
+	owners, err := NewThresholdOwners(threshold, numOwners, 0)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create threshold owners.")
+	}
+	locktime := uint64(time.Now().Add(locktimeDelay).Unix())
+	timelockedOwners, err := NewThresholdOwners(threshold, numOwners, locktime)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create timelocked threshold owners.")
+	}
+	fundingTx, err := CreateFundingTx(
+		fundingUTXO,
+		[]*avax.TransferableOutput{
+			CreateThresholdOutput(thresholdSpendAmount, owners, e.registry),
+			CreateThresholdOutput(underSignedAmount, owners, e.registry),
+			CreateThresholdOutput(timelockedSpendAmount, timelockedOwners, e.registry),
+		},
+		e.txFee,
+		genesisKey,
+		e.registry,
+		codec,
+	)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create funding transaction.")
+	}
+	if err := e.issueAndAwait(runner, fundingTx); err != nil {
+		return stacktrace.Propagate(err, "Failed to fund threshold outputs.")
+	}
+	logrus.Infof("Created %d-of-%d outputs, including one locked until %d.", threshold, numOwners, locktime)
+
+	thresholdUTXO, err := FindOutputUTXO(fundingTx, thresholdSpendAmount)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to find threshold UTXO.")
+	}
+	underSignedUTXO, err := FindOutputUTXO(fundingTx, underSignedAmount)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to find under signed UTXO.")
+	}
+	timelockedUTXO, err := FindOutputUTXO(fundingTx, timelockedSpendAmount)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to find timelocked UTXO.")
+	}
+
+	factory := crypto.FactorySECP256K1R{}
+	recipientKey, err := factory.NewPrivateKey()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to generate recipient key.")
+	}
+	recipient := recipientKey.PublicKey().Address()
+	recipientAddress, err := e.registry.AddressFormatter().XChainAddress(recipient)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to format recipient address.")
+	}
+
+	// ====================================== SIGNER COUNT =========================================
+	underSignedTx, err := CreateThresholdSpendTx(underSignedUTXO, owners, []uint32{1}, recipient, e.txFee, e.registry, codec)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create under signed transaction.")
+	}
+	if err := e.expectRejection(underSignedTx, "fewer than threshold signatures"); err != nil {
+		return err
+	}
+	overSignedTx, err := CreateThresholdSpendTx(underSignedUTXO, owners, []uint32{0, 1, 2}, recipient, e.txFee, e.registry, codec)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create over signed transaction.")
+	}
+	if err := e.expectRejection(overSignedTx, "more than threshold signatures"); err != nil {
+		return err
+	}
+	thresholdTx, err := CreateThresholdSpendTx(thresholdUTXO, owners, []uint32{0, 2}, recipient, e.txFee, e.registry, codec)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create threshold signed transaction.")
+	}
+	if err := e.issueAndAwait(runner, thresholdTx); err != nil {
+		return stacktrace.Propagate(err, "Transaction signed by exactly %d owners was not accepted.", threshold)
+	}
+	// The rejections above must not have affected the under signed UTXO, so it can still be spent by a valid set of signers
+	correctlySignedTx, err := CreateThresholdSpendTx(underSignedUTXO, owners, []uint32{1, 2}, recipient, e.txFee, e.registry, codec)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create correctly signed transaction.")
+	}
+	if err := e.issueAndAwait(runner, correctlySignedTx); err != nil {
+		return stacktrace.Propagate(err, "Failed to spend UTXO after rejected spends.")
+	}
+	logrus.Infof("Verified that only exactly %d of %d signatures can spend a threshold output.", threshold, numOwners)
+
+	// ====================================== LOCKTIME =============================================
+	if uint64(time.Now().Unix()) >= locktime {
+		return stacktrace.NewError("Locktime %d passed before the timelocked output could be tested, increase the locktime delay", locktime)
+	}
+	timelockedTx, err := CreateThresholdSpendTx(timelockedUTXO, timelockedOwners, []uint32{0, 1}, recipient, e.txFee, e.registry, codec)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create timelocked transaction.")
+	}
+	if err := e.expectRejection(timelockedTx, "an unexpired locktime"); err != nil {
+		return err
+	}
+	// Wait an extra couple of seconds to allow for clock skew between the test and the node
+	unlockTime := time.Unix(int64(locktime), 0).Add(2 * time.Second)
+	logrus.Infof("Waiting until %v for the timelocked output to unlock.", unlockTime)
+	time.Sleep(time.Until(unlockTime))
+	if err := e.issueAndAwait(runner, timelockedTx); err != nil {
+		return stacktrace.Propagate(err, "Timelocked transaction was not accepted after its locktime.")
+	}
+	logrus.Infof("Verified that a timelocked output can only be spent after its locktime.")
+
+	expectedBalance := thresholdSpendAmount + underSignedAmount + timelockedSpendAmount - 3*e.txFee
+	if err := runner.VerifyXChainAVABalance(recipientAddress, expectedBalance); err != nil {
+		return stacktrace.Propagate(err, "Unexpected balance for recipient of threshold spends.")
+	}
+	logrus.Infof("Multisig test completed successfully.")
+	return nil
+}
+
+// issueAndAwait issues [tx] to the X Chain and waits for it to be accepted
+func (e *executor) issueAndAwait(runner *helpers.RPCWorkFlowRunner, tx *avm.Tx) error {
+	txID, err := e.client.XChainAPI().IssueTx(tx.Bytes())
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to issue transaction %s.", tx.ID())
+	}
+	return runner.AwaitXChainTransactionAcceptance(txID)
+}
+
+// expectRejection issues [tx] to the X Chain and returns an error if the node accepts it.
+// [reason] describes why the transaction is invalid.
+func (e *executor) expectRejection(tx *avm.Tx, reason string) error {
+	txID, err := e.client.XChainAPI().IssueTx(tx.Bytes())
+	if err == nil {
+		return stacktrace.NewError("Transaction %s with %s should have been rejected, but was issued.", txID, reason)
+	}
+	logrus.Infof("Transaction %s with %s was rejected as expected: %v", tx.ID(), reason, err)
+	return nil
+}
+
+// exportKey returns the private key held by [user] for [address]
+func (e *executor) exportKey(user api.UserPass, address string) (*crypto.PrivateKeySECP256K1R, error) {
+	pkStr, err := e.client.XChainAPI().ExportKey(user, address)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to export key.")
+	}
+	key, err := helpers.ParsePrivateKey(pkStr)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to parse key.")
+	}
+	return key, nil
+}
+
+// findFundingUTXO returns an unlocked AVAX UTXO owned solely by [address] that is large enough to fund every output
+// created by this test
+func (e *executor) findFundingUTXO(c codec.Manager, address string) (*avax.UTXO, error) {
+	required := thresholdSpendAmount + underSignedAmount + timelockedSpendAmount + e.txFee
+	utxosBytes, _, err := e.client.XChainAPI().GetUTXOs([]string{address}, 0, "", "")
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get UTXOs of %s.", address)
+	}
+	for _, utxoBytes := range utxosBytes {
+		utxo := &avax.UTXO{}
+		if _, err := c.Unmarshal(utxoBytes, utxo); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to unmarshal utxo bytes.")
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || utxo.AssetID() != e.registry.AvaxAssetID() {
+			continue
+		}
+		if out.Locktime == 0 && out.Threshold == 1 && len(out.Addrs) == 1 && out.Amt >= required {
+			return utxo, nil
+		}
+	}
+	return nil, stacktrace.NewError("No unlocked UTXO of %s holds at least %d", address, required)
+}
@@ -0,0 +1,197 @@
+package multisig
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/codec"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// ThresholdOwners is an M-of-N owner set: any [Threshold] of [Keys] may spend outputs owned by it
+// once [Locktime] (unix seconds) has passed
+type ThresholdOwners struct {
+	Threshold uint32
+	Locktime  uint64
+	// Sorted by address, so that a key's position matches the signature index of its address
+	Keys []*crypto.PrivateKeySECP256K1R
+}
+
+// NewThresholdOwners generates [numKeys] fresh keys and returns an owner set requiring [threshold] of them to sign,
+// spendable after [locktime]
+func NewThresholdOwners(threshold uint32, numKeys int, locktime uint64) (*ThresholdOwners, error) {
+	if threshold == 0 || int(threshold) > numKeys {
+		return nil, fmt.Errorf("invalid threshold %d for %d keys", threshold, numKeys)
+	}
+	factory := crypto.FactorySECP256K1R{}
+	keys := make([]*crypto.PrivateKeySECP256K1R, numKeys)
+	for i := range keys {
+		skIntf, err := factory.NewPrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("problem generating private key: %w", err)
+		}
+		keys[i] = skIntf.(*crypto.PrivateKeySECP256K1R)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].PublicKey().Address().Bytes(), keys[j].PublicKey().Address().Bytes()) == -1
+	})
+	return &ThresholdOwners{
+		Threshold: threshold,
+		Locktime:  locktime,
+		Keys:      keys,
+	}, nil
+}
+
+// OutputOwners returns the secp256k1fx representation of [owners]
+func (owners *ThresholdOwners) OutputOwners() secp256k1fx.OutputOwners {
+	addrs := make([]ids.ShortID, len(owners.Keys))
+	for i, key := range owners.Keys {
+		addrs[i] = key.PublicKey().Address()
+	}
+	return secp256k1fx.OutputOwners{
+		Locktime:  owners.Locktime,
+		Threshold: owners.Threshold,
+		Addrs:     addrs,
+	}
+}
+
+// CreateThresholdOutput returns an AVAX output of [amount] owned by [owners]
+func CreateThresholdOutput(amount uint64, owners *ThresholdOwners, registry *services.ChainRegistry) *avax.TransferableOutput {
+	return &avax.TransferableOutput{
+		Asset: avax.Asset{ID: registry.AvaxAssetID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt:          amount,
+			OutputOwners: owners.OutputOwners(),
+		},
+	}
+}
+
+// CreateFundingTx returns a transaction spending [utxo], which must be owned solely by [privateKey], into [outs]
+// and returning the change, less [txFee], to [privateKey]
+func CreateFundingTx(
+	utxo *avax.UTXO,
+	outs []*avax.TransferableOutput,
+	txFee uint64,
+	privateKey *crypto.PrivateKeySECP256K1R,
+	registry *services.ChainRegistry,
+	codec codec.Manager) (*avm.Tx, error) {
+	utxoOut, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		return nil, fmt.Errorf("expected UTXO %s to be a secp256k1fx transfer output", utxo.InputID())
+	}
+	spent := txFee
+	for _, out := range outs {
+		spent += out.Output().Amount()
+	}
+	if utxoOut.Amt < spent {
+		return nil, fmt.Errorf("UTXO holds %d but %d is required to fund outputs and pay the fee", utxoOut.Amt, spent)
+	}
+
+	allOuts := append([]*avax.TransferableOutput{}, outs...)
+	if change := utxoOut.Amt - spent; change > 0 {
+		allOuts = append(allOuts, &avax.TransferableOutput{
+			Asset: avax.Asset{ID: registry.AvaxAssetID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: change,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{privateKey.PublicKey().Address()},
+				},
+			},
+		})
+	}
+	avax.SortTransferableOutputs(allOuts, codec)
+
+	ins := []*avax.TransferableInput{{
+		UTXOID: utxo.UTXOID,
+		Asset:  avax.Asset{ID: registry.AvaxAssetID()},
+		In: &secp256k1fx.TransferInput{
+			Amt:   utxoOut.Amt,
+			Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		},
+	}}
+
+	tx := &avm.Tx{UnsignedTx: &avm.BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    registry.NetworkID(),
+		BlockchainID: registry.XChainID(),
+		Outs:         allOuts,
+		Ins:          ins,
+	}}}
+	if err := tx.SignSECP256K1Fx(codec, [][]*crypto.PrivateKeySECP256K1R{{privateKey}}); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// CreateThresholdSpendTx returns a transaction spending [utxo], which is owned by [owners], to [address] less [txFee].
+// The input is signed by the keys of [owners] at [signerIndices], which must be sorted and unique. No check is made
+// that enough signers are provided, so that the network's handling of under-signed transactions can be tested.
+func CreateThresholdSpendTx(
+	utxo *avax.UTXO,
+	owners *ThresholdOwners,
+	signerIndices []uint32,
+	address ids.ShortID,
+	txFee uint64,
+	registry *services.ChainRegistry,
+	codec codec.Manager) (*avm.Tx, error) {
+	utxoOut, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		return nil, fmt.Errorf("expected UTXO %s to be a secp256k1fx transfer output", utxo.InputID())
+	}
+	if utxoOut.Amt <= txFee {
+		return nil, fmt.Errorf("UTXO holds %d which cannot cover the transaction fee of %d", utxoOut.Amt, txFee)
+	}
+	signers := make([]*crypto.PrivateKeySECP256K1R, len(signerIndices))
+	for i, signerIndex := range signerIndices {
+		if int(signerIndex) >= len(owners.Keys) {
+			return nil, fmt.Errorf("signer index %d out of range for %d owners", signerIndex, len(owners.Keys))
+		}
+		signers[i] = owners.Keys[signerIndex]
+	}
+
+	outs := []*avax.TransferableOutput{{
+		Asset: avax.Asset{ID: registry.AvaxAssetID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: utxoOut.Amt - txFee,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{address},
+			},
+		},
+	}}
+	ins := []*avax.TransferableInput{{
+		UTXOID: utxo.UTXOID,
+		Asset:  avax.Asset{ID: registry.AvaxAssetID()},
+		In: &secp256k1fx.TransferInput{
+			Amt:   utxoOut.Amt,
+			Input: secp256k1fx.Input{SigIndices: signerIndices},
+		},
+	}}
+
+	tx := &avm.Tx{UnsignedTx: &avm.BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    registry.NetworkID(),
+		BlockchainID: registry.XChainID(),
+		Outs:         outs,
+		Ins:          ins,
+	}}}
+	if err := tx.SignSECP256K1Fx(codec, [][]*crypto.PrivateKeySECP256K1R{signers}); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// FindOutputUTXO returns the UTXO produced by [tx] holding exactly [amount]
+func FindOutputUTXO(tx *avm.Tx, amount uint64) (*avax.UTXO, error) {
+	for _, utxo := range tx.UTXOs() {
+		if out, ok := utxo.Out.(*secp256k1fx.TransferOutput); ok && out.Amt == amount {
+			return utxo, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s produced no output of amount %d", tx.ID(), amount)
+}
@@ -0,0 +1,423 @@
+package soak
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
+	"github.com/ava-labs/avalanche-testing/testsuite/monitoring"
+	"github.com/ava-labs/avalanche-testing/testsuite/profiling"
+	"github.com/ava-labs/avalanche-testing/testsuite/testaccounts"
+	"github.com/ava-labs/avalanche-testing/testsuite/tester"
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ava-labs/coreth/plugin/evm"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	accountPassword = "Soak!Test!Passw0rd"
+
+	bytesPerMiB = 1024 * 1024
+)
+
+// suspectedTx is a transaction that wasn't accepted within the acceptance timeout
+type suspectedTx struct {
+	description string
+	check       txCheck
+}
+
+// heapSample is the bytes each node had in use on its heap at a point of the test
+type heapSample struct {
+	time  time.Time
+	sizes map[networks.ServiceID]uint64
+}
+
+type executor struct {
+	clients  map[networks.ServiceID]*services.Client
+	profiler *profiling.NodeProfiler
+	monitor  *monitoring.HealthMonitor
+	params   Params
+
+	// Set up by ExecuteTest before the workload starts
+	serviceIDs []networks.ServiceID
+	accounts   []*soakAccount
+	nodeIDs    []string
+	signer     types.Signer
+	verifier   *helpers.RPCWorkFlowRunner
+
+	// Held for reading by workers while they issue an operation, and for writing while the network is checked, so
+	// that no transaction is in flight during a check
+	pause sync.RWMutex
+
+	lock        sync.Mutex
+	suspects    []suspectedTx
+	completed   map[operation]int
+	heapSamples []heapSample
+}
+
+// NewSoakExecutor returns a test executor that issues the workload described by [params] to the nodes of [clients]
+// and checks the network while it runs. [profiler] must profile, and [monitor] must watch, the nodes of [clients].
+func NewSoakExecutor(
+	clients map[networks.ServiceID]*services.Client,
+	profiler *profiling.NodeProfiler,
+	monitor *monitoring.HealthMonitor,
+	params Params) tester.AvalancheTester {
+	return &executor{
+		clients:   clients,
+		profiler:  profiler,
+		monitor:   monitor,
+		params:    params,
+		completed: make(map[operation]int),
+	}
+}
+
+// ExecuteTest implements the AvalancheTester interface
+func (e *executor) ExecuteTest() error {
+	if err := e.setUp(); err != nil {
+		return stacktrace.Propagate(err, "Failed to set up the workload.")
+	}
+	picker, err := newOperationPicker(e.params.weights())
+	if err != nil {
+		return stacktrace.Propagate(err, "Invalid operation weights.")
+	}
+
+	deadline := time.Now().Add(e.params.Duration)
+	stop := make(chan struct{})
+	workerErrs := make(chan error, e.params.NumWorkers)
+	var workers sync.WaitGroup
+	for i := 0; i < e.params.NumWorkers; i++ {
+		w := &worker{
+			executor: e,
+			rng:      rand.New(rand.NewSource(e.params.Seed + int64(i))),
+		}
+		for j := i; j < len(e.accounts); j += e.params.NumWorkers {
+			w.accounts = append(w.accounts, e.accounts[j])
+		}
+		workers.Add(1)
+		go func(w *worker) {
+			defer workers.Done()
+			if err := w.work(picker, deadline, stop); err != nil {
+				workerErrs <- err
+			}
+		}(w)
+	}
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	logrus.Infof("Started %d workers issuing the workload from %d accounts until %s.", e.params.NumWorkers, len(e.accounts), deadline.Format(time.RFC3339))
+
+	ticker := time.NewTicker(e.params.CheckInterval)
+	defer ticker.Stop()
+	var workloadErr error
+	for running := true; running && workloadErr == nil; {
+		select {
+		case <-ticker.C:
+			workloadErr = e.check()
+		case workloadErr = <-workerErrs:
+		case <-done:
+			running = false
+		}
+	}
+	close(stop)
+	<-done
+	if workloadErr == nil && len(workerErrs) > 0 {
+		workloadErr = <-workerErrs
+	}
+	if workloadErr != nil {
+		return stacktrace.Propagate(workloadErr, "Workload failed.")
+	}
+
+	logrus.Infof("Workload finished. Running the final check...")
+	if err := e.check(); err != nil {
+		return stacktrace.Propagate(err, "Final check failed.")
+	}
+	e.reportCompleted()
+	e.reportHeapGrowth()
+	return e.checkSuspects()
+}
+
+// setUp funds the accounts, imports each to the keystore of a node, and samples the nodes' heaps before the workload
+func (e *executor) setUp() error {
+	for serviceID := range e.clients {
+		e.serviceIDs = append(e.serviceIDs, serviceID)
+	}
+	sort.Slice(e.serviceIDs, func(i, j int) bool { return e.serviceIDs[i] < e.serviceIDs[j] })
+	firstClient := e.clients[e.serviceIDs[0]]
+	e.verifier = helpers.NewRPCWorkFlowRunner(firstClient, api.UserPass{}, e.params.AcceptanceTimeout)
+	formatter, err := e.verifier.AddressFormatter()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to get address formatter.")
+	}
+
+	for _, serviceID := range e.serviceIDs {
+		nodeID, err := e.clients[serviceID].InfoAPI().GetNodeID()
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to get node ID of %s.", serviceID)
+		}
+		e.nodeIDs = append(e.nodeIDs, nodeID)
+	}
+	ethClient, err := firstClient.CChainEthAPI()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to connect to the C Chain.")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ethRequestTimeout)
+	defer cancel()
+	chainID, err := ethClient.ChainID(ctx)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to get C Chain ID.")
+	}
+	e.signer = types.NewEIP155Signer(chainID)
+
+	accounts, err := testaccounts.Derive(fmt.Sprintf("soak-%d", e.params.Seed), e.params.NumAccounts)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to derive accounts.")
+	}
+	if _, err := testaccounts.FundXChain(firstClient, accounts, e.params.AccountFunding, e.params.AcceptanceTimeout); err != nil {
+		return stacktrace.Propagate(err, "Failed to fund accounts.")
+	}
+	for i, account := range accounts {
+		serviceID := e.serviceIDs[i%len(e.serviceIDs)]
+		soakAccount := &soakAccount{
+			Account:          account,
+			serviceID:        serviceID,
+			client:           e.clients[serviceID],
+			user:             api.UserPass{Username: fmt.Sprintf("soak-account-%d", account.Index), Password: accountPassword},
+			cChainHexAddress: evm.GetEthAddress(account.Key),
+		}
+		if soakAccount.xChainAddress, err = account.XChainAddress(formatter); err != nil {
+			return err
+		}
+		if soakAccount.pChainAddress, err = account.PChainAddress(formatter); err != nil {
+			return err
+		}
+		if soakAccount.cChainBech32Address, err = account.CChainBech32Address(formatter); err != nil {
+			return err
+		}
+		if err := testaccounts.ImportToKeystore(soakAccount.client, soakAccount.user, []*testaccounts.Account{account}); err != nil {
+			return stacktrace.Propagate(err, "Failed to import %s to the keystore of %s.", account, serviceID)
+		}
+		// Every node must see the funding before the account is used through its node
+		if err := e.verifier.VerifyXChainAVABalanceOnAllNodes(e.clients, soakAccount.xChainAddress, e.params.AccountFunding); err != nil {
+			return stacktrace.Propagate(err, "Nodes disagree on the funding of %s.", account)
+		}
+		e.accounts = append(e.accounts, soakAccount)
+	}
+
+	return e.sampleHeap()
+}
+
+// work issues random operations from the worker's accounts until [deadline] passes or [stop] is closed, returning
+// the first error that isn't a transaction suspected to be stuck
+func (w *worker) work(picker *operationPicker, deadline time.Time, stop chan struct{}) error {
+	for time.Now().Before(deadline) {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		account := w.accounts[w.rng.Intn(len(w.accounts))]
+		op := picker.pick(w.rng)
+		w.executor.pause.RLock()
+		if account.quarantined {
+			w.executor.pause.RUnlock()
+			if w.allQuarantined() {
+				logrus.Warnf("Every account of a worker has a transaction suspected to be stuck, so the worker is stopping early.")
+				return nil
+			}
+			continue
+		}
+		err := w.execute(op, account)
+		w.executor.pause.RUnlock()
+		if err != nil {
+			return stacktrace.Propagate(err, "Operation %s from %s failed", op, account)
+		}
+		if !account.quarantined {
+			w.executor.lock.Lock()
+			w.executor.completed[op]++
+			w.executor.lock.Unlock()
+		}
+	}
+	return nil
+}
+
+func (w *worker) allQuarantined() bool {
+	w.executor.pause.RLock()
+	defer w.executor.pause.RUnlock()
+	for _, account := range w.accounts {
+		if !account.quarantined {
+			return false
+		}
+	}
+	return true
+}
+
+// suspect records that the transaction described by [description] wasn't accepted in time, and quarantines [account]
+func (e *executor) suspect(account *soakAccount, description string, check txCheck) {
+	logrus.Warnf("Timed out waiting for acceptance of %s; it will be checked again at the end of the test.", description)
+	account.quarantined = true
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.suspects = append(e.suspects, suspectedTx{
+		description: description,
+		check:       check,
+	})
+}
+
+// check pauses the workload, then checks that every node is healthy and agrees on the balances of every account, and
+// samples the nodes' heaps
+func (e *executor) check() error {
+	e.pause.Lock()
+	defer e.pause.Unlock()
+
+	serviceIDs := make(map[networks.ServiceID]bool, len(e.serviceIDs))
+	for _, serviceID := range e.serviceIDs {
+		serviceIDs[serviceID] = true
+	}
+	if err := e.monitor.AssertNeverUnhealthy(serviceIDs); err != nil {
+		return stacktrace.Propagate(err, "Nodes became unhealthy.")
+	}
+
+	checked := 0
+	for _, account := range e.accounts {
+		// The balances of an account with a stuck transaction may change while they're being compared
+		if account.quarantined {
+			continue
+		}
+		xChainBalance, err := account.xChainBalance()
+		if err != nil {
+			return err
+		}
+		if err := e.verifier.VerifyXChainAVABalanceOnAllNodes(e.clients, account.xChainAddress, xChainBalance); err != nil {
+			return stacktrace.Propagate(err, "Nodes disagree on the X Chain balance of %s.", account)
+		}
+		pChainBalance, err := account.pChainBalance()
+		if err != nil {
+			return err
+		}
+		if err := e.verifier.VerifyPChainBalanceOnAllNodes(e.clients, account.pChainAddress, pChainBalance); err != nil {
+			return stacktrace.Propagate(err, "Nodes disagree on the P Chain balance of %s.", account)
+		}
+		if err := e.verifyCChainBalanceOnAllNodes(account); err != nil {
+			return err
+		}
+		checked++
+	}
+	if err := e.sampleHeap(); err != nil {
+		return err
+	}
+	logrus.Infof("Every node is healthy and agrees on the balances of %d accounts.", checked)
+	return nil
+}
+
+// verifyCChainBalanceOnAllNodes waits for every node to report the C Chain balance of [account] that its own node
+// reports
+func (e *executor) verifyCChainBalanceOnAllNodes(account *soakAccount) error {
+	expected, err := account.cChainBalance()
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(e.params.AcceptanceTimeout)
+	for {
+		lagging := make([]string, 0)
+		for _, serviceID := range e.serviceIDs {
+			balance, err := cChainBalanceOn(e.clients[serviceID], account.cChainHexAddress)
+			if err != nil {
+				lagging = append(lagging, fmt.Sprintf("%s (error: %v)", serviceID, err))
+			} else if balance != expected {
+				lagging = append(lagging, fmt.Sprintf("%s (balance: %d)", serviceID, balance))
+			}
+		}
+		if len(lagging) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return stacktrace.NewError(
+				"Timed out waiting for nodes to converge on C Chain balance %d of %s. Lagging nodes: %s",
+				expected,
+				account,
+				strings.Join(lagging, ", "),
+			)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (e *executor) sampleHeap() error {
+	sizes, err := e.profiler.MeasureHeap()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to measure the nodes' heaps.")
+	}
+	e.heapSamples = append(e.heapSamples, heapSample{
+		time:  time.Now(),
+		sizes: sizes,
+	})
+	return nil
+}
+
+func (e *executor) reportCompleted() {
+	ops := make([]string, 0, len(e.completed))
+	for op, count := range e.completed {
+		ops = append(ops, fmt.Sprintf("%s: %d", op, count))
+	}
+	sort.Strings(ops)
+	logrus.Infof("Completed operations: %s", strings.Join(ops, ", "))
+}
+
+// reportHeapGrowth logs how the heap of every node grew between the first and the last sample
+func (e *executor) reportHeapGrowth() {
+	if len(e.heapSamples) < 2 {
+		return
+	}
+	first, last := e.heapSamples[0], e.heapSamples[len(e.heapSamples)-1]
+	elapsed := last.time.Sub(first.time)
+	for _, serviceID := range e.serviceIDs {
+		peak := uint64(0)
+		for _, sample := range e.heapSamples {
+			if sample.sizes[serviceID] > peak {
+				peak = sample.sizes[serviceID]
+			}
+		}
+		start, end := first.sizes[serviceID], last.sizes[serviceID]
+		logrus.Infof(
+			"Heap of %s grew by %.1f MiB over %s (start: %.1f MiB, peak: %.1f MiB, end: %.1f MiB)",
+			serviceID,
+			(float64(end)-float64(start))/bytesPerMiB,
+			elapsed.Round(time.Second),
+			float64(start)/bytesPerMiB,
+			float64(peak)/bytesPerMiB,
+			float64(end)/bytesPerMiB,
+		)
+	}
+}
+
+// checkSuspects checks every transaction that wasn't accepted in time again, returning an error if any still isn't
+func (e *executor) checkSuspects() error {
+	stuck := make([]string, 0)
+	for _, suspect := range e.suspects {
+		accepted, err := suspect.check()
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to check %s again.", suspect.description)
+		}
+		if !accepted {
+			stuck = append(stuck, suspect.description)
+		}
+	}
+	logrus.Infof("%d transactions were accepted late, and %d are still stuck.", len(e.suspects)-len(stuck), len(stuck))
+	if len(stuck) > 0 {
+		return stacktrace.NewError("%d transactions are still stuck in Processing: %s", len(stuck), strings.Join(stuck, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,471 @@
+package soak
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
+	"github.com/ava-labs/avalanche-testing/testsuite/testaccounts"
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/coreth"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// AVAX moved between chains by a single cross-chain transfer
+	atomicTransferAmount = units.Avax
+
+	// AVAX staked by each delegation, the minimum of the local network
+	delegationStake = units.Avax
+
+	// Each delegation lasts the local network's minimum staking duration
+	delegationDuration = 24 * time.Hour
+
+	// X Chain and EVM transfers send between one and this many milliAVAX
+	maxTransferMilliAvax = 100
+
+	// Gas used by an EVM transaction that only sends AVAX
+	evmTransferGas = 21000
+
+	// The C Chain denominates AVAX in units of 10^-18, and the X and P Chains in units of 10^-9
+	x2cConversion = 1000000000
+
+	// An account must be funded with enough AVAX for each kind of operation to succeed more than once
+	minAccountFunding = 100 * units.Avax
+
+	// Time between queries of the state of a transaction that hasn't been accepted yet
+	txPollInterval = 500 * time.Millisecond
+
+	// Timeout of single requests to the C Chain websocket
+	ethRequestTimeout = 10 * time.Second
+)
+
+// txCheck queries the state of a transaction, returning whether it has been accepted, or an error if it was rejected
+type txCheck func() (bool, error)
+
+// soakAccount is an account that the workload is issued from, through the keystore of a single node
+type soakAccount struct {
+	*testaccounts.Account
+
+	serviceID networks.ServiceID
+	client    *services.Client
+	user      api.UserPass
+
+	xChainAddress       string
+	pChainAddress       string
+	cChainBech32Address string
+	cChainHexAddress    common.Address
+
+	// The variable cap asset minted by the account, once the account has created it
+	assetID  ids.ID
+	hasAsset bool
+
+	// Set once a transaction of the account is suspected to be stuck, after which no more operations are issued from
+	// the account, since they might conflict with the stuck transaction
+	quarantined bool
+}
+
+// worker issues operations from its own subset of the accounts, one at a time
+type worker struct {
+	executor *executor
+	rng      *rand.Rand
+	accounts []*soakAccount
+}
+
+// execute issues [op] from [account] and waits for its transactions to be accepted. A transaction that isn't
+// accepted in time is recorded as suspected to be stuck rather than returned as an error.
+func (w *worker) execute(op operation, account *soakAccount) error {
+	switch op {
+	case xChainTransfer:
+		return w.transferOnXChain(account)
+	case assetOperation:
+		return w.operateAsset(account)
+	case xpTransfer:
+		return w.transferBetweenXAndP(account)
+	case xcTransfer:
+		return w.transferBetweenXAndC(account)
+	case staking:
+		return w.delegate(account)
+	case evmTransfer:
+		return w.transferOnCChain(account)
+	default:
+		return stacktrace.NewError("Unknown operation %s", op)
+	}
+}
+
+func (w *worker) transferOnXChain(account *soakAccount) error {
+	to := w.executor.accounts[w.rng.Intn(len(w.executor.accounts))]
+	amount := w.transferAmount()
+	txID, err := account.client.XChainAPI().Send(account.user, nil, "", amount, "AVAX", to.xChainAddress, "")
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to send %d AVAX from %s to %s", amount, account, to)
+	}
+	_, err = w.executor.await(account, fmt.Sprintf("X Chain transfer %s from %s", txID, account), xChainTxCheck(account.client, txID))
+	return err
+}
+
+func (w *worker) operateAsset(account *soakAccount) error {
+	if !account.hasAsset {
+		minters := []avm.Owners{{
+			Threshold: 1,
+			Minters:   []string{account.xChainAddress},
+		}}
+		assetID, err := account.client.XChainAPI().CreateVariableCapAsset(
+			account.user,
+			nil, // from addrs
+			"",  // change addr
+			fmt.Sprintf("Soak Asset %d", account.Index),
+			"SOAK",
+			0,
+			minters,
+		)
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to create asset of %s", account)
+		}
+		accepted, err := w.executor.await(account, fmt.Sprintf("asset creation %s by %s", assetID, account), xChainTxCheck(account.client, assetID))
+		if err != nil || !accepted {
+			return err
+		}
+		account.assetID = assetID
+		account.hasAsset = true
+		return nil
+	}
+
+	to := w.executor.accounts[w.rng.Intn(len(w.executor.accounts))]
+	amount := uint64(1 + w.rng.Intn(1000))
+	txID, err := account.client.XChainAPI().Mint(account.user, nil, "", amount, account.assetID.String(), to.xChainAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to mint asset %s of %s to %s", account.assetID, account, to)
+	}
+	_, err = w.executor.await(account, fmt.Sprintf("mint %s of asset %s by %s", txID, account.assetID, account), xChainTxCheck(account.client, txID))
+	return err
+}
+
+func (w *worker) transferBetweenXAndP(account *soakAccount) error {
+	unlocked, err := account.pChainUnlockedBalance()
+	if err != nil {
+		return err
+	}
+	if unlocked >= atomicTransferAmount+w.executor.params.TxFee && w.rng.Intn(2) == 0 {
+		_, err := w.transferPToX(account, atomicTransferAmount)
+		return err
+	}
+	_, err = w.transferXToP(account, atomicTransferAmount)
+	return err
+}
+
+func (w *worker) transferBetweenXAndC(account *soakAccount) error {
+	balance, err := account.cChainBalance()
+	if err != nil {
+		return err
+	}
+	if balance >= atomicTransferAmount+w.executor.params.TxFee && w.rng.Intn(2) == 0 {
+		_, err := w.transferCToX(account, atomicTransferAmount)
+		return err
+	}
+	_, err = w.transferXToC(account, atomicTransferAmount)
+	return err
+}
+
+func (w *worker) delegate(account *soakAccount) error {
+	// The delegation and the import of its stake each pay a fee
+	unlocked, err := account.pChainUnlockedBalance()
+	if err != nil {
+		return err
+	}
+	if unlocked < delegationStake+w.executor.params.TxFee {
+		accepted, err := w.transferXToP(account, delegationStake+2*w.executor.params.TxFee)
+		if err != nil || !accepted {
+			return err
+		}
+	}
+
+	nodeID := w.executor.nodeIDs[w.rng.Intn(len(w.executor.nodeIDs))]
+	startTime := time.Now().Add(helpers.DefaultDelegationDelay)
+	txID, err := account.client.PChainAPI().AddDelegator(
+		account.user,
+		nil, // from addrs
+		"",  // change addr
+		account.pChainAddress,
+		nodeID,
+		delegationStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(delegationDuration).Unix()),
+	)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to delegate from %s to %s", account, nodeID)
+	}
+	_, err = w.executor.await(account, fmt.Sprintf("delegation %s from %s to %s", txID, account, nodeID), pChainTxCheck(account.client, txID))
+	return err
+}
+
+func (w *worker) transferOnCChain(account *soakAccount) error {
+	ethClient, err := account.client.CChainEthAPI()
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to connect to the C Chain of %s", account.serviceID)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ethRequestTimeout)
+	defer cancel()
+	gasPrice, err := ethClient.SuggestGasPrice(ctx)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to get gas price")
+	}
+
+	// Top up the account's C Chain balance if it can't pay for the transfer
+	value := new(big.Int).SetUint64(w.transferAmount() * x2cConversion)
+	cost := new(big.Int).Mul(gasPrice, big.NewInt(evmTransferGas))
+	cost.Add(cost, value)
+	balance, err := account.cChainBalance()
+	if err != nil {
+		return err
+	}
+	if new(big.Int).SetUint64(balance*x2cConversion).Cmp(cost) < 0 {
+		accepted, err := w.transferXToC(account, atomicTransferAmount)
+		if err != nil || !accepted {
+			return err
+		}
+	}
+
+	// Transfers only go to accounts of this worker, so that the C Chain balance of an account only changes while an
+	// operation is issued from it
+	to := w.accounts[w.rng.Intn(len(w.accounts))]
+	nonce, err := ethClient.NonceAt(ctx, account.cChainHexAddress, nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to get nonce of %s", account)
+	}
+	tx, err := types.SignTx(
+		types.NewTransaction(nonce, to.cChainHexAddress, value, evmTransferGas, gasPrice, nil),
+		w.executor.signer,
+		account.Key.ToECDSA(),
+	)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to sign EVM transfer from %s", account)
+	}
+	if err := ethClient.SendTransaction(ctx, tx); err != nil {
+		return stacktrace.Propagate(err, "Failed to issue EVM transfer from %s to %s", account, to)
+	}
+	_, err = w.executor.await(account, fmt.Sprintf("EVM transfer %s from %s", tx.Hash().Hex(), account), func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), ethRequestTimeout)
+		defer cancel()
+		receipt, err := ethClient.TransactionReceipt(ctx, tx.Hash())
+		if err == coreth.NotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return false, stacktrace.NewError("EVM transfer %s failed in block %d", tx.Hash().Hex(), receipt.BlockNumber)
+		}
+		return true, nil
+	})
+	return err
+}
+
+// transferXToP exports [amount] AVAX of [account] from the X Chain and imports it to the P Chain, returning whether
+// both transactions were accepted
+func (w *worker) transferXToP(account *soakAccount, amount uint64) (bool, error) {
+	exportTxID, err := account.client.XChainAPI().ExportAVAX(account.user, nil, "", amount, account.pChainAddress)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to export AVAX of %s to the P Chain", account)
+	}
+	accepted, err := w.executor.await(account, fmt.Sprintf("X to P export %s of %s", exportTxID, account), xChainTxCheck(account.client, exportTxID))
+	if err != nil || !accepted {
+		return false, err
+	}
+	importTxID, err := account.client.PChainAPI().ImportAVAX(account.user, nil, "", account.pChainAddress, services.XChain)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to import AVAX of %s to the P Chain", account)
+	}
+	return w.executor.await(account, fmt.Sprintf("X to P import %s of %s", importTxID, account), pChainTxCheck(account.client, importTxID))
+}
+
+// transferPToX exports [amount] AVAX of [account] from the P Chain and imports it to the X Chain, returning whether
+// both transactions were accepted
+func (w *worker) transferPToX(account *soakAccount, amount uint64) (bool, error) {
+	exportTxID, err := account.client.PChainAPI().ExportAVAX(nil, "", account.user, account.xChainAddress, amount)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to export AVAX of %s to the X Chain", account)
+	}
+	accepted, err := w.executor.await(account, fmt.Sprintf("P to X export %s of %s", exportTxID, account), pChainTxCheck(account.client, exportTxID))
+	if err != nil || !accepted {
+		return false, err
+	}
+	importTxID, err := account.client.XChainAPI().ImportAVAX(account.user, account.xChainAddress, services.PChain)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to import AVAX of %s to the X Chain", account)
+	}
+	return w.executor.await(account, fmt.Sprintf("P to X import %s of %s", importTxID, account), xChainTxCheck(account.client, importTxID))
+}
+
+// transferXToC exports [amount] AVAX of [account] from the X Chain and imports it to the C Chain, returning whether
+// both transactions were accepted. The C Chain has no API for the status of atomic transactions, so the import is
+// accepted once the account's C Chain balance grows.
+func (w *worker) transferXToC(account *soakAccount, amount uint64) (bool, error) {
+	exportTxID, err := account.client.XChainAPI().ExportAVAX(account.user, nil, "", amount, account.cChainBech32Address)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to export AVAX of %s to the C Chain", account)
+	}
+	accepted, err := w.executor.await(account, fmt.Sprintf("X to C export %s of %s", exportTxID, account), xChainTxCheck(account.client, exportTxID))
+	if err != nil || !accepted {
+		return false, err
+	}
+	balance, err := account.cChainBalance()
+	if err != nil {
+		return false, err
+	}
+	importTxID, err := account.client.CChainAPI().Import(account.user, account.cChainHexAddress.Hex(), services.XChain)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to import AVAX of %s to the C Chain", account)
+	}
+	return w.executor.await(account, fmt.Sprintf("X to C import %s of %s", importTxID, account), cChainBalanceCheck(account, func(newBalance uint64) bool {
+		return newBalance > balance
+	}))
+}
+
+// transferCToX exports [amount] AVAX of [account] from the C Chain and imports it to the X Chain, returning whether
+// both transactions were accepted. The export is accepted once the account's C Chain balance shrinks.
+func (w *worker) transferCToX(account *soakAccount, amount uint64) (bool, error) {
+	balance, err := account.cChainBalance()
+	if err != nil {
+		return false, err
+	}
+	exportTxID, err := account.client.CChainAPI().ExportAVAX(account.user, amount, account.xChainAddress)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to export AVAX of %s to the X Chain", account)
+	}
+	accepted, err := w.executor.await(account, fmt.Sprintf("C to X export %s of %s", exportTxID, account), cChainBalanceCheck(account, func(newBalance uint64) bool {
+		return newBalance < balance
+	}))
+	if err != nil || !accepted {
+		return false, err
+	}
+	importTxID, err := account.client.XChainAPI().ImportAVAX(account.user, account.xChainAddress, services.CChain)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to import AVAX of %s to the X Chain", account)
+	}
+	return w.executor.await(account, fmt.Sprintf("C to X import %s of %s", importTxID, account), xChainTxCheck(account.client, importTxID))
+}
+
+// transferAmount returns a random amount of AVAX to send in an X Chain or EVM transfer
+func (w *worker) transferAmount() uint64 {
+	return uint64(1+w.rng.Intn(maxTransferMilliAvax)) * units.MilliAvax
+}
+
+// xChainBalance returns the AVAX balance of [account] on the X Chain, as seen by its node
+func (account *soakAccount) xChainBalance() (uint64, error) {
+	reply, err := account.client.XChainAPI().GetBalance(account.xChainAddress, "AVAX")
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to get X Chain balance of %s", account)
+	}
+	return uint64(reply.Balance), nil
+}
+
+// pChainBalance returns the balance of [account] on the P Chain, including staked AVAX, as seen by its node
+func (account *soakAccount) pChainBalance() (uint64, error) {
+	reply, err := account.client.PChainAPI().GetBalance(account.pChainAddress)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to get P Chain balance of %s", account)
+	}
+	return uint64(reply.Balance), nil
+}
+
+// pChainUnlockedBalance returns the balance of [account] on the P Chain that it can spend, as seen by its node
+func (account *soakAccount) pChainUnlockedBalance() (uint64, error) {
+	reply, err := account.client.PChainAPI().GetBalance(account.pChainAddress)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to get P Chain balance of %s", account)
+	}
+	return uint64(reply.Unlocked), nil
+}
+
+// cChainBalance returns the balance of [account] on the C Chain in nAVAX, as seen by its node
+func (account *soakAccount) cChainBalance() (uint64, error) {
+	return cChainBalanceOn(account.client, account.cChainHexAddress)
+}
+
+func cChainBalanceOn(client *services.Client, address common.Address) (uint64, error) {
+	ethClient, err := client.CChainEthAPI()
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to connect to the C Chain")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ethRequestTimeout)
+	defer cancel()
+	balance, err := ethClient.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to get C Chain balance of %s", address.Hex())
+	}
+	return new(big.Int).Div(balance, big.NewInt(x2cConversion)).Uint64(), nil
+}
+
+func xChainTxCheck(client *services.Client, txID ids.ID) txCheck {
+	return func() (bool, error) {
+		status, err := client.XChainAPI().GetTxStatus(txID)
+		if err != nil {
+			return false, stacktrace.Propagate(err, "Failed to get status of X Chain transaction %s", txID)
+		}
+		if status == choices.Rejected {
+			return false, stacktrace.NewError("X Chain transaction %s was rejected", txID)
+		}
+		return status == choices.Accepted, nil
+	}
+}
+
+func pChainTxCheck(client *services.Client, txID ids.ID) txCheck {
+	return func() (bool, error) {
+		reply, err := client.PChainAPI().GetTxStatus(txID, true)
+		if err != nil {
+			return false, stacktrace.Propagate(err, "Failed to get status of P Chain transaction %s", txID)
+		}
+		if reply.Status == platformvm.Aborted || reply.Status == platformvm.Dropped {
+			return false, stacktrace.NewError("P Chain transaction %s has status %s. Reason: %s", txID, reply.Status, reply.Reason)
+		}
+		return reply.Status == platformvm.Committed, nil
+	}
+}
+
+func cChainBalanceCheck(account *soakAccount, accepted func(balance uint64) bool) txCheck {
+	return func() (bool, error) {
+		balance, err := account.cChainBalance()
+		if err != nil {
+			return false, err
+		}
+		return accepted(balance), nil
+	}
+}
+
+// await polls [check] until the transaction it checks is accepted, returning true, or the acceptance timeout
+// elapses. A transaction that isn't accepted in time is recorded as suspected to be stuck, and its account is
+// quarantined.
+func (e *executor) await(account *soakAccount, description string, check txCheck) (bool, error) {
+	deadline := time.Now().Add(e.params.AcceptanceTimeout)
+	for {
+		accepted, err := check()
+		if err != nil {
+			return false, stacktrace.Propagate(err, "Failed to confirm %s", description)
+		}
+		if accepted {
+			logrus.Tracef("Accepted %s", description)
+			return true, nil
+		}
+		if time.Now().After(deadline) {
+			e.suspect(account, description, check)
+			return false, nil
+		}
+		time.Sleep(txPollInterval)
+	}
+}
@@ -0,0 +1,301 @@
+package soak
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-go/lib/testsuite"
+
+	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
+	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
+	"github.com/ava-labs/avalanche-testing/testsuite/chaos"
+	"github.com/ava-labs/avalanche-testing/testsuite/monitoring"
+	"github.com/ava-labs/avalanche-testing/testsuite/profiling"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Time between polls of the health of the nodes
+	healthPollInterval = 10 * time.Second
+
+	// The execution timeout is the duration of the workload plus the time to fund the accounts and run the final checks
+	executionTimeoutMargin = 20 * time.Minute
+
+	// Configuration of the nodes that aren't validators, which faults are injected into while the workload runs
+	chaosNodeConfigID networks.ConfigurationID = "chaos-node-config"
+
+	// Prefix of the service IDs of the nodes faults are injected into
+	chaosNodeServiceIDPrefix = "chaos-node-"
+
+	// How long a node that's added back is expected to take to bootstrap
+	chaosRecoveryTime = 2 * time.Minute
+
+	// Name of the directory in the suite's results directory that the chaos schedule is written to
+	chaosResultsName = "soakTest"
+
+	// Name of the chaos schedule in the results directory, which can be passed as ChaosSchedule to replay it
+	chaosScheduleFilename = "chaos_schedule.json"
+)
+
+// Params are the parameters of the soak test
+type Params struct {
+	// How long the workload is issued for
+	Duration time.Duration
+
+	// Number of accounts the workload is issued from
+	NumAccounts int
+
+	// Number of goroutines issuing the workload, each from its own subset of the accounts
+	NumWorkers int
+
+	// nAVAX each account is funded with on the X Chain
+	AccountFunding uint64
+
+	// Relative weights of the operations the workload is made of. An operation with a weight of 0 is never issued.
+	XChainTransferWeight int
+	AssetWeight          int
+	XPTransferWeight     int
+	XCTransferWeight     int
+	StakingWeight        int
+	EVMTransferWeight    int
+
+	// Time between checks of the accounts' balances, the nodes' agreement on them, and the nodes' health and memory
+	CheckInterval time.Duration
+
+	// Seed of the accounts' keys and of the workload's random choices, so that a failing run can be reproduced
+	Seed int64
+
+	// Fee of X Chain transactions on the test network
+	TxFee uint64
+
+	// How long to wait for each transaction to be accepted before it's suspected to be stuck
+	AcceptanceTimeout time.Duration
+
+	// Number of nodes that aren't validators which are removed and added back while the workload runs. The workload
+	// is only issued to the boot nodes, so it doesn't depend on them.
+	NumChaosNodes int
+
+	// Mean time between faults injected into the chaos nodes, or 0 to inject none
+	ChaosMeanInterval time.Duration
+
+	// Bounds of how long each chaos node stays removed
+	ChaosMinFaultDuration time.Duration
+	ChaosMaxFaultDuration time.Duration
+
+	// Path of a schedule written by an earlier run, to inject its faults again instead of generating them from Seed
+	ChaosSchedule string
+}
+
+// DefaultParams returns the parameters the soak test runs with unless they're overridden
+func DefaultParams() Params {
+	return Params{
+		Duration:              2 * time.Hour,
+		NumAccounts:           20,
+		NumWorkers:            10,
+		AccountFunding:        10 * units.KiloAvax,
+		XChainTransferWeight:  10,
+		AssetWeight:           3,
+		XPTransferWeight:      2,
+		XCTransferWeight:      2,
+		StakingWeight:         1,
+		EVMTransferWeight:     6,
+		CheckInterval:         10 * time.Minute,
+		Seed:                  1,
+		TxFee:                 1000000,
+		AcceptanceTimeout:     30 * time.Second,
+		NumChaosNodes:         2,
+		ChaosMeanInterval:     10 * time.Minute,
+		ChaosMinFaultDuration: time.Minute,
+		ChaosMaxFaultDuration: 5 * time.Minute,
+	}
+}
+
+// Validate implements the params.Validator interface
+func (p Params) Validate() error {
+	if p.Duration <= 0 || p.CheckInterval <= 0 || p.AcceptanceTimeout <= 0 {
+		return stacktrace.NewError("Duration, CheckInterval and AcceptanceTimeout must be positive")
+	}
+	if p.NumWorkers <= 0 || p.NumAccounts < p.NumWorkers {
+		return stacktrace.NewError("NumWorkers must be positive, and NumAccounts at least NumWorkers")
+	}
+	if p.AccountFunding < minAccountFunding {
+		return stacktrace.NewError("AccountFunding must be at least %d", minAccountFunding)
+	}
+	if _, err := newOperationPicker(p.weights()); err != nil {
+		return stacktrace.Propagate(err, "Invalid operation weights")
+	}
+	if p.NumChaosNodes < 0 {
+		return stacktrace.NewError("NumChaosNodes must not be negative")
+	}
+	if p.injectsFaults() && p.ChaosSchedule == "" {
+		if err := p.chaosScheduleConfig(0).Validate(); err != nil {
+			return stacktrace.Propagate(err, "Invalid chaos parameters")
+		}
+	}
+	return nil
+}
+
+// injectsFaults returns whether faults are injected into the chaos nodes
+func (p Params) injectsFaults() bool {
+	return p.NumChaosNodes > 0 && (p.ChaosMeanInterval > 0 || p.ChaosSchedule != "")
+}
+
+// chaosScheduleConfig returns the config the chaos schedule is generated from, given the stake of all the validators
+func (p Params) chaosScheduleConfig(totalStake uint64) chaos.ScheduleConfig {
+	return chaos.ScheduleConfig{
+		Seed:             p.Seed,
+		Duration:         p.Duration,
+		MeanInterval:     p.ChaosMeanInterval,
+		MinFaultDuration: p.ChaosMinFaultDuration,
+		MaxFaultDuration: p.ChaosMaxFaultDuration,
+		RecoveryTime:     chaosRecoveryTime,
+		Kinds:            []chaos.FaultKind{chaos.ReaddFault},
+		// Only nodes that aren't validators are faulted, so none of the stake may be
+		MaxFaultedStakeFraction: 0,
+		TotalStake:              totalStake,
+	}
+}
+
+// chaosNodeConfigIDs returns the configuration of each chaos node, keyed by service ID
+func (p Params) chaosNodeConfigIDs() map[networks.ServiceID]networks.ConfigurationID {
+	result := make(map[networks.ServiceID]networks.ConfigurationID, p.NumChaosNodes)
+	for i := 0; i < p.NumChaosNodes; i++ {
+		result[networks.ServiceID(fmt.Sprintf("%s%d", chaosNodeServiceIDPrefix, i))] = chaosNodeConfigID
+	}
+	return result
+}
+
+// weights returns the weight of each operation
+func (p Params) weights() map[operation]int {
+	return map[operation]int{
+		xChainTransfer: p.XChainTransferWeight,
+		assetOperation: p.AssetWeight,
+		xpTransfer:     p.XPTransferWeight,
+		xcTransfer:     p.XCTransferWeight,
+		staking:        p.StakingWeight,
+		evmTransfer:    p.EVMTransferWeight,
+	}
+}
+
+// StakingNetworkSoakTest issues a weighted random mix of X, P, and C Chain transactions from many accounts for hours,
+// periodically checking that the nodes agree on the accounts' balances and stay healthy, and reports how the nodes'
+// memory grew and any transactions that never left Processing. Meanwhile, nodes that aren't validators are removed and
+// added back from a seeded schedule.
+type StakingNetworkSoakTest struct {
+	ImageName string
+	Params
+}
+
+// Run implements the Kurtosis Test interface
+func (test StakingNetworkSoakTest) Run(network networks.Network, context testsuite.TestContext) {
+	castedNetwork := network.(avalancheNetwork.TestAvalancheNetwork)
+	bootServiceIDs := castedNetwork.GetAllBootServiceIDs()
+	clients, err := castedNetwork.GetAvalancheClients(bootServiceIDs)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to get clients of the boot nodes."))
+	}
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+
+	profiler, err := profiling.NewNodeProfiler(castedNetwork, bootServiceIDs)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to create node profiler."))
+	}
+	monitor := monitoring.NewHealthMonitor(castedNetwork, healthPollInterval)
+	defer monitor.Stop()
+	for serviceID := range bootServiceIDs {
+		if err := monitor.Watch(serviceID); err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Failed to watch the health of %s.", serviceID))
+		}
+	}
+
+	var engine *chaos.Engine
+	if test.injectsFaults() {
+		if engine, err = test.newChaosEngine(castedNetwork); err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Failed to create chaos engine."))
+		}
+		if err := engine.Start(filepath.Join(avalancheService.ResultsDirpath(chaosResultsName), chaosScheduleFilename)); err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Failed to start chaos engine."))
+		}
+	}
+
+	executor := NewSoakExecutor(clients, profiler, monitor, test.Params)
+	logrus.Infof("Executing soak test for %s...", test.Duration)
+	executionErr := executor.ExecuteTest()
+	if engine != nil {
+		if err := engine.Stop(); err != nil && executionErr == nil {
+			executionErr = stacktrace.Propagate(err, "Failed to inject or recover from a fault.")
+		}
+	}
+	if executionErr != nil {
+		context.Fatal(stacktrace.Propagate(executionErr, "Soak test failed."))
+	}
+	logrus.Infof("Soak test completed successfully.")
+}
+
+// newChaosEngine returns an engine that injects faults into the chaos nodes of [network], from the schedule at
+// ChaosSchedule if it's set and from a schedule generated from Seed otherwise
+func (test StakingNetworkSoakTest) newChaosEngine(network avalancheNetwork.TestAvalancheNetwork) (*chaos.Engine, error) {
+	targets, totalStake, err := chaos.Targets(network, test.chaosNodeConfigIDs())
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get the chaos nodes as targets")
+	}
+	config := test.chaosScheduleConfig(totalStake)
+	var schedule chaos.Schedule
+	if test.ChaosSchedule != "" {
+		schedule, err = chaos.ReadSchedule(test.ChaosSchedule)
+	} else {
+		schedule, err = chaos.NewSchedule(config, targets)
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get chaos schedule")
+	}
+	return chaos.NewEngine(network, targets, schedule, config.MaxFaultedStakeFraction, totalStake)
+}
+
+// GetNetworkLoader implements the Kurtosis Test interface
+func (test StakingNetworkSoakTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	serviceConfigs := map[networks.ConfigurationID]avalancheNetwork.TestAvalancheNetworkServiceConfig{
+		chaosNodeConfigID: *avalancheNetwork.NewTestAvalancheNetworkServiceConfig(
+			true,
+			avalancheService.INFO,
+			test.ImageName,
+			2,
+			2,
+			2*time.Second,
+			make(map[string]string),
+		),
+	}
+	// The nodes' files are exposed so that their heap profiles can be read
+	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
+		true,
+		test.ImageName,
+		avalancheService.INFO,
+		2,
+		2,
+		test.TxFee,
+		2*time.Second,
+		true,
+		serviceConfigs,
+		test.chaosNodeConfigIDs(),
+	)
+}
+
+// GetExecutionTimeout implements the Kurtosis Test interface
+func (test StakingNetworkSoakTest) GetExecutionTimeout() time.Duration {
+	return test.Duration + executionTimeoutMargin
+}
+
+// GetSetupBuffer implements the Kurtosis Test interface
+func (test StakingNetworkSoakTest) GetSetupBuffer() time.Duration {
+	// Each chaos node is started after the boot nodes, and waited for while it bootstraps
+	return 2*time.Minute + time.Duration(test.NumChaosNodes)*time.Minute
+}
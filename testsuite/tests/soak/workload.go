@@ -0,0 +1,76 @@
+package soak
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/palantir/stacktrace"
+)
+
+// operation is a kind of transaction, or short sequence of transactions, that the workload issues from an account
+type operation string
+
+const (
+	// Sends AVAX to another account on the X Chain
+	xChainTransfer operation = "xChainTransfer"
+
+	// Creates a variable cap asset minted by the account, or mints more of it to another account
+	assetOperation operation = "asset"
+
+	// Moves AVAX from the X Chain to the P Chain, or back, with an export and an import
+	xpTransfer operation = "xpTransfer"
+
+	// Moves AVAX from the X Chain to the C Chain, or back, with an export and an import
+	xcTransfer operation = "xcTransfer"
+
+	// Delegates to a boot node from the account's P Chain funds
+	staking operation = "staking"
+
+	// Sends AVAX to another account with an EVM transaction on the C Chain
+	evmTransfer operation = "evmTransfer"
+)
+
+// operationPicker picks operations at random, each with a probability proportional to its weight
+type operationPicker struct {
+	operations []operation
+
+	// The sum of the weights of operations[0..i]
+	cumulativeWeights []int
+}
+
+// newOperationPicker returns a picker of the operations in [weights] with a positive weight, returning an error if
+// a weight is negative or none is positive
+func newOperationPicker(weights map[operation]int) (*operationPicker, error) {
+	// Sorted so that the same seed always picks the same operations
+	operations := make([]operation, 0, len(weights))
+	for op, weight := range weights {
+		if weight < 0 {
+			return nil, stacktrace.NewError("Weight of %s is negative", op)
+		}
+		if weight > 0 {
+			operations = append(operations, op)
+		}
+	}
+	if len(operations) == 0 {
+		return nil, stacktrace.NewError("No operation has a positive weight")
+	}
+	sort.Slice(operations, func(i, j int) bool { return operations[i] < operations[j] })
+
+	cumulativeWeights := make([]int, len(operations))
+	total := 0
+	for i, op := range operations {
+		total += weights[op]
+		cumulativeWeights[i] = total
+	}
+	return &operationPicker{
+		operations:        operations,
+		cumulativeWeights: cumulativeWeights,
+	}, nil
+}
+
+// pick returns an operation chosen with [rng]
+func (picker *operationPicker) pick(rng *rand.Rand) operation {
+	target := rng.Intn(picker.cumulativeWeights[len(picker.cumulativeWeights)-1])
+	i := sort.Search(len(picker.cumulativeWeights), func(i int) bool { return picker.cumulativeWeights[i] > target })
+	return picker.operations[i]
+}
@@ -0,0 +1,53 @@
+package soak
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationPickerFollowsWeights(t *testing.T) {
+	picker, err := newOperationPicker(map[operation]int{
+		xChainTransfer: 3,
+		evmTransfer:    1,
+		staking:        0,
+	})
+	assert.NoError(t, err)
+
+	rng := rand.New(rand.NewSource(1))
+	counts := make(map[operation]int)
+	for i := 0; i < 40000; i++ {
+		counts[picker.pick(rng)]++
+	}
+	assert.Equal(t, 0, counts[staking], "Expected an operation with weight 0 never to be picked")
+	assert.InDelta(t, 30000, counts[xChainTransfer], 1000)
+	assert.InDelta(t, 10000, counts[evmTransfer], 1000)
+}
+
+func TestOperationPickerRejectsInvalidWeights(t *testing.T) {
+	_, err := newOperationPicker(map[operation]int{xChainTransfer: 0})
+	assert.Error(t, err)
+	_, err = newOperationPicker(map[operation]int{xChainTransfer: 1, staking: -1})
+	assert.Error(t, err)
+}
+
+func TestParamsValidate(t *testing.T) {
+	assert.NoError(t, DefaultParams().Validate())
+
+	params := DefaultParams()
+	params.NumAccounts = params.NumWorkers - 1
+	assert.Error(t, params.Validate())
+
+	params = DefaultParams()
+	params.XChainTransferWeight = -1
+	assert.Error(t, params.Validate())
+
+	params = DefaultParams()
+	params.ChaosMaxFaultDuration = params.ChaosMinFaultDuration - 1
+	assert.Error(t, params.Validate())
+
+	// The chaos parameters are ignored when no faults are injected
+	params.NumChaosNodes = 0
+	assert.NoError(t, params.Validate())
+}
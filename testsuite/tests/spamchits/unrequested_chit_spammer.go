@@ -10,6 +10,7 @@ import (
 	avalancheNetwork "github.com/ava-labs/avalanche-testing/avalanche/networks"
 	avalancheService "github.com/ava-labs/avalanche-testing/avalanche/services"
 	"github.com/ava-labs/avalanche-testing/testsuite/helpers"
+	"github.com/ava-labs/avalanche-testing/testsuite/monitoring"
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/palantir/stacktrace"
@@ -32,6 +33,13 @@ const (
 	networkAcceptanceTimeoutRatio = 0.3
 	byzantineBehavior             = "byzantine-behavior"
 	chitSpammerBehavior           = "chit-spammer"
+	healthPollInterval            = 2 * time.Second
+
+	// Name of the directory in the suite's results directory that node logs are saved to if the test fails
+	logResultsName = "chitSpammerTest"
+
+	// Name of the snapshot of the network once the byzantine nodes are validators
+	byzantineStakersSnapshotName = "byzantine-chit-spammer-stakers"
 )
 
 // StakingNetworkUnrequestedChitSpammerTest tests that a node is able to continue to work normally
@@ -46,26 +54,53 @@ func (test StakingNetworkUnrequestedChitSpammerTest) Run(network networks.Networ
 	castedNetwork := network.(avalancheNetwork.TestAvalancheNetwork)
 	networkAcceptanceTimeout := time.Duration(networkAcceptanceTimeoutRatio * float64(test.GetExecutionTimeout().Nanoseconds()))
 
-	// ============= ADD SET OF BYZANTINE NODES AS VALIDATORS ON THE NETWORK ===================
-	logrus.Infof("Adding byzantine chit spammer nodes as stakers...")
+	// Every node other than the byzantine nodes is honest, and must stay healthy despite the spam
+	honestServiceIDs := castedNetwork.GetAllBootServiceIDs()
+	byzantineServiceIDs := make([]networks.ServiceID, 0, numberOfByzantineNodes)
 	for i := 0; i < numberOfByzantineNodes; i++ {
-		byzClient, err := castedNetwork.GetAvalancheClient(networks.ServiceID(byzantineNodePrefix + strconv.Itoa(i)))
-		if err != nil {
-			context.Fatal(stacktrace.Propagate(err, "Failed to get byzantine client."))
-		}
-		highLevelByzClient := helpers.NewRPCWorkFlowRunner(
-			byzClient,
-			api.UserPass{Username: byzantineUsername, Password: byzantinePassword},
-			networkAcceptanceTimeout)
-		_, err = highLevelByzClient.ImportGenesisFundsAndStartValidating(seedAmount, stakeAmount)
-		if err != nil {
-			context.Fatal(stacktrace.Propagate(err, "Failed add client as a validator."))
-		}
-		currentStakers, err := byzClient.PChainAPI().GetCurrentValidators(ids.Empty)
-		if err != nil {
-			context.Fatal(stacktrace.Propagate(err, "Could not get current stakers."))
+		byzantineServiceIDs = append(byzantineServiceIDs, networks.ServiceID(byzantineNodePrefix+strconv.Itoa(i)))
+	}
+
+	// ============= ADD SET OF BYZANTINE NODES AS VALIDATORS ON THE NETWORK ===================
+	// Adding the byzantine validators is only done once per snapshot, and the nodes are restarted from the snapshot
+	// afterwards, so the nodes are only monitored from then on
+	err := castedNetwork.SetUpFromSnapshot(func() error {
+		logrus.Infof("Adding byzantine chit spammer nodes as stakers...")
+		for _, byzantineServiceID := range byzantineServiceIDs {
+			byzClient, err := castedNetwork.GetAvalancheClient(byzantineServiceID)
+			if err != nil {
+				return stacktrace.Propagate(err, "Failed to get byzantine client.")
+			}
+			highLevelByzClient := helpers.NewRPCWorkFlowRunner(
+				byzClient,
+				api.UserPass{Username: byzantineUsername, Password: byzantinePassword},
+				networkAcceptanceTimeout)
+			_, err = highLevelByzClient.ImportGenesisFundsAndStartValidating(seedAmount, stakeAmount)
+			if err != nil {
+				return stacktrace.Propagate(err, "Failed add client as a validator.")
+			}
+			currentStakers, err := byzClient.PChainAPI().GetCurrentValidators(ids.Empty)
+			if err != nil {
+				return stacktrace.Propagate(err, "Could not get current stakers.")
+			}
+			logrus.Infof("Current Stakers: %d", len(currentStakers))
 		}
-		logrus.Infof("Current Stakers: %d", len(currentStakers))
+		return nil
+	})
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to add the byzantine nodes as stakers."))
+	}
+
+	logInspector := monitoring.NewLogInspector(castedNetwork)
+	defer logInspector.SaveOnFailure(avalancheService.ResultsDirpath(logResultsName))
+	if err := logInspector.Inspect(append(serviceIDList(honestServiceIDs), byzantineServiceIDs...)...); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to inspect the logs of the network's nodes."))
+	}
+
+	healthMonitor := monitoring.NewHealthMonitor(castedNetwork, healthPollInterval)
+	defer healthMonitor.Stop()
+	if err := healthMonitor.Watch(serviceIDList(honestServiceIDs)...); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to monitor the health of the boot nodes."))
 	}
 
 	// =================== ADD NORMAL NODE AS A VALIDATOR ON THE NETWORK =======================
@@ -81,6 +116,13 @@ func (test StakingNetworkUnrequestedChitSpammerTest) Run(network networks.Networ
 	if err != nil {
 		context.Fatal(stacktrace.Propagate(err, "Failed to get staker client."))
 	}
+	honestServiceIDs[normalNodeServiceID] = true
+	if err := healthMonitor.Watch(normalNodeServiceID); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to monitor the health of the normal node."))
+	}
+	if err := logInspector.Inspect(normalNodeServiceID); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Failed to inspect the logs of the normal node."))
+	}
 	highLevelNormalClient := helpers.NewRPCWorkFlowRunner(
 		normalClient,
 		api.UserPass{Username: stakerUsername, Password: stakerPassword},
@@ -105,6 +147,22 @@ func (test StakingNetworkUnrequestedChitSpammerTest) Run(network networks.Networ
 	if actualNumStakers != expectedNumStakers {
 		context.AssertTrue(actualNumStakers == expectedNumStakers, stacktrace.NewError("Actual number of stakers, %v, != expected number of stakers, %v", actualNumStakers, expectedNumStakers))
 	}
+
+	healthMonitor.Stop()
+	if err := healthMonitor.AssertNeverUnhealthy(honestServiceIDs); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Byzantine chit spammers degraded the health of honest nodes."))
+	}
+	if err := logInspector.AssertNeverAppears(honestServiceIDs, monitoring.FatalLogPattern); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Honest nodes logged fatal errors while being spammed with chits."))
+	}
+}
+
+func serviceIDList(serviceIDs map[networks.ServiceID]bool) []networks.ServiceID {
+	list := make([]networks.ServiceID, 0, len(serviceIDs))
+	for serviceID := range serviceIDs {
+		list = append(list, serviceID)
+	}
+	return list
 }
 
 // GetNetworkLoader implements the Kurtosis Test interface
@@ -141,7 +199,8 @@ func (test StakingNetworkUnrequestedChitSpammerTest) GetNetworkLoader() (network
 	logrus.Debugf("Byzantine Image Name: %s", test.ByzantineImageName)
 	logrus.Debugf("Normal Image Name: %s", test.NormalImageName)
 
-	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+	loader, err := avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
 		true,
 		test.NormalImageName,
 		avalancheService.DEBUG,
@@ -149,9 +208,18 @@ func (test StakingNetworkUnrequestedChitSpammerTest) GetNetworkLoader() (network
 		2,
 		0,
 		2*time.Second,
+		true,
 		serviceConfigs,
 		serviceIDConfigMap,
 	)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to create the network loader")
+	}
+	// The normal node is added after the snapshot is taken, so it isn't part of it
+	if err := loader.UseSnapshot(byzantineStakersSnapshotName, serviceIDConfigMap); err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to use the byzantine stakers snapshot")
+	}
+	return loader, nil
 }
 
 // GetExecutionTimeout implements the Kurtosis Test interface
@@ -9,6 +9,7 @@ import (
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
 )
@@ -27,14 +28,20 @@ const (
 
 type executor struct {
 	stakerClient, delegatorClient *services.Client
-	acceptanceTimeout             time.Duration
+	// Clients for every node in the network, used to verify that balances converge across all of them
+	allClients        map[networks.ServiceID]*services.Client
+	acceptanceTimeout time.Duration
 }
 
 // NewRPCWorkflowTestExecutor ...
-func NewRPCWorkflowTestExecutor(stakerClient, delegatorClient *services.Client, acceptanceTimeout time.Duration) tester.AvalancheTester {
+func NewRPCWorkflowTestExecutor(
+	stakerClient, delegatorClient *services.Client,
+	allClients map[networks.ServiceID]*services.Client,
+	acceptanceTimeout time.Duration) tester.AvalancheTester {
 	return &executor{
 		stakerClient:      stakerClient,
 		delegatorClient:   delegatorClient,
+		allClients:        allClients,
 		acceptanceTimeout: acceptanceTimeout,
 	}
 }
@@ -175,5 +182,17 @@ func (e *executor) ExecuteTest() error {
 	}
 	logrus.Infof("Transferred leftover delegator funds back to X Chain and verified X and P balances.")
 
+	// ====================================== VERIFY CONVERGENCE =================================
+	if err := highLevelStakerClient.VerifyXChainAVABalanceOnAllNodes(e.allClients, stakerXChainAddress, expectedStakerBalance); err != nil {
+		return stacktrace.Propagate(err, "Nodes did not converge on the staker's X Chain balance.")
+	}
+	if err := highLevelDelegatorClient.VerifyXChainAVABalanceOnAllNodes(e.allClients, delegatorXChainAddress, expectedDelegatorBalance); err != nil {
+		return stacktrace.Propagate(err, "Nodes did not converge on the delegator's X Chain balance.")
+	}
+	if err := highLevelStakerClient.VerifyPChainBalanceOnAllNodes(e.allClients, stakerPChainAddress, 0); err != nil {
+		return stacktrace.Propagate(err, "Nodes did not converge on the staker's P Chain balance.")
+	}
+	logrus.Infof("Verified that every node in the network agrees on the final X and P Chain balances.")
+
 	return nil
 }
@@ -39,7 +39,15 @@ func (test StakingNetworkRPCWorkflowTest) Run(network networks.Network, context
 		context.Fatal(stacktrace.Propagate(err, "Could not get delegator client"))
 	}
 
-	executor := NewRPCWorkflowTestExecutor(stakerClient, delegatorClient, networkAcceptanceTimeout)
+	allServiceIDs := castedNetwork.GetAllBootServiceIDs()
+	allServiceIDs[regularNodeServiceID] = true
+	allServiceIDs[delegatorNodeServiceID] = true
+	allClients, err := castedNetwork.GetAvalancheClients(allServiceIDs)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get clients for all nodes"))
+	}
+
+	executor := NewRPCWorkflowTestExecutor(stakerClient, delegatorClient, allClients, networkAcceptanceTimeout)
 
 	logrus.Infof("Set up RPCWorkFlowTest. Executing...")
 	if err := executor.ExecuteTest(); err != nil {
@@ -68,6 +76,7 @@ func (test StakingNetworkRPCWorkflowTest) GetNetworkLoader() (networks.NetworkLo
 	}
 	// Return an Avalanche Test Network with this service:configuration mapping.
 	return avalancheNetwork.NewTestAvalancheNetworkLoader(
+		avalancheNetwork.LocalNetworkID,
 		true,
 		test.ImageName,
 		avalancheService.DEBUG,
@@ -75,6 +84,7 @@ func (test StakingNetworkRPCWorkflowTest) GetNetworkLoader() (networks.NetworkLo
 		2,
 		0,
 		2*time.Second,
+		false,
 		serviceConfigs,
 		desiredServices,
 	)
@@ -1,6 +1,7 @@
 package verifier
 
 import (
+	"github.com/ava-labs/avalanche-testing/avalanche/logging"
 	"github.com/ava-labs/avalanche-testing/avalanche/services"
 	"github.com/kurtosis-tech/kurtosis-go/lib/networks"
 	"github.com/palantir/stacktrace"
@@ -48,7 +49,7 @@ func (verifier NetworkStateVerifier) VerifyNetworkFullyConnected(
 			}
 		}
 
-		logrus.Infof("Expecting serviceID %v to have the following peer node IDs, %v", serviceID, acceptableNodeIDs)
+		logging.ForNode(string(serviceID), allNodeIDs[serviceID]).Infof("Expecting serviceID %v to have the following peer node IDs, %v", serviceID, acceptableNodeIDs)
 		if err := verifier.VerifyExpectedPeers(serviceID, allAvalalancheClients[serviceID], acceptableNodeIDs, len(acceptableNodeIDs), false); err != nil {
 			return stacktrace.Propagate(err, "An error occurred verifying the expected peers list")
 		}
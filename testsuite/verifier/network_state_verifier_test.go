@@ -0,0 +1,32 @@
+package verifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-testing/avalanche/services/fakenode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyExpectedPeers(t *testing.T) {
+	node := fakenode.New()
+	defer node.Close()
+	client := node.Client(5 * time.Second)
+	verifier := NetworkStateVerifier{}
+
+	node.SetPeers("NodeID-A", "NodeID-B")
+	acceptable := map[string]bool{"NodeID-A": true, "NodeID-B": true, "NodeID-C": true}
+
+	assert.NoError(t, verifier.VerifyExpectedPeers("node", client, acceptable, 2, false))
+	assert.Error(t, verifier.VerifyExpectedPeers("node", client, acceptable, 3, false), "Expected an error for too few peers")
+	assert.NoError(t, verifier.VerifyExpectedPeers("node", client, acceptable, 1, true))
+
+	node.SetPeers("NodeID-A", "NodeID-D")
+	err := verifier.VerifyExpectedPeers("node", client, acceptable, 2, false)
+	if assert.Error(t, err, "Expected an error for an unrecognized peer") {
+		assert.Contains(t, err.Error(), "NodeID-D")
+	}
+
+	node.Fail("info.peers", "network shutting down")
+	assert.Error(t, verifier.VerifyExpectedPeers("node", client, acceptable, 2, false), "Expected an error when peers can't be queried")
+}
@@ -0,0 +1,111 @@
+package addressing
+
+import (
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/palantir/stacktrace"
+)
+
+// Chain aliases used as the prefix of bech32 addresses
+const (
+	XChain = "X"
+	PChain = "P"
+	CChain = "C"
+)
+
+// Formatter converts addresses to and from the bech32 format of a single network
+type Formatter struct {
+	networkID uint32
+	hrp       string
+}
+
+// NewFormatter returns a Formatter for addresses on network [networkID]
+func NewFormatter(networkID uint32) Formatter {
+	return Formatter{
+		networkID: networkID,
+		hrp:       constants.GetHRP(networkID),
+	}
+}
+
+// NetworkID returns the ID of the network this formatter formats addresses for
+func (f Formatter) NetworkID() uint32 {
+	return f.networkID
+}
+
+// HRP returns the human readable part of bech32 addresses on this formatter's network
+func (f Formatter) HRP() string {
+	return f.hrp
+}
+
+// FormatBech32 returns the bech32 address of [address] on the chain with alias [chainAlias]
+func (f Formatter) FormatBech32(chainAlias string, address ids.ShortID) (string, error) {
+	formatted, err := formatting.FormatAddress(chainAlias, f.hrp, address.Bytes())
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to format %s Chain address %s", chainAlias, address)
+	}
+	return formatted, nil
+}
+
+// XChainAddress returns the bech32 X Chain address of [address]
+func (f Formatter) XChainAddress(address ids.ShortID) (string, error) {
+	return f.FormatBech32(XChain, address)
+}
+
+// PChainAddress returns the bech32 P Chain address of [address]
+func (f Formatter) PChainAddress(address ids.ShortID) (string, error) {
+	return f.FormatBech32(PChain, address)
+}
+
+// CChainAddress returns the bech32 C Chain address of [address], which is used to reference atomic UTXOs held by
+// [address] on the C Chain
+func (f Formatter) CChainAddress(address ids.ShortID) (string, error) {
+	return f.FormatBech32(CChain, address)
+}
+
+// ParseBech32 parses [address] as a bech32 address on the chain with alias [chainAlias]. An error is returned if
+// [address] belongs to a different chain or has a different HRP than this formatter's network.
+func (f Formatter) ParseBech32(chainAlias string, address string) (ids.ShortID, error) {
+	addressChain, hrp, addressBytes, err := formatting.ParseAddress(address)
+	if err != nil {
+		return ids.ShortID{}, stacktrace.Propagate(err, "Failed to parse address %s", address)
+	}
+	if addressChain != chainAlias {
+		return ids.ShortID{}, stacktrace.NewError("Address %s is on chain %s, expected chain %s", address, addressChain, chainAlias)
+	}
+	if hrp != f.hrp {
+		return ids.ShortID{}, stacktrace.NewError("Address %s has HRP %s, expected HRP %s for network %d", address, hrp, f.hrp, f.networkID)
+	}
+	shortID, err := ids.ToShortID(addressBytes)
+	if err != nil {
+		return ids.ShortID{}, stacktrace.Propagate(err, "Address %s does not hold a 20 byte address", address)
+	}
+	return shortID, nil
+}
+
+// ConvertChain returns the address on the chain with alias [toChainAlias] that holds the same key as [address], which
+// must be a bech32 address on the chain with alias [fromChainAlias] on this formatter's network
+func (f Formatter) ConvertChain(address string, fromChainAlias string, toChainAlias string) (string, error) {
+	shortID, err := f.ParseBech32(fromChainAlias, address)
+	if err != nil {
+		return "", err
+	}
+	return f.FormatBech32(toChainAlias, shortID)
+}
+
+// FormatHex returns [address] as a 0x prefixed, checksummed hex address, as used by the EVM.
+// Note that a key's EVM address is derived differently from its X and P Chain address, so the two are not interchangeable.
+func FormatHex(address ids.ShortID) string {
+	return common.BytesToAddress(address.Bytes()).Hex()
+}
+
+// ParseHex parses [address] as a 0x prefixed hex address, as used by the EVM
+func ParseHex(address string) (ids.ShortID, error) {
+	if !strings.HasPrefix(address, "0x") || !common.IsHexAddress(address) {
+		return ids.ShortID{}, stacktrace.NewError("Address %s is not a 0x prefixed hex address", address)
+	}
+	return ids.ToShortID(common.HexToAddress(address).Bytes())
+}
@@ -0,0 +1,54 @@
+package addressing
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	genesisShortID       = "6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV"
+	genesisXChainAddress = "X-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u"
+	genesisCChainAddress = "C-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u"
+)
+
+func TestBech32RoundTrip(t *testing.T) {
+	formatter := NewFormatter(constants.LocalID)
+	shortID, err := ids.ShortFromString(genesisShortID)
+	assert.NoError(t, err)
+
+	xChainAddress, err := formatter.XChainAddress(shortID)
+	assert.NoError(t, err)
+	assert.Equal(t, genesisXChainAddress, xChainAddress)
+
+	parsed, err := formatter.ParseBech32(XChain, xChainAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, shortID, parsed)
+
+	cChainAddress, err := formatter.ConvertChain(xChainAddress, XChain, CChain)
+	assert.NoError(t, err)
+	assert.Equal(t, genesisCChainAddress, cChainAddress)
+}
+
+func TestParseBech32Mismatches(t *testing.T) {
+	formatter := NewFormatter(constants.LocalID)
+	_, err := formatter.ParseBech32(PChain, genesisXChainAddress)
+	assert.Error(t, err, "Expected an error parsing an X Chain address as a P Chain address")
+
+	_, err = NewFormatter(constants.MainnetID).ParseBech32(XChain, genesisXChainAddress)
+	assert.Error(t, err, "Expected an error parsing a local address with the mainnet HRP")
+}
+
+func TestHexRoundTrip(t *testing.T) {
+	shortID, err := ids.ShortFromString(genesisShortID)
+	assert.NoError(t, err)
+
+	parsed, err := ParseHex(FormatHex(shortID))
+	assert.NoError(t, err)
+	assert.Equal(t, shortID, parsed)
+
+	_, err = ParseHex(shortID.Hex())
+	assert.Error(t, err, "Expected an error parsing a hex address without the 0x prefix")
+}
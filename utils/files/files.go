@@ -0,0 +1,49 @@
+package files
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/palantir/stacktrace"
+)
+
+// CopyDir copies the directory at [srcDirpath], and everything in it, to [dstDirpath], keeping the permissions of
+// each file and directory
+func CopyDir(srcDirpath string, dstDirpath string) error {
+	return filepath.Walk(srcDirpath, func(srcPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDirpath, srcPath)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dstDirpath, relPath)
+		if info.IsDir() {
+			return os.MkdirAll(dstPath, info.Mode().Perm())
+		}
+		if !info.Mode().IsRegular() {
+			return stacktrace.NewError("Can't copy %v, which isn't a regular file", srcPath)
+		}
+		return CopyFile(srcPath, dstPath, info.Mode().Perm())
+	})
+}
+
+// CopyFile copies the file at [srcFilepath] to [dstFilepath], which is created with permissions [perm]
+func CopyFile(srcFilepath string, dstFilepath string, perm os.FileMode) error {
+	src, err := os.Open(srcFilepath)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to open %v", srcFilepath)
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(dstFilepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to create %v", dstFilepath)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return stacktrace.Propagate(err, "Failed to copy %v to %v", srcFilepath, dstFilepath)
+	}
+	return dst.Close()
+}
@@ -0,0 +1,27 @@
+package files
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyDir(t *testing.T) {
+	srcDirpath := filepath.Join(t.TempDir(), "src")
+	assert.NoError(t, os.MkdirAll(filepath.Join(srcDirpath, "nested"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(srcDirpath, "nested", "key"), []byte("secret"), 0600))
+
+	dstDirpath := filepath.Join(t.TempDir(), "dst")
+	assert.NoError(t, CopyDir(srcDirpath, dstDirpath))
+	bytes, err := ioutil.ReadFile(filepath.Join(dstDirpath, "nested", "key"))
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", string(bytes))
+	info, err := os.Stat(filepath.Join(dstDirpath, "nested", "key"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Expected the file's permissions to be kept")
+
+	assert.Error(t, CopyDir(filepath.Join(srcDirpath, "missing"), dstDirpath), "Expected an error for a missing directory")
+}